@@ -17,9 +17,14 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"strings"
 
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
@@ -30,13 +35,367 @@ import (
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
 
 // VirtualClusterSpec defines the desired state of VirtualCluster.
+// +kubebuilder:validation:XValidation:rule="!has(self.ttl) || !has(self.expiresAt)",message="ttl and expiresAt are mutually exclusive"
 type VirtualClusterSpec struct {
 	Chart HelmChart `json:"chart,omitempty"`
+
+	// ValuesFrom references ConfigMap or Secret keys holding Helm values.
+	// They are merged in order before Values, so later entries and Values
+	// take precedence.
+	// +optional
+	ValuesFrom []ValuesReference `json:"valuesFrom,omitempty"`
+
+	// Validation configures how the values are validated against the schema
+	// of the chart.
+	// +optional
+	Validation *ValidationSpec `json:"validation,omitempty"`
+
+	// Drift configures how drift of the Helm release from the desired state
+	// is handled.
+	// +optional
+	Drift *DriftSpec `json:"drift,omitempty"`
+
+	// Kubeconfig configures the kubeconfig exported for the VirtualCluster.
+	// +optional
+	Kubeconfig *KubeconfigSpec `json:"kubeconfig,omitempty"`
+
+	// UpgradePolicy configures how the Helm release is installed and
+	// upgraded.
+	// +optional
+	UpgradePolicy *UpgradePolicy `json:"upgradePolicy,omitempty"`
+
+	// Rollback rolls the Helm release back to a previous revision. The
+	// release stays at that revision and isn't upgraded until Rollback is
+	// removed.
+	// +optional
+	Rollback *RollbackSpec `json:"rollback,omitempty"`
+
+	// DeletionPolicy determines what happens to the Helm release and its
+	// data when the VirtualCluster is deleted. Defaults to Delete.
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// TargetNamespace deploys the Helm release into a namespace created for
+	// the VirtualCluster instead of the namespace of the VirtualCluster. It
+	// can't be changed once the VirtualCluster is created.
+	// +optional
+	TargetNamespace *TargetNamespaceSpec `json:"targetNamespace,omitempty"`
+
+	// Suspend stops the reconciliation of the VirtualCluster, e.g. during a
+	// manual intervention. No Helm operations run until it is cleared, and a
+	// suspended VirtualCluster is only finalized once it is resumed.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+
+	// Hibernation scales the VirtualCluster to zero, manually or on a
+	// schedule, and wakes it up again.
+	// +optional
+	Hibernation *HibernationSpec `json:"hibernation,omitempty"`
+
+	// ClassRef references the VirtualClusterClass providing the chart, base
+	// values and policies of the VirtualCluster. Its own settings take
+	// precedence, and its values are deep merged on top of the base values.
+	// +optional
+	ClassRef *ClassReference `json:"classRef,omitempty"`
+
+	// TTL deletes the VirtualCluster once it has existed for the duration,
+	// e.g. "24h". Mutually exclusive with ExpiresAt.
+	// +optional
+	TTL *metav1.Duration `json:"ttl,omitempty"`
+
+	// ExpiresAt deletes the VirtualCluster at the given time. Mutually
+	// exclusive with TTL.
+	// +optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+
+	// IdleTimeout deletes the VirtualCluster once its API server served no
+	// requests for the duration. Requires the operator to probe the API
+	// servers of virtual clusters.
+	// +optional
+	IdleTimeout *metav1.Duration `json:"idleTimeout,omitempty"`
+
+	// Isolation creates a ResourceQuota, a LimitRange and a default-deny
+	// NetworkPolicy in the namespace the Helm release is deployed to and
+	// keeps them in place. Each of them defaults to a preset.
+	// +optional
+	Isolation *IsolationSpec `json:"isolation,omitempty"`
+
 	// +kubebuilder:pruning:PreserveUnknownFields
 	// +kubebuilder:validation:Schemaless
 	Values *apiextensionsv1.JSON `json:"values,required"`
 }
 
+// ClassReference references a VirtualClusterClass.
+type ClassReference struct {
+	// Name of the VirtualClusterClass
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// UpgradePolicy configures the Helm operations of a release.
+type UpgradePolicy struct {
+	// Atomic waits for the release to become ready and rolls a failed upgrade
+	// back, or uninstalls a failed install, using the atomic mode of Helm.
+	// +optional
+	Atomic bool `json:"atomic,omitempty"`
+
+	// RollbackOnFailure rolls a failed upgrade back to the last deployed
+	// revision.
+	// +optional
+	RollbackOnFailure bool `json:"rollbackOnFailure,omitempty"`
+
+	// MaxHistory is the number of revisions kept for the release and listed
+	// in status.history. Defaults to 10.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxHistory *int32 `json:"maxHistory,omitempty"`
+
+	// Timeout of a Helm operation. Defaults to 5m.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// Wait waits until the resources of the release are ready before an
+	// operation succeeds.
+	// +optional
+	Wait bool `json:"wait,omitempty"`
+}
+
+// DeletionPolicy determines what happens to the resources of a VirtualCluster
+// when it is deleted.
+// +kubebuilder:validation:Enum=Delete;Retain;Orphan
+type DeletionPolicy string
+
+// These are the valid deletion policies.
+const (
+	// DeletionPolicyDelete uninstalls the Helm release and deletes its
+	// PersistentVolumeClaims and the namespace created for it.
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+
+	// DeletionPolicyRetain uninstalls the Helm release and keeps its
+	// PersistentVolumeClaims, so the data can be recovered.
+	DeletionPolicyRetain DeletionPolicy = "Retain"
+
+	// DeletionPolicyOrphan leaves the Helm release and all its resources in
+	// place.
+	DeletionPolicyOrphan DeletionPolicy = "Orphan"
+)
+
+// TargetNamespaceSpec configures the namespace the Helm release is deployed to.
+type TargetNamespaceSpec struct {
+	// Name of the namespace. If empty, a name is generated from the namespace
+	// and name of the VirtualCluster.
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Labels added to the namespace
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations added to the namespace
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// PodSecurity is the Pod Security Admission level enforced in the
+	// namespace. If empty, the cluster default applies.
+	// +optional
+	PodSecurity PodSecurityLevel `json:"podSecurity,omitempty"`
+}
+
+// IsolationSpec configures the resources isolating the workloads of the
+// VirtualCluster in the host namespace.
+type IsolationSpec struct {
+	// ResourceQuota limits the resources the namespace may consume
+	// +optional
+	ResourceQuota *IsolationResourceQuota `json:"resourceQuota,omitempty"`
+
+	// LimitRange sets the default resources of containers that don't
+	// request any, so they count against the quota
+	// +optional
+	LimitRange *IsolationLimitRange `json:"limitRange,omitempty"`
+
+	// NetworkPolicy denies ingress from other namespaces except to the API
+	// server of the VirtualCluster
+	// +optional
+	NetworkPolicy *IsolationNetworkPolicy `json:"networkPolicy,omitempty"`
+}
+
+// IsolationResourceQuota configures the ResourceQuota of the namespace.
+type IsolationResourceQuota struct {
+	// Enabled creates the ResourceQuota. Defaults to true.
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// Hard are the limits of the quota. They replace the limits of the
+	// preset when set.
+	// +optional
+	Hard corev1.ResourceList `json:"hard,omitempty"`
+}
+
+// IsolationLimitRange configures the LimitRange of the namespace.
+type IsolationLimitRange struct {
+	// Enabled creates the LimitRange. Defaults to true.
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// Default are the limits of containers that don't set any. They replace
+	// the limits of the preset when set.
+	// +optional
+	Default corev1.ResourceList `json:"default,omitempty"`
+
+	// DefaultRequest are the requests of containers that don't set any.
+	// They replace the requests of the preset when set.
+	// +optional
+	DefaultRequest corev1.ResourceList `json:"defaultRequest,omitempty"`
+}
+
+// IsolationNetworkPolicy configures the NetworkPolicy of the namespace.
+type IsolationNetworkPolicy struct {
+	// Enabled creates the default-deny NetworkPolicy, and a second one
+	// opening the API server port of the control plane pods only. Defaults
+	// to true.
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// AllowFrom lists further peers allowed to reach the pods of the
+	// namespace, e.g. the namespace of an ingress controller
+	// +optional
+	AllowFrom []networkingv1.NetworkPolicyPeer `json:"allowFrom,omitempty"`
+}
+
+// PodSecurityLevel is a level of the Pod Security Standards.
+// +kubebuilder:validation:Enum=privileged;baseline;restricted
+type PodSecurityLevel string
+
+// These are the valid Pod Security levels.
+const (
+	PodSecurityLevelPrivileged PodSecurityLevel = "privileged"
+	PodSecurityLevelBaseline   PodSecurityLevel = "baseline"
+	PodSecurityLevelRestricted PodSecurityLevel = "restricted"
+)
+
+// HibernationSpec configures when the VirtualCluster hibernates. While it
+// hibernates, its control plane is scaled to zero and the workloads synced to
+// the host cluster are removed. They are synced again once it wakes up.
+// +kubebuilder:validation:XValidation:rule="has(self.sleepSchedule) == has(self.wakeSchedule)",message="sleepSchedule and wakeSchedule must be set together"
+type HibernationSpec struct {
+	// Hibernate puts the VirtualCluster to sleep regardless of the schedules
+	// +optional
+	Hibernate bool `json:"hibernate,omitempty"`
+
+	// SleepSchedule is the cron schedule the VirtualCluster hibernates at,
+	// e.g. "0 20 * * 1-5"
+	// +optional
+	SleepSchedule string `json:"sleepSchedule,omitempty"`
+
+	// WakeSchedule is the cron schedule the VirtualCluster wakes up at,
+	// e.g. "0 7 * * 1-5"
+	// +optional
+	WakeSchedule string `json:"wakeSchedule,omitempty"`
+
+	// TimeZone the schedules are evaluated in, as a name of the IANA time
+	// zone database. Defaults to UTC.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// RollbackSpec requests a rollback of the Helm release.
+type RollbackSpec struct {
+	// Revision of the Helm release to roll back to
+	// +kubebuilder:validation:Minimum=1
+	Revision int32 `json:"revision"`
+}
+
+// KubeconfigSpec configures the exported kubeconfig.
+type KubeconfigSpec struct {
+	// Server overrides the server URL of the kubeconfig.
+	// +optional
+	Server string `json:"server,omitempty"`
+
+	// Endpoint selects the address the server URL is rewritten to when Server
+	// is not set. Internal uses the DNS name of the Service, External the load
+	// balancer address of the Service or the ingress host. Defaults to
+	// Internal.
+	// +optional
+	Endpoint KubeconfigEndpoint `json:"endpoint,omitempty"`
+
+	// ContextName renames the current context of the kubeconfig.
+	// +optional
+	ContextName string `json:"contextName,omitempty"`
+
+	// CopyTo lists further Secrets the kubeconfig is copied to.
+	// +optional
+	CopyTo []KubeconfigTarget `json:"copyTo,omitempty"`
+}
+
+// KubeconfigEndpoint is the address the server URL of the kubeconfig is
+// rewritten to.
+// +kubebuilder:validation:Enum=Internal;External
+type KubeconfigEndpoint string
+
+// These are the valid kubeconfig endpoints.
+const (
+	// KubeconfigEndpointInternal uses the DNS name of the Service, reachable
+	// from inside the host cluster.
+	KubeconfigEndpointInternal KubeconfigEndpoint = "Internal"
+
+	// KubeconfigEndpointExternal uses the load balancer address of the
+	// Service or the ingress host.
+	KubeconfigEndpointExternal KubeconfigEndpoint = "External"
+)
+
+// KubeconfigTarget is a Secret the kubeconfig is copied to.
+type KubeconfigTarget struct {
+	// Namespace of the Secret. Defaults to the namespace of the
+	// VirtualCluster. Other namespaces must opt in with the annotation
+	// core.openvc.dev/kubeconfig-copies-from listing the namespace of the
+	// VirtualCluster, or "*".
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Name of the Secret
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Key holding the kubeconfig. Defaults to "config".
+	// +optional
+	Key string `json:"key,omitempty"`
+}
+
+// DriftSpec configures the handling of drift of the Helm release.
+type DriftSpec struct {
+	// SelfHeal re-applies the desired state when the release drifted from it.
+	// Defaults to the default of the operator (see the --self-heal flag).
+	// +optional
+	SelfHeal *bool `json:"selfHeal,omitempty"`
+}
+
+// ValidationSpec configures the validation of the values.
+type ValidationSpec struct {
+	// Mode is the validation mode. Defaults to the default of the operator
+	// (see the --default-validation-mode flag), which is Warn unless changed.
+	// +optional
+	Mode ValidationMode `json:"mode,omitempty"`
+}
+
+// ValidationMode controls what happens when the values fail schema validation.
+// +kubebuilder:validation:Enum=Disabled;Warn;Enforce
+type ValidationMode string
+
+// These are the valid validation modes.
+const (
+	// ValidationModeDisabled skips schema validation.
+	ValidationModeDisabled ValidationMode = "Disabled"
+
+	// ValidationModeWarn reports validation errors in the status but still
+	// deploys the VirtualCluster.
+	ValidationModeWarn ValidationMode = "Warn"
+
+	// ValidationModeEnforce does not deploy values failing validation.
+	ValidationModeEnforce ValidationMode = "Enforce"
+)
+
 // GetValues unmarshals the raw values to a map[string]interface{} and returns
 // the result.
 func (in VirtualCluster) GetValues() (map[string]interface{}, error) {
@@ -57,10 +416,126 @@ func (in VirtualCluster) GetValues() (map[string]interface{}, error) {
 	return values, nil
 }
 
+// ReleaseNamespace returns the namespace the Helm release is deployed to: the
+// target namespace if one is configured, or the namespace of the
+// VirtualCluster. Generated names that exceed the length limit of namespaces
+// are shortened with a hash of the full name.
+func (in *VirtualCluster) ReleaseNamespace() string {
+	if in.Spec.TargetNamespace == nil {
+		return in.Namespace
+	}
+	if in.Spec.TargetNamespace.Name != "" {
+		return in.Spec.TargetNamespace.Name
+	}
+
+	name := fmt.Sprintf("vc-%s-%s", in.Namespace, in.Name)
+	if len(name) <= 63 {
+		return name
+	}
+	sum := sha256.Sum256([]byte(name))
+	return strings.TrimRight(name[:54], "-") + "-" + hex.EncodeToString(sum[:4])
+}
+
+// ValuesReference references a key of a ConfigMap or Secret holding Helm values.
+type ValuesReference struct {
+	// Kind of the object holding the values
+	// +kubebuilder:validation:Enum=ConfigMap;Secret
+	Kind string `json:"kind"`
+
+	// Name of the object in the namespace of the VirtualCluster
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// ValuesKey is the key holding the values. Defaults to "values.yaml".
+	// +optional
+	ValuesKey string `json:"valuesKey,omitempty"`
+
+	// TargetPath is the dot notated path (e.g. "external.database.password")
+	// the value of the key is set at. When set, the value is used verbatim as
+	// a string instead of being parsed as YAML.
+	// +optional
+	TargetPath string `json:"targetPath,omitempty"`
+
+	// Optional marks the reference as optional. A missing object or key is
+	// then ignored instead of failing the reconciliation.
+	// +optional
+	Optional bool `json:"optional,omitempty"`
+}
+
+// DefaultChartVersion is the vcluster chart version deployed when
+// spec.chart.version is empty.
+const DefaultChartVersion = "v0.24.1"
+
 type HelmChart struct {
-	// Version is the version of the helm chart
-	// +default:value="v0.24.1"
+	// Version is the version of the helm chart, or a semantic version
+	// constraint such as ~0.24.0 that Helm resolves on every install and
+	// upgrade. Defaults to the version the operator ships with, which the
+	// defaulting webhook writes to the object.
+	// +optional
 	Version string `json:"version,omitempty"`
+
+	// Source overrides where the chart is pulled from. Defaults to the vcluster
+	// chart in the loft.sh Helm repository.
+	// +optional
+	Source *ChartSource `json:"source,omitempty"`
+}
+
+// ChartSource describes the repository or registry serving the vcluster chart.
+type ChartSource struct {
+	// RepoURL is the URL of a Helm repository (http:// or https://) or of an
+	// OCI registry path (oci://) holding the chart
+	// +optional
+	// +kubebuilder:validation:Pattern=`^(https?|oci)://.+`
+	RepoURL string `json:"repoURL,omitempty"`
+
+	// Name overrides the name of the chart. For OCI registries the name is
+	// appended to the RepoURL to form the chart reference.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// CredentialsSecretRef references a Secret in the namespace of the
+	// VirtualCluster holding credentials for the repository. The keys
+	// "username" and "password" are used for basic auth, "ca.crt" to verify
+	// the repository and "tls.crt"/"tls.key" as client certificate.
+	// +optional
+	CredentialsSecretRef *corev1.LocalObjectReference `json:"credentialsSecretRef,omitempty"`
+
+	// InsecureSkipTLSVerify skips verification of the repository certificate
+	// +optional
+	InsecureSkipTLSVerify bool `json:"insecureSkipTLSVerify,omitempty"`
+
+	// PlainHTTP talks to an OCI registry over plain HTTP
+	// +optional
+	PlainHTTP bool `json:"plainHTTP,omitempty"`
+
+	// Offline loads the chart and its values schema from an in-cluster object
+	// or from the operator image instead of the network. It takes precedence
+	// over RepoURL.
+	// +optional
+	Offline *OfflineChartSource `json:"offline,omitempty"`
+}
+
+// OfflineChartSource describes a chart archive available without network access.
+// The archive is read from the key "chart.tgz" and the values schema from the
+// optional key "values.schema.json", falling back to the schema packaged in
+// the chart.
+// +kubebuilder:validation:XValidation:rule="(has(self.configMapRef) ? 1 : 0) + (has(self.secretRef) ? 1 : 0) + (has(self.file) ? 1 : 0) == 1",message="exactly one of configMapRef, secretRef or file must be set"
+type OfflineChartSource struct {
+	// ConfigMapRef references a ConfigMap in the namespace of the
+	// VirtualCluster holding the chart archive as binaryData
+	// +optional
+	ConfigMapRef *corev1.LocalObjectReference `json:"configMapRef,omitempty"`
+
+	// SecretRef references a Secret in the namespace of the VirtualCluster
+	// holding the chart archive
+	// +optional
+	SecretRef *corev1.LocalObjectReference `json:"secretRef,omitempty"`
+
+	// File is the name of a chart archive in the offline chart directory of
+	// the operator image (see the --offline-chart-dir flag)
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[^/]+\.tgz$`
+	File string `json:"file,omitempty"`
 }
 
 // VirtualClusterStatus defines the observed state of VirtualCluster.
@@ -69,6 +544,10 @@ type VirtualClusterStatus struct {
 	// +optional
 	Phase VirtualClusterPhase `json:"phase,omitempty"`
 
+	// ObservedGeneration is the generation of the spec that was last applied
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
 	// Conditions represent the latest available observations of an object's state
 	// +optional
 	// +patchMergeKey=type
@@ -86,8 +565,144 @@ type VirtualClusterStatus struct {
 	// HelmRelease is the name of the helm release used to deploy the VirtualCluster
 	// +optional
 	HelmRelease string `json:"helmRelease,omitempty"`
+
+	// TargetNamespace is the namespace the helm release is deployed to, if it
+	// differs from the namespace of the VirtualCluster
+	// +optional
+	TargetNamespace string `json:"targetNamespace,omitempty"`
+
+	// ChartSource is the kind of source the helm chart was loaded from
+	// +optional
+	ChartSource ChartSourceType `json:"chartSource,omitempty"`
+
+	// DeletionPolicy is the effective deletion policy of the VirtualCluster,
+	// recorded while its class is resolvable so it is still honored when the
+	// class is gone by the time the VirtualCluster is deleted
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// AppliedHash is the hash of the chart version and effective values last
+	// applied to the Helm release. The release is only upgraded when it
+	// changes or the release drifted.
+	// +optional
+	AppliedHash string `json:"appliedHash,omitempty"`
+
+	// FailedHash is the hash of the chart version and effective values of an
+	// upgrade that failed and was rolled back, through
+	// upgradePolicy.rollbackOnFailure or by Helm for atomic upgrades. It
+	// isn't retried until the spec or the effective values change.
+	// +optional
+	FailedHash string `json:"failedHash,omitempty"`
+
+	// KubeconfigSecretRef references the Secret holding the kubeconfig of the
+	// VirtualCluster
+	// +optional
+	KubeconfigSecretRef *SecretKeyReference `json:"kubeconfigSecretRef,omitempty"`
+
+	// History lists the latest revisions of the Helm release, newest first
+	// +optional
+	History []ReleaseRevision `json:"history,omitempty"`
+
+	// RollbackRevision is the revision the Helm release was rolled back to
+	// as requested by spec.rollback
+	// +optional
+	RollbackRevision int32 `json:"rollbackRevision,omitempty"`
+
+	// ExpiresAt is the time the VirtualCluster is deleted at, as determined by
+	// spec.ttl, spec.expiresAt and spec.idleTimeout
+	// +optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+
+	// LastActivityTime is the last time the API server of the VirtualCluster
+	// was seen serving requests
+	// +optional
+	LastActivityTime *metav1.Time `json:"lastActivityTime,omitempty"`
+
+	// Isolation reports the isolation resources of the host namespace
+	// +optional
+	Isolation *IsolationStatus `json:"isolation,omitempty"`
+}
+
+// IsolationStatus reports the isolation resources created for spec.isolation.
+type IsolationStatus struct {
+	// Namespace the resources are created in
+	Namespace string `json:"namespace"`
+
+	// ResourceQuota is the name of the ResourceQuota
+	// +optional
+	ResourceQuota string `json:"resourceQuota,omitempty"`
+
+	// LimitRange is the name of the LimitRange
+	// +optional
+	LimitRange string `json:"limitRange,omitempty"`
+
+	// NetworkPolicy is the name of the NetworkPolicy
+	// +optional
+	NetworkPolicy string `json:"networkPolicy,omitempty"`
+
+	// Hard are the limits enforced by the ResourceQuota
+	// +optional
+	Hard corev1.ResourceList `json:"hard,omitempty"`
+
+	// Used is the usage of the ResourceQuota
+	// +optional
+	Used corev1.ResourceList `json:"used,omitempty"`
+}
+
+// ReleaseRevision is a revision of the Helm release.
+type ReleaseRevision struct {
+	// Revision number
+	Revision int32 `json:"revision"`
+
+	// ChartVersion is the version of the chart deployed by the revision
+	ChartVersion string `json:"chartVersion"`
+
+	// ValuesHash is the hash of the chart version and values of the revision
+	ValuesHash string `json:"valuesHash"`
+
+	// Outcome is the status of the revision reported by Helm, e.g. deployed,
+	// superseded or failed
+	Outcome string `json:"outcome"`
+
+	// Description of the revision reported by Helm
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// Timestamp is the time the revision was deployed
+	Timestamp metav1.Time `json:"timestamp"`
 }
 
+// SecretKeyReference references a key of a Secret in the namespace of the
+// VirtualCluster.
+type SecretKeyReference struct {
+	// Name of the Secret
+	Name string `json:"name"`
+
+	// Key of the Secret
+	Key string `json:"key"`
+}
+
+// ChartSourceType is the kind of source a chart was loaded from.
+type ChartSourceType string
+
+// These are the valid chart source types.
+const (
+	// ChartSourceRepository means the chart was pulled from a Helm repository.
+	ChartSourceRepository ChartSourceType = "Repository"
+
+	// ChartSourceOCI means the chart was pulled from an OCI registry.
+	ChartSourceOCI ChartSourceType = "OCI"
+
+	// ChartSourceConfigMap means the chart was read from a ConfigMap.
+	ChartSourceConfigMap ChartSourceType = "ConfigMap"
+
+	// ChartSourceSecret means the chart was read from a Secret.
+	ChartSourceSecret ChartSourceType = "Secret"
+
+	// ChartSourceFile means the chart was read from a file in the operator image.
+	ChartSourceFile ChartSourceType = "File"
+)
+
 // VirtualClusterPhase is a label for the phase of a VirtualCluster at the current time.
 type VirtualClusterPhase string
 
@@ -107,11 +722,16 @@ const (
 
 	// VirtualClusterDeleting means the VirtualCluster is being deleted.
 	VirtualClusterDeleting VirtualClusterPhase = "Deleting"
+
+	// VirtualClusterHibernated means the VirtualCluster is scaled to zero.
+	VirtualClusterHibernated VirtualClusterPhase = "Hibernated"
 )
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.phase",description="Status of the VirtualCluster"
+// +kubebuilder:printcolumn:name="Suspended",type="boolean",JSONPath=".spec.suspend",priority=1
+// +kubebuilder:printcolumn:name="Expires",type="string",JSONPath=".status.expiresAt",description="Time the VirtualCluster is deleted at"
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:resource:shortName=vc
 // +kubebuilder:storageversion
@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -131,6 +132,7 @@ func TestVirtualClusterPhases(t *testing.T) {
 	assert.Equal(t, VirtualClusterPhase("Running"), VirtualClusterRunning)
 	assert.Equal(t, VirtualClusterPhase("Failed"), VirtualClusterFailed)
 	assert.Equal(t, VirtualClusterPhase("Deleting"), VirtualClusterDeleting)
+	assert.Equal(t, VirtualClusterPhase("Hibernated"), VirtualClusterHibernated)
 }
 
 func TestVirtualCluster_DefaultValues(t *testing.T) {
@@ -194,3 +196,48 @@ func TestVirtualClusterStatusConditions(t *testing.T) {
 	// Test condition count
 	assert.Equal(t, 2, len(vc.Status.Conditions))
 }
+
+func TestVirtualCluster_ReleaseNamespace(t *testing.T) {
+	tests := []struct {
+		name            string
+		vcName          string
+		targetNamespace *TargetNamespaceSpec
+		want            string
+	}{
+		{
+			name:   "without a target namespace",
+			vcName: "test-vcluster",
+			want:   "tenant-a",
+		},
+		{
+			name:            "with a named target namespace",
+			vcName:          "test-vcluster",
+			targetNamespace: &TargetNamespaceSpec{Name: "workloads"},
+			want:            "workloads",
+		},
+		{
+			name:            "with a generated target namespace",
+			vcName:          "test-vcluster",
+			targetNamespace: &TargetNamespaceSpec{},
+			want:            "vc-tenant-a-test-vcluster",
+		},
+		{
+			name:            "with a generated name exceeding the length limit",
+			vcName:          strings.Repeat("long-name-", 6),
+			targetNamespace: &TargetNamespaceSpec{},
+			want:            "vc-tenant-a-long-name-long-name-long-name-long-name-lo-c145f555",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vc := VirtualCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: tt.vcName, Namespace: "tenant-a"},
+				Spec:       VirtualClusterSpec{TargetNamespace: tt.targetNamespace},
+			}
+			got := vc.ReleaseNamespace()
+			assert.Equal(t, tt.want, got)
+			assert.LessOrEqual(t, len(got), 63)
+		})
+	}
+}
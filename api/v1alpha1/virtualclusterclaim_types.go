@@ -0,0 +1,110 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// VirtualClusterClaimSpec defines the desired state of VirtualClusterClaim.
+type VirtualClusterClaimSpec struct {
+	// PoolRef references the VirtualClusterPool in the namespace of the claim
+	// to bind a VirtualCluster from.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="poolRef is immutable"
+	PoolRef PoolReference `json:"poolRef"`
+}
+
+// PoolReference references a VirtualClusterPool.
+type PoolReference struct {
+	// Name of the VirtualClusterPool
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// VirtualClusterClaimPhase is a label for the phase of a VirtualClusterClaim
+// at the current time.
+type VirtualClusterClaimPhase string
+
+// These are the valid phases of a VirtualClusterClaim.
+const (
+	// VirtualClusterClaimPending means the claim waits for a ready
+	// VirtualCluster of the pool.
+	VirtualClusterClaimPending VirtualClusterClaimPhase = "Pending"
+
+	// VirtualClusterClaimBound means the claim is bound to a VirtualCluster.
+	VirtualClusterClaimBound VirtualClusterClaimPhase = "Bound"
+
+	// VirtualClusterClaimLost means the VirtualCluster bound to the claim was
+	// deleted.
+	VirtualClusterClaimLost VirtualClusterClaimPhase = "Lost"
+)
+
+// VirtualClusterClaimStatus defines the observed state of VirtualClusterClaim.
+type VirtualClusterClaimStatus struct {
+	// Phase is the current phase of the claim
+	// +optional
+	Phase VirtualClusterClaimPhase `json:"phase,omitempty"`
+
+	// Message provides human-readable details about the current status
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// VirtualClusterName is the name of the VirtualCluster bound to the claim
+	// +optional
+	VirtualClusterName string `json:"virtualClusterName,omitempty"`
+
+	// KubeconfigSecretRef references the Secret holding the kubeconfig of the
+	// bound VirtualCluster
+	// +optional
+	KubeconfigSecretRef *SecretKeyReference `json:"kubeconfigSecretRef,omitempty"`
+
+	// BoundTime is the time the claim was bound to the VirtualCluster
+	// +optional
+	BoundTime *metav1.Time `json:"boundTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Pool",type="string",JSONPath=".spec.poolRef.name",description="VirtualClusterPool of the claim"
+// +kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.phase",description="Status of the claim"
+// +kubebuilder:printcolumn:name="VirtualCluster",type="string",JSONPath=".status.virtualClusterName",description="VirtualCluster bound to the claim"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:shortName=vcclaim
+
+// VirtualClusterClaim is the Schema for the virtualclusterclaims API. It
+// binds a ready VirtualCluster of a VirtualClusterPool for as long as the
+// claim exists.
+type VirtualClusterClaim struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VirtualClusterClaimSpec   `json:"spec,omitempty"`
+	Status VirtualClusterClaimStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// VirtualClusterClaimList contains a list of VirtualClusterClaim.
+type VirtualClusterClaimList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VirtualClusterClaim `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&VirtualClusterClaim{}, &VirtualClusterClaimList{})
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"encoding/json"
+	"fmt"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// VirtualClusterClassSpec defines the settings shared by the VirtualClusters
+// referencing the class. Settings of a VirtualCluster take precedence.
+type VirtualClusterClassSpec struct {
+	// Chart selects the vcluster chart of the VirtualClusters that don't set
+	// spec.chart.version or spec.chart.source themselves.
+	// +optional
+	Chart HelmChart `json:"chart,omitempty"`
+
+	// Values are the base values of the VirtualClusters. Their valuesFrom and
+	// values are deep merged on top.
+	// +optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	Values *apiextensionsv1.JSON `json:"values,omitempty"`
+
+	// Validation configures how the values are validated against the schema
+	// of the chart.
+	// +optional
+	Validation *ValidationSpec `json:"validation,omitempty"`
+
+	// Drift configures how drift of the Helm releases from the desired state
+	// is handled.
+	// +optional
+	Drift *DriftSpec `json:"drift,omitempty"`
+
+	// UpgradePolicy configures how the Helm releases are installed and
+	// upgraded.
+	// +optional
+	UpgradePolicy *UpgradePolicy `json:"upgradePolicy,omitempty"`
+
+	// DeletionPolicy determines what happens to the Helm releases and their
+	// data when the VirtualClusters are deleted.
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster,shortName=vcclass
+// +kubebuilder:printcolumn:name="Chart Version",type="string",JSONPath=".spec.chart.version",description="Version of the vcluster chart"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// VirtualClusterClass is the Schema for the virtualclusterclasses API. It
+// holds the chart, base values and policies VirtualClusters reference with
+// spec.classRef.
+type VirtualClusterClass struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec VirtualClusterClassSpec `json:"spec,omitempty"`
+}
+
+// GetValues unmarshals the base values of the class.
+func (in *VirtualClusterClass) GetValues() (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+	if in.Spec.Values == nil {
+		return values, nil
+	}
+	if err := json.Unmarshal(in.Spec.Values.Raw, &values); err != nil {
+		if yamlErr := yaml.Unmarshal(in.Spec.Values.Raw, &values); yamlErr != nil {
+			return nil, fmt.Errorf("failed to unmarshal values of class %s: %v", in.Name, err)
+		}
+	}
+	if values == nil {
+		values = make(map[string]interface{})
+	}
+	return values, nil
+}
+
+// +kubebuilder:object:root=true
+
+// VirtualClusterClassList contains a list of VirtualClusterClass.
+type VirtualClusterClassList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VirtualClusterClass `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&VirtualClusterClass{}, &VirtualClusterClassList{})
+}
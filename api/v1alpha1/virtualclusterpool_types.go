@@ -0,0 +1,133 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// VirtualClusterPoolSpec defines the desired state of VirtualClusterPool.
+type VirtualClusterPoolSpec struct {
+	// Replicas is the number of ready VirtualClusters kept available for
+	// claims. Claiming one creates a replacement.
+	// +kubebuilder:validation:Minimum=0
+	Replicas int32 `json:"replicas"`
+
+	// Template describes the VirtualClusters created by the pool. Changing it
+	// replaces the unclaimed VirtualClusters, claimed ones are left alone.
+	Template VirtualClusterTemplate `json:"template"`
+
+	// ReleasePolicy determines what happens to a VirtualCluster when its
+	// claim is deleted. Defaults to Delete.
+	// +optional
+	// +kubebuilder:validation:Enum=Delete;Recycle
+	// +kubebuilder:default=Delete
+	ReleasePolicy PoolReleasePolicy `json:"releasePolicy,omitempty"`
+}
+
+// VirtualClusterTemplate describes the VirtualClusters created by a pool.
+type VirtualClusterTemplate struct {
+	// Metadata holds the labels and annotations of the VirtualClusters
+	// +optional
+	Metadata VirtualClusterTemplateMetadata `json:"metadata,omitempty"`
+
+	// Spec of the VirtualClusters
+	Spec VirtualClusterSpec `json:"spec"`
+}
+
+// VirtualClusterTemplateMetadata holds the metadata of the VirtualClusters
+// created by a pool.
+type VirtualClusterTemplateMetadata struct {
+	// Labels of the VirtualClusters
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations of the VirtualClusters
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// PoolReleasePolicy determines what happens to a released VirtualCluster.
+type PoolReleasePolicy string
+
+// These are the valid release policies.
+const (
+	// PoolReleasePolicyDelete deletes the VirtualCluster, so every claim gets
+	// a fresh one.
+	PoolReleasePolicyDelete PoolReleasePolicy = "Delete"
+
+	// PoolReleasePolicyRecycle returns the VirtualCluster to the pool as is,
+	// including the workloads of the previous claim.
+	PoolReleasePolicyRecycle PoolReleasePolicy = "Recycle"
+)
+
+// VirtualClusterPoolStatus defines the observed state of VirtualClusterPool.
+type VirtualClusterPoolStatus struct {
+	// ObservedGeneration is the generation of the spec that was last applied
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Replicas is the number of VirtualClusters of the pool, including
+	// claimed ones
+	// +optional
+	Replicas int32 `json:"replicas,omitempty"`
+
+	// ReadyReplicas is the number of ready VirtualClusters available for
+	// claims
+	// +optional
+	ReadyReplicas int32 `json:"readyReplicas,omitempty"`
+
+	// ClaimedReplicas is the number of VirtualClusters bound to a claim
+	// +optional
+	ClaimedReplicas int32 `json:"claimedReplicas,omitempty"`
+
+	// PendingClaims is the number of claims waiting for a ready
+	// VirtualCluster
+	// +optional
+	PendingClaims int32 `json:"pendingClaims,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Replicas",type="integer",JSONPath=".spec.replicas",description="Number of ready VirtualClusters to keep available"
+// +kubebuilder:printcolumn:name="Ready",type="integer",JSONPath=".status.readyReplicas",description="Number of ready VirtualClusters available for claims"
+// +kubebuilder:printcolumn:name="Claimed",type="integer",JSONPath=".status.claimedReplicas",description="Number of VirtualClusters bound to a claim"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:shortName=vcpool
+
+// VirtualClusterPool is the Schema for the virtualclusterpools API. It keeps
+// a number of VirtualClusters ready to be bound by a VirtualClusterClaim.
+type VirtualClusterPool struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VirtualClusterPoolSpec   `json:"spec,omitempty"`
+	Status VirtualClusterPoolStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// VirtualClusterPoolList contains a list of VirtualClusterPool.
+type VirtualClusterPoolList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VirtualClusterPool `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&VirtualClusterPool{}, &VirtualClusterPoolList{})
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// VirtualClusterSetSpec defines the desired state of VirtualClusterSet.
+type VirtualClusterSetSpec struct {
+	// Replicas is the number of VirtualClusters of the set
+	// +kubebuilder:validation:Minimum=0
+	Replicas int32 `json:"replicas"`
+
+	// NamePattern names the VirtualClusters of the set. {name} is replaced
+	// with the name of the set and {index} with the index of the
+	// VirtualCluster, starting at 0. Defaults to "{name}-{index}".
+	// +optional
+	// +kubebuilder:default="{name}-{index}"
+	// +kubebuilder:validation:XValidation:rule="self.contains('{index}')",message="namePattern must contain {index}"
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="namePattern is immutable"
+	NamePattern string `json:"namePattern,omitempty"`
+
+	// Template describes the VirtualClusters of the set. Changes are rolled
+	// out to them as configured by the update strategy.
+	Template VirtualClusterTemplate `json:"template"`
+
+	// UpdateStrategy configures how changes of the template are rolled out.
+	// +optional
+	UpdateStrategy VirtualClusterSetUpdateStrategy `json:"updateStrategy,omitempty"`
+}
+
+// VirtualClusterSetUpdateStrategy configures the rollout of template changes.
+type VirtualClusterSetUpdateStrategy struct {
+	// MaxUnavailable is the maximum number or percentage of VirtualClusters
+	// that may be unavailable while the template is rolled out. Defaults to 1.
+	// +optional
+	// +kubebuilder:validation:XIntOrString
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+// VirtualClusterSetStatus defines the observed state of VirtualClusterSet.
+type VirtualClusterSetStatus struct {
+	// ObservedGeneration is the generation of the spec that was last applied
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Replicas is the number of VirtualClusters of the set
+	// +optional
+	Replicas int32 `json:"replicas,omitempty"`
+
+	// ReadyReplicas is the number of VirtualClusters that are running and
+	// available with their current spec applied
+	// +optional
+	ReadyReplicas int32 `json:"readyReplicas,omitempty"`
+
+	// FailedReplicas is the number of failed VirtualClusters
+	// +optional
+	FailedReplicas int32 `json:"failedReplicas,omitempty"`
+
+	// UpdatedReplicas is the number of VirtualClusters of the current
+	// template
+	// +optional
+	UpdatedReplicas int32 `json:"updatedReplicas,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:subresource:scale:specpath=.spec.replicas,statuspath=.status.replicas
+// +kubebuilder:printcolumn:name="Replicas",type="integer",JSONPath=".spec.replicas",description="Number of VirtualClusters of the set"
+// +kubebuilder:printcolumn:name="Ready",type="integer",JSONPath=".status.readyReplicas",description="Number of ready VirtualClusters"
+// +kubebuilder:printcolumn:name="Failed",type="integer",JSONPath=".status.failedReplicas",description="Number of failed VirtualClusters"
+// +kubebuilder:printcolumn:name="Updated",type="integer",JSONPath=".status.updatedReplicas",description="Number of VirtualClusters of the current template"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:shortName=vcset
+
+// VirtualClusterSet is the Schema for the virtualclustersets API. It creates a
+// number of identical VirtualClusters from a template.
+type VirtualClusterSet struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VirtualClusterSetSpec   `json:"spec,omitempty"`
+	Status VirtualClusterSetStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// VirtualClusterSetList contains a list of VirtualClusterSet.
+type VirtualClusterSetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VirtualClusterSet `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&VirtualClusterSet{}, &VirtualClusterSetList{})
+}
@@ -0,0 +1,393 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/OpenVirtualCluster/openvirtualcluster-operator/api/v1alpha1"
+)
+
+// Chart values paths of the typed fields
+const (
+	distroPath      = "controlPlane.distro"
+	volumeClaimPath = "controlPlane.statefulSet.persistence.volumeClaim"
+	resourcesPath   = "controlPlane.statefulSet.resources"
+	serviceTypePath = "controlPlane.service.spec.type"
+	ingressPath     = "controlPlane.ingress"
+)
+
+// ConvertTo converts this VirtualCluster to the Hub version (v1alpha1). The
+// typed fields are written to the values, taking precedence over the raw
+// values.
+func (src *VirtualCluster) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*v1alpha1.VirtualCluster)
+	if !ok {
+		return fmt.Errorf("expected a v1alpha1 VirtualCluster but got %T", dstRaw)
+	}
+
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = v1alpha1.VirtualClusterSpec{}
+	spec := src.Spec
+	spec.Values = nil
+	if err := convertFields(spec, &dst.Spec); err != nil {
+		return fmt.Errorf("failed to convert spec: %w", err)
+	}
+	dst.Spec.Values = src.Spec.Values
+	dst.Status = v1alpha1.VirtualClusterStatus{}
+	if err := convertFields(src.Status, &dst.Status); err != nil {
+		return fmt.Errorf("failed to convert status: %w", err)
+	}
+
+	values, err := src.GetValues()
+	if err != nil {
+		if !src.Spec.hasTypedFields() {
+			// Keep the raw values for the validating webhook to report
+			return nil
+		}
+		return err
+	}
+	src.Spec.applyTo(values)
+
+	raw, err := json.Marshal(values)
+	if err != nil {
+		return fmt.Errorf("failed to marshal values: %w", err)
+	}
+	dst.Spec.Values = &apiextensionsv1.JSON{Raw: raw}
+	return nil
+}
+
+// ConvertFrom converts the Hub version (v1alpha1) to this version. Values
+// with a typed field are moved to it and the rest is kept in the raw values.
+func (dst *VirtualCluster) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*v1alpha1.VirtualCluster)
+	if !ok {
+		return fmt.Errorf("expected a v1alpha1 VirtualCluster but got %T", srcRaw)
+	}
+
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = VirtualClusterSpec{}
+	spec := src.Spec
+	spec.Values = nil
+	if err := convertFields(spec, &dst.Spec); err != nil {
+		return fmt.Errorf("failed to convert spec: %w", err)
+	}
+	dst.Spec.Values = src.Spec.Values
+	dst.Status = VirtualClusterStatus{}
+	if err := convertFields(src.Status, &dst.Status); err != nil {
+		return fmt.Errorf("failed to convert status: %w", err)
+	}
+
+	values, err := src.GetValues()
+	if err != nil {
+		// Keep the raw values as they are
+		return nil
+	}
+	dst.Spec.extractFrom(values)
+
+	dst.Spec.Values = nil
+	if len(values) > 0 {
+		raw, err := json.Marshal(values)
+		if err != nil {
+			return fmt.Errorf("failed to marshal values: %w", err)
+		}
+		dst.Spec.Values = &apiextensionsv1.JSON{Raw: raw}
+	}
+	return nil
+}
+
+// convertFields copies the fields both versions share through their JSON
+// representation.
+func convertFields(in, out interface{}) error {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
+
+func (s *VirtualClusterSpec) hasTypedFields() bool {
+	return s.Distro != nil || s.Persistence != nil || s.Exposure != nil || s.Sync != nil || s.Resources != nil
+}
+
+// applyTo writes the typed fields to the values.
+func (s *VirtualClusterSpec) applyTo(values map[string]interface{}) {
+	if d := s.Distro; d != nil {
+		setValue(values, distroPath+"."+string(d.Name)+".enabled", true)
+		if d.KubernetesVersion != "" {
+			setValue(values, distroPath+"."+string(d.Name)+".image.tag", d.KubernetesVersion)
+		}
+	}
+
+	if p := s.Persistence; p != nil {
+		if p.Enabled != nil {
+			setValue(values, volumeClaimPath+".enabled", *p.Enabled)
+		}
+		if p.Size != nil {
+			setValue(values, volumeClaimPath+".size", p.Size.String())
+		}
+		if p.StorageClassName != nil {
+			setValue(values, volumeClaimPath+".storageClass", *p.StorageClassName)
+		}
+	}
+
+	if e := s.Exposure; e != nil {
+		if e.ServiceType != "" {
+			setValue(values, serviceTypePath, string(e.ServiceType))
+		}
+		if e.Ingress != nil {
+			setValue(values, ingressPath+".enabled", true)
+			if e.Ingress.Host != "" {
+				setValue(values, ingressPath+".host", e.Ingress.Host)
+			}
+		}
+	}
+
+	if s.Sync != nil {
+		toHost, fromHost := SyncToHost{}, SyncFromHost{}
+		if s.Sync.ToHost != nil {
+			toHost = *s.Sync.ToHost
+		}
+		if s.Sync.FromHost != nil {
+			fromHost = *s.Sync.FromHost
+		}
+		for path, toggle := range syncToggles(&toHost, &fromHost) {
+			if *toggle != nil {
+				setValue(values, path, **toggle)
+			}
+		}
+	}
+
+	if r := s.Resources; r != nil {
+		resources := make(map[string]interface{})
+		if len(r.Limits) > 0 {
+			resources["limits"] = resourceListValues(r.Limits)
+		}
+		if len(r.Requests) > 0 {
+			resources["requests"] = resourceListValues(r.Requests)
+		}
+		setValue(values, resourcesPath, resources)
+	}
+}
+
+// extractFrom moves the values with a typed field from the values to the
+// field. Values that can't be represented exactly are left in place.
+func (s *VirtualClusterSpec) extractFrom(values map[string]interface{}) {
+	s.Distro = extractDistro(values)
+
+	persistence := &PersistenceSpec{}
+	if enabled, ok := takeValue[bool](values, volumeClaimPath+".enabled"); ok {
+		persistence.Enabled = &enabled
+	}
+	if size, ok := lookupValue[string](values, volumeClaimPath+".size"); ok {
+		if q, err := resource.ParseQuantity(size); err == nil && q.String() == size {
+			deleteValue(values, volumeClaimPath+".size")
+			persistence.Size = &q
+		}
+	}
+	if storageClass, ok := takeValue[string](values, volumeClaimPath+".storageClass"); ok {
+		persistence.StorageClassName = &storageClass
+	}
+	if *persistence != (PersistenceSpec{}) {
+		s.Persistence = persistence
+	}
+
+	exposure := &ExposureSpec{}
+	if serviceType, ok := lookupValue[string](values, serviceTypePath); ok {
+		switch t := corev1.ServiceType(serviceType); t {
+		case corev1.ServiceTypeClusterIP, corev1.ServiceTypeNodePort, corev1.ServiceTypeLoadBalancer:
+			deleteValue(values, serviceTypePath)
+			exposure.ServiceType = t
+		}
+	}
+	if enabled, ok := lookupValue[bool](values, ingressPath+".enabled"); ok && enabled {
+		deleteValue(values, ingressPath+".enabled")
+		exposure.Ingress = &IngressSpec{}
+		if host, ok := takeValue[string](values, ingressPath+".host"); ok {
+			exposure.Ingress.Host = host
+		}
+	}
+	if exposure.ServiceType != "" || exposure.Ingress != nil {
+		s.Exposure = exposure
+	}
+
+	toHost, fromHost := &SyncToHost{}, &SyncFromHost{}
+	for path, toggle := range syncToggles(toHost, fromHost) {
+		if enabled, ok := takeValue[bool](values, path); ok {
+			*toggle = &enabled
+		}
+	}
+	if *toHost != (SyncToHost{}) || *fromHost != (SyncFromHost{}) {
+		s.Sync = &SyncSpec{}
+		if *toHost != (SyncToHost{}) {
+			s.Sync.ToHost = toHost
+		}
+		if *fromHost != (SyncFromHost{}) {
+			s.Sync.FromHost = fromHost
+		}
+	}
+
+	s.Resources = extractResources(values)
+}
+
+// extractDistro moves the enabled distro and its image tag to a DistroSpec.
+func extractDistro(values map[string]interface{}) *DistroSpec {
+	var enabled []Distro
+	for _, name := range []Distro{DistroK3s, DistroK8s, DistroK0s} {
+		if on, ok := lookupValue[bool](values, distroPath+"."+string(name)+".enabled"); ok && on {
+			enabled = append(enabled, name)
+		}
+	}
+	if len(enabled) != 1 {
+		return nil
+	}
+
+	prefix := distroPath + "." + string(enabled[0])
+	deleteValue(values, prefix+".enabled")
+	distro := &DistroSpec{Name: enabled[0]}
+	if tag, ok := takeValue[string](values, prefix+".image.tag"); ok {
+		distro.KubernetesVersion = tag
+	}
+	return distro
+}
+
+// extractResources moves the resources of the control plane to
+// ResourceRequirements if all quantities can be represented exactly.
+func extractResources(values map[string]interface{}) *corev1.ResourceRequirements {
+	raw, ok := lookupValue[map[string]interface{}](values, resourcesPath)
+	if !ok || len(raw) == 0 {
+		return nil
+	}
+
+	resources := &corev1.ResourceRequirements{}
+	for key, list := range raw {
+		items, ok := list.(map[string]interface{})
+		if !ok || len(items) == 0 {
+			return nil
+		}
+		parsed := corev1.ResourceList{}
+		for name, value := range items {
+			s, ok := value.(string)
+			if !ok {
+				return nil
+			}
+			q, err := resource.ParseQuantity(s)
+			if err != nil || q.String() != s {
+				return nil
+			}
+			parsed[corev1.ResourceName(name)] = q
+		}
+		switch key {
+		case "limits":
+			resources.Limits = parsed
+		case "requests":
+			resources.Requests = parsed
+		default:
+			return nil
+		}
+	}
+
+	deleteValue(values, resourcesPath)
+	return resources
+}
+
+// syncToggles returns the sync toggles keyed by their values path.
+func syncToggles(toHost *SyncToHost, fromHost *SyncFromHost) map[string]**bool {
+	return map[string]**bool{
+		"sync.toHost.ingresses.enabled":         &toHost.Ingresses,
+		"sync.toHost.persistentVolumes.enabled": &toHost.PersistentVolumes,
+		"sync.toHost.networkPolicies.enabled":   &toHost.NetworkPolicies,
+		"sync.toHost.serviceAccounts.enabled":   &toHost.ServiceAccounts,
+		"sync.fromHost.nodes.enabled":           &fromHost.Nodes,
+		"sync.fromHost.storageClasses.enabled":  &fromHost.StorageClasses,
+		"sync.fromHost.ingressClasses.enabled":  &fromHost.IngressClasses,
+	}
+}
+
+func resourceListValues(list corev1.ResourceList) map[string]interface{} {
+	out := make(map[string]interface{}, len(list))
+	for name, q := range list {
+		out[string(name)] = q.String()
+	}
+	return out
+}
+
+// setValue sets the value at the dot separated path, creating missing maps.
+func setValue(values map[string]interface{}, path string, value interface{}) {
+	keys := strings.Split(path, ".")
+	current := values
+	for _, key := range keys[:len(keys)-1] {
+		next, ok := current[key].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			current[key] = next
+		}
+		current = next
+	}
+	current[keys[len(keys)-1]] = value
+}
+
+// lookupValue returns the value at the dot separated path if it has type T.
+func lookupValue[T any](values map[string]interface{}, path string) (T, bool) {
+	var zero T
+	keys := strings.Split(path, ".")
+	current := values
+	for _, key := range keys[:len(keys)-1] {
+		next, ok := current[key].(map[string]interface{})
+		if !ok {
+			return zero, false
+		}
+		current = next
+	}
+	value, ok := current[keys[len(keys)-1]].(T)
+	return value, ok
+}
+
+// takeValue returns and deletes the value at the dot separated path if it
+// has type T.
+func takeValue[T any](values map[string]interface{}, path string) (T, bool) {
+	value, ok := lookupValue[T](values, path)
+	if ok {
+		deleteValue(values, path)
+	}
+	return value, ok
+}
+
+// deleteValue deletes the value at the dot separated path and the maps left
+// empty by it.
+func deleteValue(values map[string]interface{}, path string) {
+	key, rest, nested := strings.Cut(path, ".")
+	if !nested {
+		delete(values, key)
+		return
+	}
+	next, ok := values[key].(map[string]interface{})
+	if !ok {
+		return
+	}
+	deleteValue(next, rest)
+	if len(next) == 0 {
+		delete(values, key)
+	}
+}
@@ -0,0 +1,257 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/OpenVirtualCluster/openvirtualcluster-operator/api/v1alpha1"
+)
+
+func rawValues(t *testing.T, values map[string]interface{}) *apiextensionsv1.JSON {
+	t.Helper()
+	raw, err := json.Marshal(values)
+	require.NoError(t, err)
+	return &apiextensionsv1.JSON{Raw: raw}
+}
+
+func TestVirtualCluster_ConvertTo(t *testing.T) {
+	size := resource.MustParse("10Gi")
+	src := &VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+		Spec: VirtualClusterSpec{
+			Chart:       HelmChart{Version: "v0.24.1"},
+			Distro:      &DistroSpec{Name: DistroK8s, KubernetesVersion: "v1.30.2"},
+			Persistence: &PersistenceSpec{Enabled: ptr.To(true), Size: &size, StorageClassName: ptr.To("fast")},
+			Exposure:    &ExposureSpec{ServiceType: corev1.ServiceTypeLoadBalancer, Ingress: &IngressSpec{Host: "vc.example.com"}},
+			Sync:        &SyncSpec{ToHost: &SyncToHost{Ingresses: ptr.To(true)}, FromHost: &SyncFromHost{Nodes: ptr.To(false)}},
+			Resources: &corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("2Gi")},
+			},
+			Validation: &ValidationSpec{Mode: ValidationModeEnforce},
+			Values: rawValues(t, map[string]interface{}{
+				"controlPlane": map[string]interface{}{
+					"service": map[string]interface{}{"spec": map[string]interface{}{"type": "NodePort"}},
+				},
+				"telemetry": map[string]interface{}{"enabled": false},
+			}),
+		},
+		Status: VirtualClusterStatus{Phase: VirtualClusterRunning},
+	}
+
+	dst := &v1alpha1.VirtualCluster{}
+	require.NoError(t, src.ConvertTo(dst))
+
+	assert.Equal(t, "test", dst.Name)
+	assert.Equal(t, "v0.24.1", dst.Spec.Chart.Version)
+	assert.Equal(t, v1alpha1.ValidationModeEnforce, dst.Spec.Validation.Mode)
+	assert.Equal(t, v1alpha1.VirtualClusterRunning, dst.Status.Phase)
+
+	values, err := dst.GetValues()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"controlPlane": map[string]interface{}{
+			"distro": map[string]interface{}{
+				"k8s": map[string]interface{}{"enabled": true, "image": map[string]interface{}{"tag": "v1.30.2"}},
+			},
+			"statefulSet": map[string]interface{}{
+				"persistence": map[string]interface{}{
+					"volumeClaim": map[string]interface{}{"enabled": true, "size": "10Gi", "storageClass": "fast"},
+				},
+				"resources": map[string]interface{}{"limits": map[string]interface{}{"memory": "2Gi"}},
+			},
+			// The typed field takes precedence over the raw values
+			"service": map[string]interface{}{"spec": map[string]interface{}{"type": "LoadBalancer"}},
+			"ingress": map[string]interface{}{"enabled": true, "host": "vc.example.com"},
+		},
+		"sync": map[string]interface{}{
+			"toHost":   map[string]interface{}{"ingresses": map[string]interface{}{"enabled": true}},
+			"fromHost": map[string]interface{}{"nodes": map[string]interface{}{"enabled": false}},
+		},
+		"telemetry": map[string]interface{}{"enabled": false},
+	}, values)
+}
+
+func TestVirtualCluster_ConvertFrom(t *testing.T) {
+	tests := []struct {
+		name       string
+		values     map[string]interface{}
+		wantSpec   VirtualClusterSpec
+		wantValues map[string]interface{}
+	}{
+		{
+			name:   "empty values",
+			values: map[string]interface{}{},
+		},
+		{
+			name: "typed values",
+			values: map[string]interface{}{
+				"controlPlane": map[string]interface{}{
+					"distro": map[string]interface{}{
+						"k3s": map[string]interface{}{
+							"enabled": true,
+							"image":   map[string]interface{}{"repository": "rancher/k3s", "tag": "v1.30.2-k3s1"},
+						},
+					},
+					"service": map[string]interface{}{"spec": map[string]interface{}{"type": "NodePort"}},
+					"statefulSet": map[string]interface{}{
+						"persistence": map[string]interface{}{"volumeClaim": map[string]interface{}{"size": "5Gi"}},
+						"resources": map[string]interface{}{
+							"requests": map[string]interface{}{"cpu": "200m"},
+						},
+					},
+				},
+				"sync": map[string]interface{}{
+					"fromHost": map[string]interface{}{"storageClasses": map[string]interface{}{"enabled": true}},
+				},
+			},
+			wantSpec: VirtualClusterSpec{
+				Distro:      &DistroSpec{Name: DistroK3s, KubernetesVersion: "v1.30.2-k3s1"},
+				Persistence: &PersistenceSpec{Size: ptr.To(resource.MustParse("5Gi"))},
+				Exposure:    &ExposureSpec{ServiceType: corev1.ServiceTypeNodePort},
+				Sync:        &SyncSpec{FromHost: &SyncFromHost{StorageClasses: ptr.To(true)}},
+				Resources: &corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("200m")},
+				},
+			},
+			wantValues: map[string]interface{}{
+				"controlPlane": map[string]interface{}{
+					"distro": map[string]interface{}{
+						"k3s": map[string]interface{}{"image": map[string]interface{}{"repository": "rancher/k3s"}},
+					},
+				},
+			},
+		},
+		{
+			name: "values that can't be represented exactly stay raw",
+			values: map[string]interface{}{
+				"controlPlane": map[string]interface{}{
+					"service": map[string]interface{}{"spec": map[string]interface{}{"type": "ExternalName"}},
+					"ingress": map[string]interface{}{"enabled": false, "host": "vc.example.com"},
+					"statefulSet": map[string]interface{}{
+						"persistence": map[string]interface{}{"volumeClaim": map[string]interface{}{"size": "0.5Gi"}},
+						"resources":   map[string]interface{}{"limits": map[string]interface{}{"cpu": 1.0}},
+					},
+				},
+				"vcluster": map[string]interface{}{"image": "rancher/k3s:v1.25.0-k3s1"},
+			},
+			wantValues: map[string]interface{}{
+				"controlPlane": map[string]interface{}{
+					"service": map[string]interface{}{"spec": map[string]interface{}{"type": "ExternalName"}},
+					"ingress": map[string]interface{}{"enabled": false, "host": "vc.example.com"},
+					"statefulSet": map[string]interface{}{
+						"persistence": map[string]interface{}{"volumeClaim": map[string]interface{}{"size": "0.5Gi"}},
+						"resources":   map[string]interface{}{"limits": map[string]interface{}{"cpu": 1.0}},
+					},
+				},
+				"vcluster": map[string]interface{}{"image": "rancher/k3s:v1.25.0-k3s1"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := &v1alpha1.VirtualCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+				Spec: v1alpha1.VirtualClusterSpec{
+					Chart:  v1alpha1.HelmChart{Version: "v0.24.1"},
+					Values: rawValues(t, tt.values),
+				},
+			}
+
+			dst := &VirtualCluster{}
+			require.NoError(t, dst.ConvertFrom(src))
+
+			// Converting back restores the original values
+			hub := &v1alpha1.VirtualCluster{}
+			require.NoError(t, dst.ConvertTo(hub))
+			roundTrip, err := hub.GetValues()
+			require.NoError(t, err)
+			assert.Equal(t, tt.values, roundTrip)
+
+			assert.Equal(t, "v0.24.1", dst.Spec.Chart.Version)
+			values, err := dst.GetValues()
+			require.NoError(t, err)
+			if tt.wantValues == nil {
+				assert.Nil(t, dst.Spec.Values)
+				tt.wantValues = map[string]interface{}{}
+			}
+			assert.Equal(t, tt.wantValues, values)
+
+			dst.Spec.Chart, dst.Spec.Values = HelmChart{}, nil
+			assert.Equal(t, tt.wantSpec, dst.Spec)
+		})
+	}
+}
+
+func TestVirtualCluster_RoundTrip(t *testing.T) {
+	src := &v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+		Spec: v1alpha1.VirtualClusterSpec{
+			Values: rawValues(t, map[string]interface{}{
+				"controlPlane": map[string]interface{}{
+					"distro":  map[string]interface{}{"k0s": map[string]interface{}{"enabled": true}},
+					"ingress": map[string]interface{}{"enabled": true, "host": "vc.example.com", "pathType": "Prefix"},
+				},
+				"sync": map[string]interface{}{
+					"toHost": map[string]interface{}{"serviceAccounts": map[string]interface{}{"enabled": true}},
+				},
+				"telemetry": map[string]interface{}{"enabled": false},
+			}),
+		},
+	}
+
+	spoke := &VirtualCluster{}
+	require.NoError(t, spoke.ConvertFrom(src))
+	assert.Equal(t, &DistroSpec{Name: DistroK0s}, spoke.Spec.Distro)
+	assert.Equal(t, &IngressSpec{Host: "vc.example.com"}, spoke.Spec.Exposure.Ingress)
+
+	hub := &v1alpha1.VirtualCluster{}
+	require.NoError(t, spoke.ConvertTo(hub))
+
+	want, err := src.GetValues()
+	require.NoError(t, err)
+	got, err := hub.GetValues()
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+}
+
+func TestVirtualCluster_ConvertInvalidValues(t *testing.T) {
+	src := &v1alpha1.VirtualCluster{
+		Spec: v1alpha1.VirtualClusterSpec{Values: &apiextensionsv1.JSON{Raw: []byte("{invalid")}},
+	}
+
+	dst := &VirtualCluster{}
+	require.NoError(t, dst.ConvertFrom(src))
+	assert.Equal(t, []byte("{invalid"), dst.Spec.Values.Raw)
+
+	hub := &v1alpha1.VirtualCluster{}
+	require.NoError(t, dst.ConvertTo(hub))
+	assert.Equal(t, []byte("{invalid"), hub.Spec.Values.Raw)
+
+	dst.Spec.Distro = &DistroSpec{Name: DistroK3s}
+	assert.Error(t, dst.ConvertTo(hub))
+}
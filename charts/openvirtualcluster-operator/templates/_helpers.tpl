@@ -59,4 +59,28 @@ Create the name of the service account to use
 {{- else }}
 {{- default "default" .Values.serviceAccount.name }}
 {{- end }}
-{{- end }} 
\ No newline at end of file
+{{- end }} 
+{{/*
+Serving certificate of the webhooks generated by Helm, as a dict holding the
+base64 encoded caBundle, tlsCert and tlsKey. The existing Secret is reused so
+upgrades don't rotate the certificate. It is generated once per render, so the
+webhook configurations and the CRD share the CA.
+*/}}
+{{- define "openvirtualcluster-operator.webhookCert" -}}
+{{- if not (hasKey .Values.webhook "generatedCert") }}
+{{- $fullName := include "openvirtualcluster-operator.fullname" . }}
+{{- $serviceName := printf "%s-webhook" $fullName }}
+{{- $cert := dict }}
+{{- $existing := lookup "v1" "Secret" .Release.Namespace (printf "%s-webhook-cert" $fullName) }}
+{{- if and $existing (index $existing.data "ca.crt") }}
+{{- $cert = dict "caBundle" (index $existing.data "ca.crt") "tlsCert" (index $existing.data "tls.crt") "tlsKey" (index $existing.data "tls.key") }}
+{{- else }}
+{{- $altNames := list (printf "%s.%s.svc" $serviceName .Release.Namespace) (printf "%s.%s.svc.cluster.local" $serviceName .Release.Namespace) }}
+{{- $ca := genCA (printf "%s-ca" $fullName) (int .Values.webhook.selfSigned.validityDays) }}
+{{- $signed := genSignedCert $serviceName nil $altNames (int .Values.webhook.selfSigned.validityDays) $ca }}
+{{- $cert = dict "caBundle" ($ca.Cert | b64enc) "tlsCert" ($signed.Cert | b64enc) "tlsKey" ($signed.Key | b64enc) }}
+{{- end }}
+{{- $_ := set .Values.webhook "generatedCert" $cert }}
+{{- end }}
+{{- toYaml (index .Values.webhook "generatedCert") }}
+{{- end }}
@@ -19,7 +19,10 @@ package main
 import (
 	"crypto/tls"
 	"flag"
+	"fmt"
 	"os"
+	"path/filepath"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -34,9 +37,13 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/yaml"
 
 	corev1alpha1 "github.com/OpenVirtualCluster/openvirtualcluster-operator/api/v1alpha1"
+	corev1beta1 "github.com/OpenVirtualCluster/openvirtualcluster-operator/api/v1beta1"
 	"github.com/OpenVirtualCluster/openvirtualcluster-operator/internal/controller"
+	"github.com/OpenVirtualCluster/openvirtualcluster-operator/internal/helm"
+	webhookcorev1alpha1 "github.com/OpenVirtualCluster/openvirtualcluster-operator/internal/webhook/v1alpha1"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -49,6 +56,7 @@ func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
 	utilruntime.Must(corev1alpha1.AddToScheme(scheme))
+	utilruntime.Must(corev1beta1.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
 }
 
@@ -58,6 +66,16 @@ func main() {
 	var probeAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
+	var offlineChartDir string
+	var defaultValidationMode string
+	var defaultValuesFile string
+	var resyncInterval time.Duration
+	var selfHeal bool
+	var probeAPIServer bool
+	var pendingReleaseTimeout time.Duration
+	var deletionTimeout time.Duration
+	var chartCacheDir string
+	var chartIndexRefreshInterval time.Duration
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
@@ -69,6 +87,30 @@ func main() {
 		"If set, the metrics endpoint is served securely via HTTPS. Use --metrics-secure=false to use HTTP instead.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.StringVar(&offlineChartDir, "offline-chart-dir", "",
+		"Directory holding packaged vcluster charts named <chart>-<version>.tgz. "+
+			"If set, charts and schemas are loaded from it instead of the network unless a VirtualCluster sets a repository.")
+	flag.StringVar(&defaultValidationMode, "default-validation-mode", string(corev1alpha1.ValidationModeWarn),
+		"Schema validation mode for VirtualClusters that don't set spec.validation.mode. One of Disabled, Warn or Enforce.")
+	flag.StringVar(&defaultValuesFile, "default-values-file", "",
+		"YAML file holding operator-level values merged beneath the values of every VirtualCluster.")
+	flag.DurationVar(&resyncInterval, "resync-interval", 10*time.Minute,
+		"Interval running VirtualClusters are checked for drift of their Helm release. Set to 0 to disable.")
+	flag.BoolVar(&selfHeal, "self-heal", false,
+		"If set, drifted Helm releases are re-applied for VirtualClusters that don't set spec.drift.selfHeal.")
+	flag.BoolVar(&probeAPIServer, "probe-api-server", true,
+		"If set, VirtualClusters only become available once the /readyz endpoint of their API server reports ready, "+
+			"and their API server is probed for activity to apply spec.idleTimeout. "+
+			"Disable it when the manager can't reach Services of the cluster, e.g. when running it locally.")
+	flag.DurationVar(&pendingReleaseTimeout, "pending-release-timeout", 15*time.Minute,
+		"Time after which a Helm release stuck in a pending state is rolled back or its pending revision removed. "+
+			"Set to 0 to disable.")
+	flag.DurationVar(&deletionTimeout, "deletion-timeout", 10*time.Minute,
+		"Time after which a VirtualCluster waiting for its resources to be deleted reports an error. Set to 0 to disable.")
+	flag.StringVar(&chartCacheDir, "chart-cache-dir", filepath.Join(os.TempDir(), "openvc-charts"),
+		"Directory the chart archives and repository indexes shared by all VirtualClusters are cached in.")
+	flag.DurationVar(&chartIndexRefreshInterval, "chart-index-refresh-interval", 10*time.Minute,
+		"Interval cached repository indexes are downloaded again to pick up new chart versions.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -77,6 +119,25 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	switch mode := corev1alpha1.ValidationMode(defaultValidationMode); mode {
+	case corev1alpha1.ValidationModeDisabled, corev1alpha1.ValidationModeWarn, corev1alpha1.ValidationModeEnforce:
+	default:
+		setupLog.Error(fmt.Errorf("unknown validation mode %q", mode), "invalid --default-validation-mode")
+		os.Exit(1)
+	}
+
+	defaultValues := map[string]interface{}{}
+	if defaultValuesFile != "" {
+		data, err := os.ReadFile(defaultValuesFile)
+		if err == nil {
+			err = yaml.Unmarshal(data, &defaultValues)
+		}
+		if err != nil {
+			setupLog.Error(err, "unable to load default values", "file", defaultValuesFile)
+			os.Exit(1)
+		}
+	}
+
 	// if the enable-http2 flag is false (the default), http/2 should be disabled
 	// due to its vulnerabilities. More specifically, disabling http/2 will
 	// prevent from being vulnerable to the HTTP/2 Stream Cancellation and
@@ -144,14 +205,68 @@ func main() {
 		os.Exit(1)
 	}
 
+	chartCache, err := helm.NewChartCache(chartCacheDir, chartIndexRefreshInterval)
+	if err != nil {
+		setupLog.Error(err, "unable to create chart cache")
+		os.Exit(1)
+	}
+
+	helmEngine, err := helm.NewEngine(mgr.GetConfig(), chartCache)
+	if err != nil {
+		setupLog.Error(err, "unable to create Helm engine")
+		os.Exit(1)
+	}
+
+	var prober controller.APIServerProber
+	var activityProber controller.ActivityProber
+	if probeAPIServer {
+		prober = controller.NewAPIServerProber(5 * time.Second)
+		activityProber = controller.NewActivityProber(5 * time.Second)
+	}
+
 	if err = (&controller.VirtualClusterReconciler{
 		Client:   mgr.GetClient(),
 		Scheme:   mgr.GetScheme(),
 		Recorder: mgr.GetEventRecorderFor("virtualcluster-controller"),
+		Helm:     helmEngine,
+
+		DefaultValidationMode: corev1alpha1.ValidationMode(defaultValidationMode),
+		OfflineChartDir:       offlineChartDir,
+		DefaultValues:         defaultValues,
+		ResyncInterval:        resyncInterval,
+		DefaultSelfHeal:       selfHeal,
+		Prober:                prober,
+		PendingReleaseTimeout: pendingReleaseTimeout,
+		DeletionTimeout:       deletionTimeout,
+		ActivityProber:        activityProber,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "VirtualCluster")
 		os.Exit(1)
 	}
+	if err = (&controller.VirtualClusterPoolReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("virtualclusterpool-controller"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "VirtualClusterPool")
+		os.Exit(1)
+	}
+	if err = (&controller.VirtualClusterSetReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("virtualclusterset-controller"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "VirtualClusterSet")
+		os.Exit(1)
+	}
+	// nolint:goconst
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err = webhookcorev1alpha1.SetupVirtualClusterWebhookWithManager(mgr,
+			corev1alpha1.ValidationMode(defaultValidationMode), defaultValues); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "VirtualCluster")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -150,8 +150,8 @@ EOF
 # Copy CRDs
 echo "Generating Helm chart templates from Kustomize configurations..."
 echo "Copying CRDs to chart directory..."
-mkdir -p ${CHART_PATH}/crds
-cp config/crd/bases/* ${CHART_PATH}/crds/ 2>/dev/null || true
+mkdir -p ${CHART_PATH}/files/crds
+cp config/crd/bases/* ${CHART_PATH}/files/crds/ 2>/dev/null || true
 
 # Generate RBAC and manager kustomize output
 echo "Generating RBAC templates..."
@@ -0,0 +1,248 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	corev1alpha1 "github.com/OpenVirtualCluster/openvirtualcluster-operator/api/v1alpha1"
+	"github.com/OpenVirtualCluster/openvirtualcluster-operator/internal/helm"
+)
+
+const (
+	// chartArchiveKey is the key holding the chart archive in offline sources
+	chartArchiveKey = "chart.tgz"
+	// schemaKey is the key holding the values schema
+	schemaKey = "values.schema.json"
+)
+
+// resolvedChart is the chart to deploy together with where it came from.
+type resolvedChart struct {
+	// Ref is passed to the release engine
+	Ref helm.ChartRef
+	// SourceType is reported in the status
+	SourceType corev1alpha1.ChartSourceType
+	// Location describes the source of the chart for the status
+	Location string
+	// Schema is the values schema of an offline chart
+	Schema string
+}
+
+// chartVersionMismatchError is returned when an offline chart archive is not
+// the chart version requested by the VirtualCluster.
+type chartVersionMismatchError struct {
+	location  string
+	requested string
+	archived  string
+}
+
+func (e *chartVersionMismatchError) Error() string {
+	return fmt.Sprintf("chart in %s has version %s but version %s was requested", e.location, e.archived, e.requested)
+}
+
+// isChartVersionMismatch returns true if err reports an offline chart with
+// the wrong version.
+func isChartVersionMismatch(err error) bool {
+	var mismatch *chartVersionMismatchError
+	return errors.As(err, &mismatch)
+}
+
+// chartVersionMatches returns true if the chart version satisfies the
+// requested version, which may also be a semver constraint.
+func chartVersionMatches(requested, version string) bool {
+	if constraint, err := semver.NewConstraint(requested); err == nil {
+		if v, err := semver.NewVersion(version); err == nil {
+			return constraint.Check(v)
+		}
+	}
+	return strings.TrimPrefix(requested, "v") == strings.TrimPrefix(version, "v")
+}
+
+// offline returns true if the chart does not need network access.
+func (c *resolvedChart) offline() bool {
+	return len(c.Ref.Archive) > 0
+}
+
+// resolveChart returns the chart to deploy for the VirtualCluster. Offline
+// sources take precedence, followed by the repository in the spec, the
+// offline chart directory of the manager and finally the vcluster chart in
+// the loft.sh repository.
+func (r *VirtualClusterReconciler) resolveChart(ctx context.Context, vcluster *corev1alpha1.VirtualCluster, class *corev1alpha1.VirtualClusterClass, version string) (*resolvedChart, error) {
+	ref := helm.ChartRef{
+		RepoURL: vclusterRepo,
+		Name:    vclusterChart,
+		Version: version,
+	}
+
+	source := r.chartFor(vcluster, class).Source
+	if source != nil && source.Name != "" {
+		ref.Name = source.Name
+	}
+
+	switch {
+	case source != nil && source.Offline != nil:
+		return r.resolveOfflineChart(ctx, vcluster, ref, source.Offline)
+	case (source == nil || source.RepoURL == "") && r.OfflineChartDir != "":
+		file := fmt.Sprintf("%s-%s.tgz", ref.Name, strings.TrimPrefix(version, "v"))
+		return r.resolveOfflineChart(ctx, vcluster, ref, &corev1alpha1.OfflineChartSource{File: file})
+	}
+
+	if source != nil && source.RepoURL != "" {
+		ref.RepoURL = source.RepoURL
+	}
+
+	creds, err := r.chartCredentials(ctx, vcluster, class)
+	if err != nil {
+		return nil, err
+	}
+	ref.Credentials = creds
+
+	chart := &resolvedChart{
+		Ref:        ref,
+		SourceType: corev1alpha1.ChartSourceRepository,
+		Location:   ref.String(),
+	}
+	if ref.IsOCI() {
+		chart.SourceType = corev1alpha1.ChartSourceOCI
+	}
+	return chart, nil
+}
+
+// chartCredentials loads the repository credentials from the Secret
+// referenced by the chart source, if any.
+func (r *VirtualClusterReconciler) chartCredentials(ctx context.Context, vcluster *corev1alpha1.VirtualCluster, class *corev1alpha1.VirtualClusterClass) (*helm.Credentials, error) {
+	logger := log.FromContext(ctx)
+
+	source := r.chartFor(vcluster, class).Source
+	if source == nil {
+		return nil, nil
+	}
+
+	if source.CredentialsSecretRef == nil {
+		if source.InsecureSkipTLSVerify || source.PlainHTTP {
+			return &helm.Credentials{
+				InsecureSkipTLSVerify: source.InsecureSkipTLSVerify,
+				PlainHTTP:             source.PlainHTTP,
+			}, nil
+		}
+		return nil, nil
+	}
+
+	secret := &corev1.Secret{}
+	key := client.ObjectKey{Namespace: vcluster.Namespace, Name: source.CredentialsSecretRef.Name}
+	if err := r.Get(ctx, key, secret); err != nil {
+		logger.Error(err, "Failed to get chart credentials Secret", "secret", key.Name)
+		return nil, fmt.Errorf("failed to get chart credentials Secret %s: %w", key.Name, err)
+	}
+
+	logger.Info("Using chart credentials from Secret", "secret", key.Name)
+	return &helm.Credentials{
+		Username:              string(secret.Data[corev1.BasicAuthUsernameKey]),
+		Password:              string(secret.Data[corev1.BasicAuthPasswordKey]),
+		CAData:                secret.Data[corev1.ServiceAccountRootCAKey],
+		CertData:              secret.Data[corev1.TLSCertKey],
+		KeyData:               secret.Data[corev1.TLSPrivateKeyKey],
+		InsecureSkipTLSVerify: source.InsecureSkipTLSVerify,
+		PlainHTTP:             source.PlainHTTP,
+	}, nil
+}
+
+// resolveOfflineChart reads the chart archive and values schema from a
+// ConfigMap, a Secret or the offline chart directory.
+func (r *VirtualClusterReconciler) resolveOfflineChart(ctx context.Context, vcluster *corev1alpha1.VirtualCluster, ref helm.ChartRef, offline *corev1alpha1.OfflineChartSource) (*resolvedChart, error) {
+	logger := log.FromContext(ctx)
+	chart := &resolvedChart{}
+
+	switch {
+	case offline.ConfigMapRef != nil:
+		configMap := &corev1.ConfigMap{}
+		key := client.ObjectKey{Namespace: vcluster.Namespace, Name: offline.ConfigMapRef.Name}
+		if err := r.Get(ctx, key, configMap); err != nil {
+			return nil, fmt.Errorf("failed to get chart ConfigMap %s: %w", key.Name, err)
+		}
+		ref.Archive = configMap.BinaryData[chartArchiveKey]
+		chart.Schema = configMap.Data[schemaKey]
+		chart.SourceType = corev1alpha1.ChartSourceConfigMap
+		chart.Location = fmt.Sprintf("configmap/%s", key.Name)
+
+	case offline.SecretRef != nil:
+		secret := &corev1.Secret{}
+		key := client.ObjectKey{Namespace: vcluster.Namespace, Name: offline.SecretRef.Name}
+		if err := r.Get(ctx, key, secret); err != nil {
+			return nil, fmt.Errorf("failed to get chart Secret %s: %w", key.Name, err)
+		}
+		ref.Archive = secret.Data[chartArchiveKey]
+		chart.Schema = string(secret.Data[schemaKey])
+		chart.SourceType = corev1alpha1.ChartSourceSecret
+		chart.Location = fmt.Sprintf("secret/%s", key.Name)
+
+	case offline.File != "":
+		if r.OfflineChartDir == "" {
+			return nil, fmt.Errorf("chart file %s requested but the operator has no offline chart directory", offline.File)
+		}
+		path := filepath.Join(r.OfflineChartDir, filepath.Base(offline.File))
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read chart file: %w", err)
+		}
+		ref.Archive = data
+		chart.SourceType = corev1alpha1.ChartSourceFile
+		chart.Location = path
+
+	default:
+		return nil, fmt.Errorf("offline chart source has no ConfigMap, Secret or file")
+	}
+
+	if len(ref.Archive) == 0 {
+		return nil, fmt.Errorf("%s has no %s key", chart.Location, chartArchiveKey)
+	}
+
+	chrt, err := loader.LoadArchive(bytes.NewReader(ref.Archive))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load chart archive from %s: %w", chart.Location, err)
+	}
+
+	// The archive is installed as is, so it has to be the requested version
+	if ref.Version != "" && !chartVersionMatches(ref.Version, chrt.Metadata.Version) {
+		return nil, &chartVersionMismatchError{
+			location:  chart.Location,
+			requested: ref.Version,
+			archived:  chrt.Metadata.Version,
+		}
+	}
+
+	// Fall back to the schema packaged with the chart
+	if chart.Schema == "" {
+		chart.Schema = string(chrt.Schema)
+	}
+
+	logger.Info("Using offline chart", "source", chart.SourceType, "location", chart.Location)
+	chart.Ref = ref
+	return chart, nil
+}
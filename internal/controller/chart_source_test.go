@@ -0,0 +1,289 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	corev1alpha1 "github.com/OpenVirtualCluster/openvirtualcluster-operator/api/v1alpha1"
+	"github.com/OpenVirtualCluster/openvirtualcluster-operator/internal/helm"
+)
+
+var _ = Describe("Chart Source", func() {
+	var (
+		ctx        context.Context
+		reconciler *VirtualClusterReconciler
+		vc         *corev1alpha1.VirtualCluster
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		credentials := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "chart-credentials",
+				Namespace: "default",
+			},
+			Data: map[string][]byte{
+				corev1.BasicAuthUsernameKey:    []byte("mirror-user"),
+				corev1.BasicAuthPasswordKey:    []byte("mirror-pass"),
+				corev1.ServiceAccountRootCAKey: []byte("ca-data"),
+			},
+		}
+
+		reconciler = NewTestReconciler(nil, nil, credentials)
+
+		vc = CreateTestVirtualCluster("chart-source-test", "default", "")
+	})
+
+	It("should default to the loft.sh repository", func() {
+		chart, err := reconciler.resolveChart(ctx, vc, nil, "v0.24.1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(chart.Ref).To(Equal(helm.ChartRef{
+			RepoURL: vclusterRepo,
+			Name:    vclusterChart,
+			Version: "v0.24.1",
+		}))
+		Expect(chart.SourceType).To(Equal(corev1alpha1.ChartSourceRepository))
+		Expect(chart.Location).To(Equal("https://charts.loft.sh/vcluster"))
+		Expect(chart.offline()).To(BeFalse())
+	})
+
+	It("should use the repository and chart name from the source", func() {
+		vc.Spec.Chart.Source = &corev1alpha1.ChartSource{
+			RepoURL: "oci://registry.example.com/charts",
+			Name:    "vcluster-mirror",
+		}
+
+		chart, err := reconciler.resolveChart(ctx, vc, nil, "v0.24.1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(chart.SourceType).To(Equal(corev1alpha1.ChartSourceOCI))
+		ref := chart.Ref
+		Expect(ref.IsOCI()).To(BeTrue())
+		Expect(ref.String()).To(Equal("oci://registry.example.com/charts/vcluster-mirror"))
+		Expect(ref.Credentials).To(BeNil())
+	})
+
+	It("should load credentials from the referenced Secret", func() {
+		vc.Spec.Chart.Source = &corev1alpha1.ChartSource{
+			RepoURL:              "https://mirror.example.com/charts",
+			CredentialsSecretRef: &corev1.LocalObjectReference{Name: "chart-credentials"},
+		}
+
+		chart, err := reconciler.resolveChart(ctx, vc, nil, "v0.24.1")
+		Expect(err).NotTo(HaveOccurred())
+		ref := chart.Ref
+		Expect(ref.Name).To(Equal(vclusterChart))
+		Expect(ref.Credentials).NotTo(BeNil())
+		Expect(ref.Credentials.Username).To(Equal("mirror-user"))
+		Expect(ref.Credentials.Password).To(Equal("mirror-pass"))
+		Expect(string(ref.Credentials.CAData)).To(Equal("ca-data"))
+	})
+
+	It("should fail when the credentials Secret is missing", func() {
+		vc.Spec.Chart.Source = &corev1alpha1.ChartSource{
+			RepoURL:              "https://mirror.example.com/charts",
+			CredentialsSecretRef: &corev1.LocalObjectReference{Name: "missing"},
+		}
+
+		_, err := reconciler.resolveChart(ctx, vc, nil, "v0.24.1")
+		Expect(err).To(HaveOccurred())
+	})
+
+	Context("offline", func() {
+		var archive []byte
+
+		BeforeEach(func() {
+			archive = packageTestChart(`{"type": "object"}`)
+		})
+
+		It("should load the chart and schema from a ConfigMap", func() {
+			Expect(reconciler.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "vcluster-chart", Namespace: "default"},
+				Data:       map[string]string{schemaKey: `{"type": "object", "title": "override"}`},
+				BinaryData: map[string][]byte{chartArchiveKey: archive},
+			})).To(Succeed())
+			vc.Spec.Chart.Source = &corev1alpha1.ChartSource{
+				Offline: &corev1alpha1.OfflineChartSource{
+					ConfigMapRef: &corev1.LocalObjectReference{Name: "vcluster-chart"},
+				},
+			}
+
+			chart, err := reconciler.resolveChart(ctx, vc, nil, "v0.24.1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(chart.offline()).To(BeTrue())
+			Expect(chart.SourceType).To(Equal(corev1alpha1.ChartSourceConfigMap))
+			Expect(chart.Location).To(Equal("configmap/vcluster-chart"))
+			Expect(chart.Schema).To(ContainSubstring("override"))
+		})
+
+		It("should fall back to the schema packaged with a chart from a Secret", func() {
+			Expect(reconciler.Create(ctx, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "vcluster-chart", Namespace: "default"},
+				Data:       map[string][]byte{chartArchiveKey: archive},
+			})).To(Succeed())
+			vc.Spec.Chart.Source = &corev1alpha1.ChartSource{
+				Offline: &corev1alpha1.OfflineChartSource{
+					SecretRef: &corev1.LocalObjectReference{Name: "vcluster-chart"},
+				},
+			}
+
+			chart, err := reconciler.resolveChart(ctx, vc, nil, "v0.24.1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(chart.SourceType).To(Equal(corev1alpha1.ChartSourceSecret))
+			Expect(chart.Schema).To(Equal(`{"type": "object"}`))
+		})
+
+		It("should reject an archive of another chart version", func() {
+			Expect(reconciler.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "vcluster-chart", Namespace: "default"},
+				BinaryData: map[string][]byte{chartArchiveKey: archive},
+			})).To(Succeed())
+			vc.Spec.Chart.Source = &corev1alpha1.ChartSource{
+				Offline: &corev1alpha1.OfflineChartSource{
+					ConfigMapRef: &corev1.LocalObjectReference{Name: "vcluster-chart"},
+				},
+			}
+
+			_, err := reconciler.resolveChart(ctx, vc, nil, "v0.25.0")
+			Expect(err).To(MatchError(ContainSubstring("has version 0.24.1 but version v0.25.0 was requested")))
+			Expect(isChartVersionMismatch(err)).To(BeTrue())
+		})
+
+		It("should accept an archive satisfying a version constraint", func() {
+			Expect(reconciler.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "vcluster-chart", Namespace: "default"},
+				BinaryData: map[string][]byte{chartArchiveKey: archive},
+			})).To(Succeed())
+			vc.Spec.Chart.Source = &corev1alpha1.ChartSource{
+				Offline: &corev1alpha1.OfflineChartSource{
+					ConfigMapRef: &corev1.LocalObjectReference{Name: "vcluster-chart"},
+				},
+			}
+
+			_, err := reconciler.resolveChart(ctx, vc, nil, "~0.24.0")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should fail the VirtualCluster when the archive has another chart version", func() {
+			engine := &fakeReleaseEngine{}
+			recorder := record.NewFakeRecorder(10)
+			vc.Finalizers = []string{vclusterFinalizer}
+			vc.Status.Phase = corev1alpha1.VirtualClusterProvisioning
+			vc.Spec.Chart.Version = "v0.25.0"
+			vc.Spec.Chart.Source = &corev1alpha1.ChartSource{
+				Offline: &corev1alpha1.OfflineChartSource{
+					ConfigMapRef: &corev1.LocalObjectReference{Name: "vcluster-chart"},
+				},
+			}
+			reconciler = NewTestReconciler(engine, recorder, vc, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "vcluster-chart", Namespace: "default"},
+				BinaryData: map[string][]byte{chartArchiveKey: archive},
+			})
+
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(vc)})
+			Expect(err).To(HaveOccurred())
+			Expect(engine.installed).To(BeEmpty())
+			Expect(recorder.Events).To(Receive(ContainSubstring("Warning ChartVersionMismatch")))
+
+			updated := &corev1alpha1.VirtualCluster{}
+			Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(vc), updated)).To(Succeed())
+			Expect(updated.Status.Phase).To(Equal(corev1alpha1.VirtualClusterFailed))
+			cond := meta.FindStatusCondition(updated.Status.Conditions, VirtualClusterConditionError)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Reason).To(Equal("ChartVersionMismatch"))
+		})
+
+		It("should fail when the object has no chart archive", func() {
+			Expect(reconciler.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "empty", Namespace: "default"},
+			})).To(Succeed())
+			vc.Spec.Chart.Source = &corev1alpha1.ChartSource{
+				Offline: &corev1alpha1.OfflineChartSource{
+					ConfigMapRef: &corev1.LocalObjectReference{Name: "empty"},
+				},
+			}
+
+			_, err := reconciler.resolveChart(ctx, vc, nil, "v0.24.1")
+			Expect(err).To(MatchError(ContainSubstring(chartArchiveKey)))
+		})
+
+		It("should default to the offline chart directory", func() {
+			dir := GinkgoT().TempDir()
+			Expect(os.WriteFile(filepath.Join(dir, "vcluster-0.24.1.tgz"), archive, 0600)).To(Succeed())
+			reconciler.OfflineChartDir = dir
+
+			chart, err := reconciler.resolveChart(ctx, vc, nil, "v0.24.1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(chart.SourceType).To(Equal(corev1alpha1.ChartSourceFile))
+			Expect(chart.Location).To(Equal(filepath.Join(dir, "vcluster-0.24.1.tgz")))
+			Expect(chart.Ref.Archive).To(Equal(archive))
+		})
+
+		It("should prefer an explicit repository over the offline chart directory", func() {
+			reconciler.OfflineChartDir = GinkgoT().TempDir()
+			vc.Spec.Chart.Source = &corev1alpha1.ChartSource{RepoURL: "https://mirror.example.com/charts"}
+
+			chart, err := reconciler.resolveChart(ctx, vc, nil, "v0.24.1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(chart.SourceType).To(Equal(corev1alpha1.ChartSourceRepository))
+		})
+
+		It("should reject a chart file without an offline chart directory", func() {
+			vc.Spec.Chart.Source = &corev1alpha1.ChartSource{
+				Offline: &corev1alpha1.OfflineChartSource{File: "vcluster-0.24.1.tgz"},
+			}
+
+			_, err := reconciler.resolveChart(ctx, vc, nil, "v0.24.1")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
+
+// packageTestChart returns a packaged chart with the given values schema, or
+// without a schema if it is empty.
+func packageTestChart(schema string) []byte {
+	chrt := &chart.Chart{
+		Metadata: &chart.Metadata{
+			APIVersion: chart.APIVersionV2,
+			Name:       vclusterChart,
+			Version:    "0.24.1",
+		},
+	}
+	if schema != "" {
+		chrt.Schema = []byte(schema)
+	}
+
+	path, err := chartutil.Save(chrt, GinkgoT().TempDir())
+	Expect(err).NotTo(HaveOccurred())
+	data, err := os.ReadFile(path)
+	Expect(err).NotTo(HaveOccurred())
+	return data
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	corev1alpha1 "github.com/OpenVirtualCluster/openvirtualcluster-operator/api/v1alpha1"
+)
+
+// classRefIndex is the field index of the VirtualClusterClass referenced by
+// spec.classRef
+const classRefIndex = ".spec.classRef.name"
+
+// resolveClass gets the VirtualClusterClass referenced by the VirtualCluster,
+// or nil if it doesn't reference one. The class isn't merged into the spec,
+// as status updates return the stored spec, and is passed on instead.
+func (r *VirtualClusterReconciler) resolveClass(ctx context.Context, vcluster *corev1alpha1.VirtualCluster) (*corev1alpha1.VirtualClusterClass, error) {
+	if vcluster.Spec.ClassRef == nil {
+		return nil, nil
+	}
+
+	class := &corev1alpha1.VirtualClusterClass{}
+	if err := r.Get(ctx, client.ObjectKey{Name: vcluster.Spec.ClassRef.Name}, class); err != nil {
+		return nil, fmt.Errorf("failed to get VirtualClusterClass %s: %w", vcluster.Spec.ClassRef.Name, err)
+	}
+	return class, nil
+}
+
+// chartFor returns the chart of the VirtualCluster. Its version and source
+// fall back to the ones of its class.
+func (r *VirtualClusterReconciler) chartFor(vcluster *corev1alpha1.VirtualCluster, class *corev1alpha1.VirtualClusterClass) corev1alpha1.HelmChart {
+	chart := vcluster.Spec.Chart
+	if class != nil {
+		if chart.Version == "" {
+			chart.Version = class.Spec.Chart.Version
+		}
+		if chart.Source == nil {
+			chart.Source = class.Spec.Chart.Source
+		}
+	}
+	return chart
+}
+
+// classRefIndexer indexes VirtualClusters by the name of their class.
+func classRefIndexer(obj client.Object) []string {
+	vcluster, ok := obj.(*corev1alpha1.VirtualCluster)
+	if !ok || vcluster.Spec.ClassRef == nil {
+		return nil
+	}
+	return []string{vcluster.Spec.ClassRef.Name}
+}
+
+// requestsForClass maps a VirtualClusterClass to the VirtualClusters
+// referencing it in any namespace.
+func (r *VirtualClusterReconciler) requestsForClass(ctx context.Context, obj client.Object) []reconcile.Request {
+	vclusters := &corev1alpha1.VirtualClusterList{}
+	if err := r.List(ctx, vclusters, client.MatchingFields{classRefIndex: obj.GetName()}); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list VirtualClusters referencing class", "name", obj.GetName())
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(vclusters.Items))
+	for _, vcluster := range vclusters.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: client.ObjectKeyFromObject(&vcluster),
+		})
+	}
+	return requests
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	corev1alpha1 "github.com/OpenVirtualCluster/openvirtualcluster-operator/api/v1alpha1"
+)
+
+var _ = Describe("VirtualClusterClass", func() {
+	var (
+		ctx        context.Context
+		engine     *fakeReleaseEngine
+		recorder   *record.FakeRecorder
+		reconciler *VirtualClusterReconciler
+		class      *corev1alpha1.VirtualClusterClass
+		vc         *corev1alpha1.VirtualCluster
+	)
+
+	newReconciler := func(objs ...client.Object) {
+		reconciler = NewTestReconciler(engine, recorder, append(objs, vc)...)
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		engine = &fakeReleaseEngine{}
+		recorder = record.NewFakeRecorder(10)
+
+		class = &corev1alpha1.VirtualClusterClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "small"},
+			Spec: corev1alpha1.VirtualClusterClassSpec{
+				Chart: corev1alpha1.HelmChart{Version: "v0.25.0"},
+				Values: &apiextensionsv1.JSON{Raw: []byte(
+					`{"sync": {"toHost": {"ingresses": {"enabled": true}}}, "exportKubeConfig": {"context": "class", "server": "https://class"}}`,
+				)},
+				Validation:     &corev1alpha1.ValidationSpec{Mode: corev1alpha1.ValidationModeDisabled},
+				Drift:          &corev1alpha1.DriftSpec{SelfHeal: ptr.To(false)},
+				DeletionPolicy: corev1alpha1.DeletionPolicyRetain,
+			},
+		}
+
+		vc = CreateTestVirtualCluster("class-test", "default", `{"exportKubeConfig": {"context": "vc"}}`)
+		vc.UID = "class-test-uid"
+		vc.Spec.Chart.Version = ""
+		vc.Spec.ClassRef = &corev1alpha1.ClassReference{Name: "small"}
+	})
+
+	It("should merge the values of the VirtualCluster on top of the class", func() {
+		newReconciler(class)
+		resolved, err := reconciler.resolveClass(ctx, vc)
+		Expect(err).NotTo(HaveOccurred())
+
+		values, err := reconciler.composeValues(ctx, vc, resolved)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(values).To(HaveKeyWithValue("exportKubeConfig", map[string]interface{}{
+			"context": "vc",
+			"server":  "https://class",
+		}))
+		Expect(values).To(HaveKeyWithValue("sync", HaveKeyWithValue("toHost", HaveKeyWithValue("ingresses", HaveKeyWithValue("enabled", true)))))
+	})
+
+	It("should fall back to the chart and policies of the class", func() {
+		newReconciler(class)
+		resolved, err := reconciler.resolveClass(ctx, vc)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(reconciler.chartVersionFor(vc, resolved)).To(Equal("v0.25.0"))
+		Expect(reconciler.validationMode(vc, resolved)).To(Equal(corev1alpha1.ValidationModeDisabled))
+		Expect(reconciler.selfHeal(vc, resolved)).To(BeFalse())
+		Expect(reconciler.deletionPolicy(vc, resolved)).To(Equal(corev1alpha1.DeletionPolicyRetain))
+
+		// Settings of the VirtualCluster take precedence
+		vc.Spec.Chart.Version = "v0.24.1"
+		vc.Spec.DeletionPolicy = corev1alpha1.DeletionPolicyDelete
+		Expect(reconciler.chartVersionFor(vc, resolved)).To(Equal("v0.24.1"))
+		Expect(reconciler.deletionPolicy(vc, resolved)).To(Equal(corev1alpha1.DeletionPolicyDelete))
+	})
+
+	It("should deploy the chart version of the class", func() {
+		newReconciler(class)
+		resolved, err := reconciler.resolveClass(ctx, vc)
+		Expect(err).NotTo(HaveOccurred())
+
+		values, err := reconciler.translateValues(ctx, vc, resolved)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(reconciler.installOrUpgradeVCluster(ctx, vc, resolved, values)).To(Succeed())
+		Expect(engine.installed).To(HaveLen(1))
+		Expect(engine.installed[0].Chart.Version).To(Equal("v0.25.0"))
+		Expect(engine.installed[0].Values).To(HaveKeyWithValue("exportKubeConfig", HaveKeyWithValue("context", "vc")))
+	})
+
+	It("should fail the VirtualCluster while its class doesn't exist", func() {
+		vc.Finalizers = []string{vclusterFinalizer}
+		vc.Status.Phase = corev1alpha1.VirtualClusterPending
+		newReconciler()
+
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(vc)})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(engine.installed).To(BeEmpty())
+		Expect(recorder.Events).To(Receive(ContainSubstring("Warning ClassNotFound")))
+
+		updated := &corev1alpha1.VirtualCluster{}
+		Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(vc), updated)).To(Succeed())
+		Expect(updated.Status.Phase).To(Equal(corev1alpha1.VirtualClusterFailed))
+		cond := meta.FindStatusCondition(updated.Status.Conditions, VirtualClusterConditionError)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Reason).To(Equal("ClassNotFound"))
+	})
+
+	It("should record the deletion policy of the class in the status", func() {
+		vc.Finalizers = []string{vclusterFinalizer}
+		vc.Status.Phase = corev1alpha1.VirtualClusterProvisioning
+		newReconciler(class)
+
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(vc)})
+		Expect(err).NotTo(HaveOccurred())
+
+		updated := &corev1alpha1.VirtualCluster{}
+		Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(vc), updated)).To(Succeed())
+		Expect(updated.Status.DeletionPolicy).To(Equal(corev1alpha1.DeletionPolicyRetain))
+	})
+
+	It("should not finalize without a deletion policy once the class is gone", func() {
+		vc.Finalizers = []string{vclusterFinalizer}
+		vc.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+		vc.Status.Phase = corev1alpha1.VirtualClusterRunning
+		newReconciler()
+
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(vc)})
+		Expect(err).To(HaveOccurred())
+		Expect(engine.uninstalled).To(BeEmpty())
+		Expect(recorder.Events).To(Receive(ContainSubstring("Warning ClassNotFound")))
+
+		updated := &corev1alpha1.VirtualCluster{}
+		Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(vc), updated)).To(Succeed())
+		Expect(updated.Finalizers).To(ContainElement(vclusterFinalizer))
+	})
+
+	It("should finalize with the recorded deletion policy once the class is gone", func() {
+		vc.Finalizers = []string{vclusterFinalizer}
+		vc.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+		vc.Status.Phase = corev1alpha1.VirtualClusterRunning
+		vc.Status.DeletionPolicy = corev1alpha1.DeletionPolicyOrphan
+		newReconciler()
+
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(vc)})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(engine.uninstalled).To(BeEmpty())
+
+		err = reconciler.Get(ctx, client.ObjectKeyFromObject(vc), &corev1alpha1.VirtualCluster{})
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should map a class to the VirtualClusters referencing it", func() {
+		other := CreateTestVirtualCluster("other", "tenant-a", "")
+		other.Spec.ClassRef = &corev1alpha1.ClassReference{Name: "small"}
+		unrelated := CreateTestVirtualCluster("unrelated", "default", "")
+		newReconciler(class, other, unrelated)
+
+		Expect(reconciler.requestsForClass(ctx, class)).To(ConsistOf(
+			reconcile.Request{NamespacedName: client.ObjectKeyFromObject(vc)},
+			reconcile.Request{NamespacedName: client.ObjectKeyFromObject(other)},
+		))
+	})
+})
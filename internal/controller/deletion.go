@@ -0,0 +1,243 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	corev1alpha1 "github.com/OpenVirtualCluster/openvirtualcluster-operator/api/v1alpha1"
+)
+
+const (
+	// forceDeleteAnnotation removes the finalizer of a deleted VirtualCluster
+	// without waiting for its resources to be gone, when set to "true"
+	forceDeleteAnnotation = "core.openvc.dev/force-delete"
+
+	// deletionRequeueInterval is how often the resources of a deleted
+	// VirtualCluster are checked until they are gone
+	deletionRequeueInterval = 5 * time.Second
+
+	// managedByLabel is set by the vcluster syncer on the objects it syncs
+	// into the host cluster
+	managedByLabel = "vcluster.loft.sh/managed-by"
+)
+
+// deletionPolicy returns the deletion policy of the VirtualCluster or its
+// class, defaulting to Delete.
+func (r *VirtualClusterReconciler) deletionPolicy(vcluster *corev1alpha1.VirtualCluster, class *corev1alpha1.VirtualClusterClass) corev1alpha1.DeletionPolicy {
+	if vcluster.Spec.DeletionPolicy != "" {
+		return vcluster.Spec.DeletionPolicy
+	}
+	if class != nil && class.Spec.DeletionPolicy != "" {
+		return class.Spec.DeletionPolicy
+	}
+	return corev1alpha1.DeletionPolicyDelete
+}
+
+// recordedDeletionPolicy returns the deletion policy of the VirtualCluster,
+// falling back to the one recorded in the status while its class was
+// resolvable. The class itself may be gone by the time the VirtualCluster is
+// deleted.
+func recordedDeletionPolicy(vcluster *corev1alpha1.VirtualCluster) corev1alpha1.DeletionPolicy {
+	if vcluster.Spec.DeletionPolicy != "" {
+		return vcluster.Spec.DeletionPolicy
+	}
+	if vcluster.Status.DeletionPolicy != "" {
+		return vcluster.Status.DeletionPolicy
+	}
+	return corev1alpha1.DeletionPolicyDelete
+}
+
+// forceDelete reports whether the VirtualCluster is annotated to be removed
+// without waiting for its resources.
+func forceDelete(vcluster *corev1alpha1.VirtualCluster) bool {
+	return vcluster.Annotations[forceDeleteAnnotation] == "true"
+}
+
+// controlPlaneLabels returns the labels of the control plane pods of the
+// VirtualCluster.
+func controlPlaneLabels(vcluster *corev1alpha1.VirtualCluster) client.MatchingLabels {
+	return client.MatchingLabels{"app": "vcluster", "release": vcluster.Name}
+}
+
+// isReleaseVolumeClaim reports whether the PVC stores data of the
+// VirtualCluster: the volumes of the control plane StatefulSet, created from
+// its "data" volume claim template, and the volumes synced from the virtual
+// cluster.
+func isReleaseVolumeClaim(vcluster *corev1alpha1.VirtualCluster, pvc *corev1.PersistentVolumeClaim) bool {
+	if pvc.Labels[managedByLabel] == vcluster.Name {
+		return true
+	}
+	if pvc.Labels["app"] == "vcluster" && pvc.Labels["release"] == vcluster.Name {
+		return true
+	}
+	ordinal, ok := strings.CutPrefix(pvc.Name, "data-"+vcluster.Name+"-")
+	_, err := strconv.Atoi(ordinal)
+	return ok && err == nil
+}
+
+// releaseVolumeClaims returns the PVCs storing data of the VirtualCluster.
+func (r *VirtualClusterReconciler) releaseVolumeClaims(ctx context.Context, vcluster *corev1alpha1.VirtualCluster) ([]corev1.PersistentVolumeClaim, error) {
+	pvcs := &corev1.PersistentVolumeClaimList{}
+	if err := r.List(ctx, pvcs, client.InNamespace(vcluster.ReleaseNamespace())); err != nil {
+		return nil, fmt.Errorf("failed to list PersistentVolumeClaims: %w", err)
+	}
+
+	var claims []corev1.PersistentVolumeClaim
+	for _, pvc := range pvcs.Items {
+		if isReleaseVolumeClaim(vcluster, &pvc) {
+			claims = append(claims, pvc)
+		}
+	}
+	return claims, nil
+}
+
+// createdNamespace returns the release namespace if the operator created it
+// for the VirtualCluster, or nil. Namespaces that existed before, including
+// the namespace of the VirtualCluster itself, are never deleted.
+func (r *VirtualClusterReconciler) createdNamespace(ctx context.Context, vcluster *corev1alpha1.VirtualCluster) (*corev1.Namespace, error) {
+	name := vcluster.ReleaseNamespace()
+	if name == vcluster.Namespace {
+		return nil, nil
+	}
+
+	ns := &corev1.Namespace{}
+	if err := r.Get(ctx, client.ObjectKey{Name: name}, ns); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get namespace %s: %w", name, err)
+	}
+	if !isNamespaceOf(ns, vcluster) {
+		return nil, nil
+	}
+	return ns, nil
+}
+
+// deleteReleaseData deletes the PVCs and the namespace created for the
+// VirtualCluster, which Helm leaves behind when uninstalling the release.
+func (r *VirtualClusterReconciler) deleteReleaseData(ctx context.Context, vcluster *corev1alpha1.VirtualCluster) error {
+	logger := log.FromContext(ctx)
+
+	claims, err := r.releaseVolumeClaims(ctx, vcluster)
+	if err != nil {
+		return err
+	}
+	for i := range claims {
+		if !claims[i].DeletionTimestamp.IsZero() {
+			continue
+		}
+		if err := r.Delete(ctx, &claims[i]); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete PersistentVolumeClaim %s: %w", claims[i].Name, err)
+		}
+		logger.Info("Deleted PersistentVolumeClaim", "name", claims[i].Name, "namespace", claims[i].Namespace)
+	}
+
+	ns, err := r.createdNamespace(ctx, vcluster)
+	if err != nil || ns == nil || !ns.DeletionTimestamp.IsZero() {
+		return err
+	}
+	if err := r.Delete(ctx, ns); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("failed to delete namespace %s: %w", ns.Name, err)
+	}
+	logger.Info("Deleted namespace", "name", ns.Name)
+	return nil
+}
+
+// remainingResources returns the resources the deletion of the VirtualCluster
+// still waits for, as kind/name.
+func (r *VirtualClusterReconciler) remainingResources(ctx context.Context, vcluster *corev1alpha1.VirtualCluster) ([]string, error) {
+	policy := recordedDeletionPolicy(vcluster)
+	if policy == corev1alpha1.DeletionPolicyOrphan {
+		return nil, nil
+	}
+
+	var remaining []string
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(vcluster.ReleaseNamespace()), controlPlaneLabels(vcluster)); err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	for _, pod := range pods.Items {
+		remaining = append(remaining, "pod/"+pod.Name)
+	}
+
+	if policy != corev1alpha1.DeletionPolicyDelete {
+		return remaining, nil
+	}
+
+	claims, err := r.releaseVolumeClaims(ctx, vcluster)
+	if err != nil {
+		return nil, err
+	}
+	for _, pvc := range claims {
+		remaining = append(remaining, "persistentvolumeclaim/"+pvc.Name)
+	}
+
+	ns, err := r.createdNamespace(ctx, vcluster)
+	if err != nil {
+		return nil, err
+	}
+	if ns != nil {
+		remaining = append(remaining, "namespace/"+ns.Name)
+	}
+	return remaining, nil
+}
+
+// waitForDeletion keeps the finalizer until the remaining resources are gone.
+// Once the deletion takes longer than the deletion timeout, the VirtualCluster
+// reports an error, but keeps waiting until the resources are gone or it is
+// annotated to be force deleted.
+func (r *VirtualClusterReconciler) waitForDeletion(ctx context.Context, vcluster *corev1alpha1.VirtualCluster, remaining []string) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	logger.Info("Waiting for the resources of the VirtualCluster to be deleted", "remaining", remaining)
+
+	vcluster.Status.Message = fmt.Sprintf("Waiting for %s to be deleted", strings.Join(remaining, ", "))
+
+	waiting := time.Since(vcluster.DeletionTimestamp.Time)
+	if r.DeletionTimeout > 0 && waiting > r.DeletionTimeout {
+		message := fmt.Sprintf("Deletion did not complete within %s, still waiting for %s. Annotate the VirtualCluster with %s=true to remove it anyway",
+			r.DeletionTimeout, strings.Join(remaining, ", "), forceDeleteAnnotation)
+		if cond := meta.FindStatusCondition(vcluster.Status.Conditions, VirtualClusterConditionError); cond == nil || cond.Reason != "DeletionTimedOut" {
+			logger.Info("Deletion of the VirtualCluster timed out", "timeout", r.DeletionTimeout, "remaining", remaining)
+			r.Recorder.Event(vcluster, corev1.EventTypeWarning, "DeletionTimedOut", message)
+		}
+		meta.SetStatusCondition(&vcluster.Status.Conditions, metav1.Condition{
+			Type:    VirtualClusterConditionError,
+			Status:  metav1.ConditionTrue,
+			Reason:  "DeletionTimedOut",
+			Message: message,
+		})
+	}
+
+	if err := r.Status().Update(ctx, vcluster); err != nil {
+		logger.Error(err, "Failed to update VirtualCluster status")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: deletionRequeueInterval}, nil
+}
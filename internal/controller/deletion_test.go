@@ -0,0 +1,204 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	corev1alpha1 "github.com/OpenVirtualCluster/openvirtualcluster-operator/api/v1alpha1"
+)
+
+var _ = Describe("Deletion Policy", func() {
+	var (
+		ctx        context.Context
+		engine     *fakeReleaseEngine
+		recorder   *record.FakeRecorder
+		reconciler *VirtualClusterReconciler
+		vc         *corev1alpha1.VirtualCluster
+		objects    []client.Object
+	)
+
+	key := types.NamespacedName{Name: "deletion-test", Namespace: "default"}
+
+	pvc := func(name string, labels map[string]string) *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: key.Namespace, Labels: labels},
+		}
+	}
+
+	exists := func(obj client.Object, name string) bool {
+		err := reconciler.Get(ctx, types.NamespacedName{Name: name, Namespace: key.Namespace}, obj)
+		if errors.IsNotFound(err) {
+			return false
+		}
+		Expect(err).NotTo(HaveOccurred())
+		return true
+	}
+
+	// build creates the reconciler once the test has adjusted the VirtualCluster
+	build := func() {
+		reconciler = NewTestReconciler(engine, recorder, append(objects, vc)...)
+		reconciler.DeletionTimeout = 10 * time.Minute
+	}
+
+	reconcileDeletion := func() (reconcile.Result, error) {
+		return reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		engine = &fakeReleaseEngine{exists: true}
+		recorder = record.NewFakeRecorder(10)
+
+		vc = CreateTestVirtualCluster(key.Name, key.Namespace, "")
+		vc.Finalizers = []string{vclusterFinalizer}
+		vc.Status.Phase = corev1alpha1.VirtualClusterRunning
+		now := metav1.Now()
+		vc.DeletionTimestamp = &now
+
+		objects = []client.Object{
+			pvc("data-deletion-test-0", nil),
+			pvc("synced-volume", map[string]string{managedByLabel: key.Name}),
+			pvc("data-deletion-test-other-0", nil),
+			pvc("unrelated", map[string]string{"app": "database"}),
+		}
+	})
+
+	It("should delete the PVCs of the release by default", func() {
+		build()
+
+		result, err := reconcileDeletion()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeZero())
+		Expect(engine.uninstalled).To(ConsistOf("default/deletion-test"))
+
+		Expect(exists(&corev1.PersistentVolumeClaim{}, "data-deletion-test-0")).To(BeFalse())
+		Expect(exists(&corev1.PersistentVolumeClaim{}, "synced-volume")).To(BeFalse())
+		Expect(exists(&corev1.PersistentVolumeClaim{}, "data-deletion-test-other-0")).To(BeTrue())
+		Expect(exists(&corev1.PersistentVolumeClaim{}, "unrelated")).To(BeTrue())
+
+		// The finalizer is removed, so the VirtualCluster is gone
+		Expect(exists(&corev1alpha1.VirtualCluster{}, key.Name)).To(BeFalse())
+	})
+
+	It("should keep the PVCs when retained", func() {
+		vc.Spec.DeletionPolicy = corev1alpha1.DeletionPolicyRetain
+		build()
+
+		_, err := reconcileDeletion()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(engine.uninstalled).To(ConsistOf("default/deletion-test"))
+
+		Expect(exists(&corev1.PersistentVolumeClaim{}, "data-deletion-test-0")).To(BeTrue())
+		Expect(exists(&corev1.PersistentVolumeClaim{}, "synced-volume")).To(BeTrue())
+		Expect(exists(&corev1alpha1.VirtualCluster{}, key.Name)).To(BeFalse())
+	})
+
+	It("should leave everything in place when orphaned", func() {
+		vc.Spec.DeletionPolicy = corev1alpha1.DeletionPolicyOrphan
+		objects = append(objects, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name: "deletion-test-0", Namespace: key.Namespace, Labels: map[string]string{"app": "vcluster", "release": key.Name},
+		}})
+		build()
+
+		_, err := reconcileDeletion()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(engine.uninstalled).To(BeEmpty())
+
+		Expect(exists(&corev1.PersistentVolumeClaim{}, "data-deletion-test-0")).To(BeTrue())
+		Expect(exists(&corev1.Pod{}, "deletion-test-0")).To(BeTrue())
+		Expect(exists(&corev1alpha1.VirtualCluster{}, key.Name)).To(BeFalse())
+	})
+
+	Context("with a control plane pod that is still terminating", func() {
+		BeforeEach(func() {
+			objects = append(objects, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Name: "deletion-test-0", Namespace: key.Namespace, Labels: map[string]string{"app": "vcluster", "release": key.Name},
+			}})
+		})
+
+		It("should wait until it is gone", func() {
+			build()
+
+			result, err := reconcileDeletion()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(deletionRequeueInterval))
+
+			updated := &corev1alpha1.VirtualCluster{}
+			Expect(reconciler.Get(ctx, key, updated)).To(Succeed())
+			Expect(updated.Finalizers).To(ContainElement(vclusterFinalizer))
+			Expect(updated.Status.Phase).To(Equal(corev1alpha1.VirtualClusterDeleting))
+			Expect(updated.Status.Message).To(Equal("Waiting for pod/deletion-test-0 to be deleted"))
+			Expect(meta.FindStatusCondition(updated.Status.Conditions, VirtualClusterConditionError)).To(BeNil())
+
+			Expect(reconciler.Delete(ctx, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "deletion-test-0", Namespace: key.Namespace}})).To(Succeed())
+			result, err = reconcileDeletion()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeZero())
+			Expect(exists(&corev1alpha1.VirtualCluster{}, key.Name)).To(BeFalse())
+		})
+
+		It("should report a deletion that times out", func() {
+			deleted := metav1.NewTime(time.Now().Add(-time.Hour))
+			vc.DeletionTimestamp = &deleted
+			build()
+
+			result, err := reconcileDeletion()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(deletionRequeueInterval))
+
+			updated := &corev1alpha1.VirtualCluster{}
+			Expect(reconciler.Get(ctx, key, updated)).To(Succeed())
+			Expect(updated.Finalizers).To(ContainElement(vclusterFinalizer))
+			cond := meta.FindStatusCondition(updated.Status.Conditions, VirtualClusterConditionError)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Reason).To(Equal("DeletionTimedOut"))
+			Expect(cond.Message).To(ContainSubstring("pod/deletion-test-0"))
+			Expect(recorder.Events).To(Receive(ContainSubstring("DeletionTimedOut")))
+
+			// The event is only emitted once
+			_, err = reconcileDeletion()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).NotTo(Receive())
+		})
+
+		It("should remove a force deleted VirtualCluster without waiting", func() {
+			vc.Annotations = map[string]string{forceDeleteAnnotation: "true"}
+			engine.uninstallErr = fmt.Errorf("cluster unreachable")
+			build()
+
+			result, err := reconcileDeletion()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeZero())
+			Expect(exists(&corev1.Pod{}, "deletion-test-0")).To(BeTrue())
+			Expect(exists(&corev1alpha1.VirtualCluster{}, key.Name)).To(BeFalse())
+		})
+	})
+})
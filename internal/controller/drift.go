@@ -0,0 +1,199 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/release"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	corev1alpha1 "github.com/OpenVirtualCluster/openvirtualcluster-operator/api/v1alpha1"
+	"github.com/OpenVirtualCluster/openvirtualcluster-operator/internal/helm"
+)
+
+// Reasons of the Drifted condition
+const (
+	driftReasonInSync              = "InSync"
+	driftReasonSelfHealed          = "SelfHealed"
+	driftReasonReleaseMissing      = "ReleaseMissing"
+	driftReasonReleaseNotDeployed  = "ReleaseNotDeployed"
+	driftReasonChartVersionChanged = "ChartVersionChanged"
+	driftReasonValuesChanged       = "ValuesChanged"
+)
+
+// releaseDrift describes how the Helm release differs from the desired state.
+type releaseDrift struct {
+	Reason  string
+	Message string
+}
+
+// releaseHash returns the hash of a chart version and values. Versions are
+// compared without their "v" prefix since Helm drops it from the chart
+// metadata.
+func releaseHash(version string, values map[string]interface{}) (string, error) {
+	if values == nil {
+		values = map[string]interface{}{}
+	}
+
+	// encoding/json sorts map keys, so equal values always hash the same
+	data, err := json.Marshal(values)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal values: %w", err)
+	}
+
+	h := sha256.New()
+	h.Write([]byte(strings.TrimPrefix(version, "v")))
+	h.Write([]byte{0})
+	h.Write(data)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// selfHeal returns whether drift of the VirtualCluster is corrected, falling
+// back to its class and the default of the operator.
+func (r *VirtualClusterReconciler) selfHeal(vcluster *corev1alpha1.VirtualCluster, class *corev1alpha1.VirtualClusterClass) bool {
+	if vcluster.Spec.Drift != nil && vcluster.Spec.Drift.SelfHeal != nil {
+		return *vcluster.Spec.Drift.SelfHeal
+	}
+	if class != nil && class.Spec.Drift != nil && class.Spec.Drift.SelfHeal != nil {
+		return *class.Spec.Drift.SelfHeal
+	}
+	return r.DefaultSelfHeal
+}
+
+// releaseUpToDate reports whether the release already matches the spec of the
+// VirtualCluster, so the Helm operation, the chart source and schema
+// validation can be skipped. That is the case when the generation of the spec
+// was applied, the hash of the effective values and chart version is
+// unchanged and the release either didn't drift or drifted without self-heal,
+// in which case the drift is only reported. Anything else, including errors,
+// is left to the full reconcile, which reports it.
+func (r *VirtualClusterReconciler) releaseUpToDate(ctx context.Context, vcluster *corev1alpha1.VirtualCluster, class *corev1alpha1.VirtualClusterClass) bool {
+	logger := log.FromContext(ctx)
+
+	if vcluster.Spec.Rollback != nil || vcluster.Status.AppliedHash == "" ||
+		vcluster.Status.ObservedGeneration != vcluster.Generation {
+		return false
+	}
+
+	// Referenced ConfigMaps, Secrets and operator defaults don't change the
+	// generation, so the effective values are hashed again
+	values, err := r.translateValues(ctx, vcluster, class)
+	if err != nil {
+		return false
+	}
+	version := r.chartVersionFor(vcluster, class)
+	desiredHash, err := releaseHash(version, values)
+	if err != nil || desiredHash != vcluster.Status.AppliedHash {
+		return false
+	}
+
+	drift, err := r.detectDrift(ctx, vcluster, version, desiredHash)
+	if err != nil {
+		return false
+	}
+	if drift != nil {
+		if r.selfHeal(vcluster, class) {
+			return false
+		}
+		logger.Info("Release drifted, self-heal is disabled", "release", vcluster.Name, "reason", drift.Reason)
+		r.reportDrift(vcluster, drift)
+		return true
+	}
+
+	meta.SetStatusCondition(&vcluster.Status.Conditions, metav1.Condition{
+		Type:    VirtualClusterConditionDrifted,
+		Status:  metav1.ConditionFalse,
+		Reason:  driftReasonInSync,
+		Message: "Helm release matches the desired state",
+	})
+	logger.Info("Release is up to date, skipping Helm operation", "release", vcluster.Name, "generation", vcluster.Generation)
+	return true
+}
+
+// reportDrift sets the Drifted condition for drift that isn't corrected. The
+// DriftDetected event is only emitted when the drift is new or its reason
+// changed, not on every resync of a release that stays drifted.
+func (r *VirtualClusterReconciler) reportDrift(vcluster *corev1alpha1.VirtualCluster, drift *releaseDrift) {
+	previous := meta.FindStatusCondition(vcluster.Status.Conditions, VirtualClusterConditionDrifted)
+	if previous == nil || previous.Status != metav1.ConditionTrue || previous.Reason != drift.Reason {
+		r.Recorder.Event(vcluster, corev1.EventTypeWarning, "DriftDetected", drift.Message)
+	}
+
+	meta.SetStatusCondition(&vcluster.Status.Conditions, metav1.Condition{
+		Type:    VirtualClusterConditionDrifted,
+		Status:  metav1.ConditionTrue,
+		Reason:  drift.Reason,
+		Message: drift.Message,
+	})
+}
+
+// detectDrift compares the live Helm release against the desired chart
+// version and values. It returns nil if the release is in sync.
+func (r *VirtualClusterReconciler) detectDrift(ctx context.Context, vcluster *corev1alpha1.VirtualCluster, version string, desiredHash string) (*releaseDrift, error) {
+	rel, err := r.Helm.Get(ctx, vcluster.ReleaseNamespace(), vcluster.Name)
+	if helm.IsReleaseNotFound(err) {
+		return &releaseDrift{
+			Reason:  driftReasonReleaseMissing,
+			Message: "Helm release was removed",
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if rel.Info != nil && rel.Info.Status != release.StatusDeployed {
+		return &releaseDrift{
+			Reason:  driftReasonReleaseNotDeployed,
+			Message: fmt.Sprintf("Helm release revision %d is %s", rel.Version, rel.Info.Status),
+		}, nil
+	}
+
+	var liveVersion string
+	if rel.Chart != nil && rel.Chart.Metadata != nil {
+		liveVersion = rel.Chart.Metadata.Version
+	}
+	if !chartVersionMatches(version, liveVersion) {
+		return &releaseDrift{
+			Reason:  driftReasonChartVersionChanged,
+			Message: fmt.Sprintf("Helm release uses chart version %s instead of %s", liveVersion, version),
+		}, nil
+	}
+
+	// The live chart satisfies the desired version, which may be a
+	// constraint, so only the values are compared
+	liveHash, err := releaseHash(version, rel.Config)
+	if err != nil {
+		return nil, err
+	}
+	if liveHash != desiredHash {
+		return &releaseDrift{
+			Reason:  driftReasonValuesChanged,
+			Message: fmt.Sprintf("Values of Helm release revision %d differ from the desired values", rel.Version),
+		}, nil
+	}
+
+	return nil, nil
+}
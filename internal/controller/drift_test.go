@@ -0,0 +1,313 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/release"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	corev1alpha1 "github.com/OpenVirtualCluster/openvirtualcluster-operator/api/v1alpha1"
+)
+
+var _ = Describe("Drift Detection", func() {
+	var (
+		ctx        context.Context
+		engine     *fakeReleaseEngine
+		recorder   *record.FakeRecorder
+		reconciler *VirtualClusterReconciler
+		vc         *corev1alpha1.VirtualCluster
+		values     map[string]interface{}
+	)
+
+	driftedCondition := func() *metav1.Condition {
+		return meta.FindStatusCondition(vc.Status.Conditions, VirtualClusterConditionDrifted)
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		vc = CreateTestVirtualCluster("drift-test", "default", "")
+		vc.Finalizers = []string{vclusterFinalizer}
+		vc.Status.Phase = corev1alpha1.VirtualClusterProvisioning
+
+		schemaConfigMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "vcluster-schema-v0-24-1", Namespace: "default"},
+			Data:       map[string]string{"values.schema.json": `{"type": "object"}`},
+		}
+
+		engine = &fakeReleaseEngine{}
+		recorder = record.NewFakeRecorder(10)
+		reconciler = NewTestReconciler(engine, recorder, vc, schemaConfigMap)
+
+		var err error
+		values, err = reconciler.translateValues(ctx, vc, nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		// Deploy the release once so later calls compare against it
+		Expect(reconciler.installOrUpgradeVCluster(ctx, vc, nil, values)).To(Succeed())
+		Expect(engine.installed).To(HaveLen(1))
+		Expect(reconciler.Status().Update(ctx, vc)).To(Succeed())
+		engine.exists = true
+	})
+
+	It("should record the applied hash", func() {
+		Expect(vc.Status.AppliedHash).NotTo(BeEmpty())
+
+		cond := driftedCondition()
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+		Expect(cond.Reason).To(Equal("InSync"))
+	})
+
+	It("should not upgrade a release in sync", func() {
+		Expect(reconciler.installOrUpgradeVCluster(ctx, vc, nil, values)).To(Succeed())
+		Expect(engine.upgraded).To(BeEmpty())
+		Expect(driftedCondition().Reason).To(Equal("InSync"))
+	})
+
+	It("should upgrade when the desired state changes", func() {
+		vc.Spec.Chart.Version = "v0.25.0"
+
+		Expect(reconciler.installOrUpgradeVCluster(ctx, vc, nil, values)).To(Succeed())
+		Expect(engine.upgraded).To(HaveLen(1))
+		Expect(driftedCondition().Status).To(Equal(metav1.ConditionFalse))
+	})
+
+	It("should report drifted values without self-heal", func() {
+		engine.current.Config = map[string]interface{}{"edited": true}
+
+		Expect(reconciler.installOrUpgradeVCluster(ctx, vc, nil, values)).To(Succeed())
+		Expect(engine.upgraded).To(BeEmpty())
+
+		cond := driftedCondition()
+		Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+		Expect(cond.Reason).To(Equal("ValuesChanged"))
+		Expect(recorder.Events).To(Receive(ContainSubstring("DriftDetected")))
+	})
+
+	It("should only emit an event when the drift changes", func() {
+		engine.current.Config = map[string]interface{}{"edited": true}
+
+		Expect(reconciler.installOrUpgradeVCluster(ctx, vc, nil, values)).To(Succeed())
+		Expect(recorder.Events).To(Receive(ContainSubstring("DriftDetected")))
+
+		Expect(reconciler.installOrUpgradeVCluster(ctx, vc, nil, values)).To(Succeed())
+		Expect(recorder.Events).NotTo(Receive())
+		Expect(driftedCondition().Reason).To(Equal("ValuesChanged"))
+
+		engine.current.Chart.Metadata.Version = "0.23.0"
+
+		Expect(reconciler.installOrUpgradeVCluster(ctx, vc, nil, values)).To(Succeed())
+		Expect(recorder.Events).To(Receive(ContainSubstring("chart version 0.23.0")))
+		Expect(engine.upgraded).To(BeEmpty())
+	})
+
+	It("should report a changed chart version", func() {
+		engine.current.Chart.Metadata.Version = "0.23.0"
+
+		Expect(reconciler.installOrUpgradeVCluster(ctx, vc, nil, values)).To(Succeed())
+		Expect(driftedCondition().Reason).To(Equal("ChartVersionChanged"))
+	})
+
+	It("should keep a release satisfying a chart version constraint in sync", func() {
+		vc.Spec.Chart.Version = "~0.24.0"
+		Expect(reconciler.installOrUpgradeVCluster(ctx, vc, nil, values)).To(Succeed())
+		Expect(engine.upgraded).To(HaveLen(1))
+
+		// Helm resolves the constraint to a chart version
+		engine.current.Chart.Metadata.Version = "0.24.3"
+
+		Expect(reconciler.installOrUpgradeVCluster(ctx, vc, nil, values)).To(Succeed())
+		Expect(engine.upgraded).To(HaveLen(1))
+		Expect(driftedCondition().Reason).To(Equal("InSync"))
+
+		engine.current.Chart.Metadata.Version = "0.25.0"
+
+		Expect(reconciler.installOrUpgradeVCluster(ctx, vc, nil, values)).To(Succeed())
+		Expect(driftedCondition().Reason).To(Equal("ChartVersionChanged"))
+	})
+
+	It("should report a release that is not deployed", func() {
+		engine.current.Info.Status = release.StatusFailed
+
+		Expect(reconciler.installOrUpgradeVCluster(ctx, vc, nil, values)).To(Succeed())
+		Expect(driftedCondition().Reason).To(Equal("ReleaseNotDeployed"))
+	})
+
+	It("should re-apply drifted values with self-heal", func() {
+		vc.Spec.Drift = &corev1alpha1.DriftSpec{SelfHeal: ptr.To(true)}
+		Expect(reconciler.Update(ctx, vc)).To(Succeed())
+		engine.current.Config = map[string]interface{}{"edited": true}
+
+		Expect(reconciler.installOrUpgradeVCluster(ctx, vc, nil, values)).To(Succeed())
+		Expect(engine.upgraded).To(HaveLen(1))
+
+		cond := driftedCondition()
+		Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+		Expect(cond.Reason).To(Equal("SelfHealed"))
+		Expect(recorder.Events).To(Receive(ContainSubstring("DriftCorrected")))
+	})
+
+	It("should reinstall a removed release with the default self-heal", func() {
+		reconciler.DefaultSelfHeal = true
+		engine.exists = false
+		engine.current = nil
+
+		Expect(reconciler.installOrUpgradeVCluster(ctx, vc, nil, values)).To(Succeed())
+		Expect(engine.installed).To(HaveLen(2))
+		Expect(driftedCondition().Reason).To(Equal("SelfHealed"))
+	})
+
+	It("should prefer the self-heal setting of the VirtualCluster", func() {
+		reconciler.DefaultSelfHeal = true
+		Expect(reconciler.selfHeal(vc, nil)).To(BeTrue())
+
+		vc.Spec.Drift = &corev1alpha1.DriftSpec{SelfHeal: ptr.To(false)}
+		Expect(reconciler.selfHeal(vc, nil)).To(BeFalse())
+	})
+
+	It("should requeue running VirtualClusters after the resync interval", func() {
+		reconciler.ResyncInterval = 5 * time.Minute
+		for _, obj := range readyControlPlane("drift-test", "default") {
+			Expect(reconciler.Create(ctx, obj)).To(Succeed())
+		}
+
+		result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(vc)})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(5 * time.Minute))
+		Expect(engine.upgraded).To(BeEmpty())
+
+		updated := &corev1alpha1.VirtualCluster{}
+		Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(vc), updated)).To(Succeed())
+		Expect(updated.Status.Phase).To(Equal(corev1alpha1.VirtualClusterRunning))
+		Expect(updated.Status.AppliedHash).To(Equal(vc.Status.AppliedHash))
+	})
+
+	Context("with an applied generation", func() {
+		reconcileVirtualCluster := func() *corev1alpha1.VirtualCluster {
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(vc)})
+			Expect(err).NotTo(HaveOccurred())
+
+			updated := &corev1alpha1.VirtualCluster{}
+			Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(vc), updated)).To(Succeed())
+			return updated
+		}
+
+		BeforeEach(func() {
+			for _, obj := range readyControlPlane("drift-test", "default") {
+				Expect(reconciler.Create(ctx, obj)).To(Succeed())
+			}
+
+			// The fake client doesn't track generations
+			vc.Generation = 3
+			Expect(reconciler.Update(ctx, vc)).To(Succeed())
+			Expect(reconcileVirtualCluster().Status.ObservedGeneration).To(Equal(int64(3)))
+		})
+
+		It("should skip the Helm operation", func() {
+			updated := reconcileVirtualCluster()
+			Expect(updated.Status.ObservedGeneration).To(Equal(int64(3)))
+			Expect(meta.FindStatusCondition(updated.Status.Conditions, VirtualClusterConditionDrifted).Reason).To(Equal("InSync"))
+			Expect(engine.upgraded).To(BeEmpty())
+		})
+
+		It("should report drift without preparing an upgrade when self-heal is off", func() {
+			// Resolving this chart source fails, so it must not be needed
+			updated := reconcileVirtualCluster()
+			updated.Spec.Chart.Source = &corev1alpha1.ChartSource{
+				Offline: &corev1alpha1.OfflineChartSource{
+					ConfigMapRef: &corev1.LocalObjectReference{Name: "missing"},
+				},
+			}
+			Expect(reconciler.Update(ctx, updated)).To(Succeed())
+			engine.current.Config = map[string]interface{}{"edited": true}
+			for len(recorder.Events) > 0 {
+				<-recorder.Events
+			}
+
+			updated = reconcileVirtualCluster()
+			cond := meta.FindStatusCondition(updated.Status.Conditions, VirtualClusterConditionDrifted)
+			Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+			Expect(cond.Reason).To(Equal("ValuesChanged"))
+			Expect(engine.upgraded).To(BeEmpty())
+			Expect(recorder.Events).To(Receive(ContainSubstring("DriftDetected")))
+
+			reconcileVirtualCluster()
+			Expect(recorder.Events).NotTo(Receive(ContainSubstring("DriftDetected")))
+		})
+
+		It("should reconcile a new generation without upgrading unchanged values", func() {
+			updated := reconcileVirtualCluster()
+			updated.Generation = 4
+			updated.Spec.UpgradePolicy = &corev1alpha1.UpgradePolicy{Wait: true}
+			Expect(reconciler.Update(ctx, updated)).To(Succeed())
+
+			Expect(reconcileVirtualCluster().Status.ObservedGeneration).To(Equal(int64(4)))
+			Expect(engine.upgraded).To(BeEmpty())
+		})
+
+		It("should upgrade when the effective values change", func() {
+			reconciler.DefaultValues = map[string]interface{}{"sync": map[string]interface{}{"toHost": map[string]interface{}{"ingresses": map[string]interface{}{"enabled": true}}}}
+
+			reconcileVirtualCluster()
+			Expect(engine.upgraded).To(HaveLen(1))
+		})
+
+		It("should check a drifted release in full", func() {
+			engine.current.Info.Status = release.StatusFailed
+
+			updated := reconcileVirtualCluster()
+			Expect(meta.FindStatusCondition(updated.Status.Conditions, VirtualClusterConditionDrifted).Reason).
+				To(Equal("ReleaseNotDeployed"))
+		})
+	})
+})
+
+var _ = Describe("releaseHash", func() {
+	It("should ignore the v prefix of the version", func() {
+		values := map[string]interface{}{"a": 1, "b": map[string]interface{}{"c": "d"}}
+
+		withPrefix, err := releaseHash("v0.24.1", values)
+		Expect(err).NotTo(HaveOccurred())
+		withoutPrefix, err := releaseHash("0.24.1", values)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(withPrefix).To(Equal(withoutPrefix))
+
+		other, err := releaseHash("0.24.2", values)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(other).NotTo(Equal(withPrefix))
+	})
+
+	It("should treat missing values as empty", func() {
+		empty, err := releaseHash("0.24.1", map[string]interface{}{})
+		Expect(err).NotTo(HaveOccurred())
+		missing, err := releaseHash("0.24.1", nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(missing).To(Equal(empty))
+	})
+})
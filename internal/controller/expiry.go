@@ -0,0 +1,274 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/common/expfmt"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	corev1alpha1 "github.com/OpenVirtualCluster/openvirtualcluster-operator/api/v1alpha1"
+)
+
+const (
+	// expiryWarningPeriod is how long before its expiry a VirtualCluster
+	// warns that it is about to be deleted
+	expiryWarningPeriod = 10 * time.Minute
+
+	// activityProbeInterval is how often the API server of a VirtualCluster
+	// with an idle timeout is probed for activity
+	activityProbeInterval = time.Minute
+
+	// apiServerRequestsMetric counts the requests served by an API server
+	apiServerRequestsMetric = "apiserver_request_total"
+)
+
+// idleResources are resources the control plane keeps writing on its own, so
+// requests for them don't count as activity.
+var idleResources = map[string]bool{"leases": true, "events": true}
+
+// ActivityProber reads the activity of the API server of a virtual cluster.
+type ActivityProber interface {
+	// Requests returns the number of requests the API server at url served
+	// for clients, authenticating with the kubeconfig.
+	Requests(ctx context.Context, url string, kubeconfig []byte) (float64, error)
+}
+
+// metricsActivityProber counts the requests in the metrics of API servers.
+type metricsActivityProber struct {
+	timeout time.Duration
+}
+
+// NewActivityProber returns a prober reading the /metrics endpoint of API
+// servers with the credentials of their kubeconfig.
+func NewActivityProber(timeout time.Duration) ActivityProber {
+	return &metricsActivityProber{timeout: timeout}
+}
+
+func (p *metricsActivityProber) Requests(ctx context.Context, url string, kubeconfig []byte) (float64, error) {
+	config, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	config.Host = url
+	config.Timeout = p.timeout
+
+	httpClient, err := rest.HTTPClientFor(config)
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url+"/metrics", nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 256))
+		return 0, fmt.Errorf("HTTP status code %d: %s", resp.StatusCode, body)
+	}
+	return clientRequests(resp.Body)
+}
+
+// clientRequests sums the requests of the apiserver_request_total metric
+// that indicate someone uses the virtual cluster. Watches, requests for
+// non-resource URLs like the metrics themselves and requests for resources
+// the control plane writes on its own are left out.
+func clientRequests(metrics io.Reader) (float64, error) {
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(metrics)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse metrics: %w", err)
+	}
+
+	family, ok := families[apiServerRequestsMetric]
+	if !ok {
+		return 0, fmt.Errorf("metric %s not found", apiServerRequestsMetric)
+	}
+
+	var total float64
+	for _, metric := range family.GetMetric() {
+		labels := map[string]string{}
+		for _, label := range metric.GetLabel() {
+			labels[label.GetName()] = label.GetValue()
+		}
+		if labels["verb"] == "WATCH" || labels["resource"] == "" || idleResources[labels["resource"]] {
+			continue
+		}
+		total += metric.GetCounter().GetValue()
+	}
+	return total, nil
+}
+
+// observeActivity reports whether the API server of a running VirtualCluster
+// served requests since it was last probed. The request counts are only kept
+// in memory, so the first probe after the operator started records the count
+// without reporting activity.
+func (r *VirtualClusterReconciler) observeActivity(ctx context.Context, vcluster *corev1alpha1.VirtualCluster) (bool, error) {
+	if vcluster.Status.Phase != corev1alpha1.VirtualClusterRunning {
+		return false, nil
+	}
+
+	key := client.ObjectKey{Namespace: vcluster.ReleaseNamespace(), Name: vcluster.Name}
+	service := &corev1.Service{}
+	if err := r.Get(ctx, key, service); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	secret := &corev1.Secret{}
+	key.Name = kubeconfigSourceSecretName(vcluster)
+	if err := r.Get(ctx, key, secret); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+
+	requests, err := r.ActivityProber.Requests(ctx, apiServerURL(service), secret.Data[defaultKubeconfigKey])
+	if err != nil {
+		return false, err
+	}
+
+	// The count drops when the API server restarts, which isn't activity
+	previous, seen := r.requestCounts.Swap(vcluster.UID, requests)
+	return seen && requests > previous.(float64), nil
+}
+
+// expiryOf returns the time the VirtualCluster expires at and what it expires
+// by, or a zero time if it doesn't expire. The idle timeout only applies once
+// the activity of the API server is probed.
+func (r *VirtualClusterReconciler) expiryOf(vcluster *corev1alpha1.VirtualCluster) (time.Time, string) {
+	var expiresAt time.Time
+	var cause string
+	earliest := func(t time.Time, c string) {
+		if expiresAt.IsZero() || t.Before(expiresAt) {
+			expiresAt, cause = t, c
+		}
+	}
+
+	spec := vcluster.Spec
+	if spec.TTL != nil {
+		earliest(vcluster.CreationTimestamp.Add(spec.TTL.Duration), fmt.Sprintf("TTL of %s", spec.TTL.Duration))
+	}
+	if spec.ExpiresAt != nil {
+		earliest(spec.ExpiresAt.Time, "spec.expiresAt")
+	}
+	if spec.IdleTimeout != nil && r.ActivityProber != nil && vcluster.Status.LastActivityTime != nil {
+		earliest(vcluster.Status.LastActivityTime.Add(spec.IdleTimeout.Duration), fmt.Sprintf("idle timeout of %s", spec.IdleTimeout.Duration))
+	}
+	return expiresAt, cause
+}
+
+// reconcileExpiry deletes the VirtualCluster once its TTL, expiry time or idle
+// timeout passed and warns shortly before. The deletion runs through the
+// finalizer like any other. It reports whether the VirtualCluster was
+// deleted. The result requeues it for the next probe, warning or expiry.
+func (r *VirtualClusterReconciler) reconcileExpiry(ctx context.Context, vcluster *corev1alpha1.VirtualCluster) (ctrl.Result, bool, error) {
+	logger := log.FromContext(ctx)
+
+	var result ctrl.Result
+	changed := false
+	now := time.Now()
+
+	if vcluster.Spec.IdleTimeout != nil && r.ActivityProber != nil {
+		result.RequeueAfter = activityProbeInterval
+		active, err := r.observeActivity(ctx, vcluster)
+		if err != nil {
+			// An unreachable API server isn't activity, the idle timeout keeps running
+			logger.Error(err, "Failed to probe the activity of the API server")
+		}
+		// The idle timeout starts once the activity is probed, so setting it
+		// doesn't delete a VirtualCluster that was never probed
+		if active || vcluster.Status.LastActivityTime == nil {
+			vcluster.Status.LastActivityTime = &metav1.Time{Time: now}
+			changed = true
+		}
+	}
+
+	expiresAt, cause := r.expiryOf(vcluster)
+	if expiresAt.IsZero() {
+		changed = meta.RemoveStatusCondition(&vcluster.Status.Conditions, VirtualClusterConditionExpiring) || changed
+		if vcluster.Status.ExpiresAt != nil {
+			vcluster.Status.ExpiresAt = nil
+			changed = true
+		}
+		return result, false, r.updateExpiryStatus(ctx, vcluster, changed)
+	}
+	if vcluster.Status.ExpiresAt == nil || !vcluster.Status.ExpiresAt.Time.Equal(expiresAt) {
+		vcluster.Status.ExpiresAt = &metav1.Time{Time: expiresAt}
+		changed = true
+	}
+
+	remaining := expiresAt.Sub(now)
+	if remaining <= 0 {
+		message := fmt.Sprintf("VirtualCluster expired by its %s, deleting it", cause)
+		logger.Info("VirtualCluster expired, deleting it", "expiresAt", expiresAt, "cause", cause)
+		r.Recorder.Event(vcluster, corev1.EventTypeWarning, "Expired", message)
+		if err := r.Delete(ctx, vcluster); client.IgnoreNotFound(err) != nil {
+			logger.Error(err, "Failed to delete expired VirtualCluster")
+			return ctrl.Result{}, true, err
+		}
+		return ctrl.Result{}, true, nil
+	}
+
+	if remaining > expiryWarningPeriod {
+		changed = meta.RemoveStatusCondition(&vcluster.Status.Conditions, VirtualClusterConditionExpiring) || changed
+		result.RequeueAfter = shorterRequeue(result.RequeueAfter, remaining-expiryWarningPeriod)
+		return result, false, r.updateExpiryStatus(ctx, vcluster, changed)
+	}
+
+	message := fmt.Sprintf("VirtualCluster expires by its %s at %s and will be deleted", cause, expiresAt.UTC().Format(time.RFC3339))
+	if cond := meta.FindStatusCondition(vcluster.Status.Conditions, VirtualClusterConditionExpiring); cond == nil || cond.Message != message {
+		logger.Info("VirtualCluster is about to expire", "expiresAt", expiresAt, "cause", cause)
+		r.Recorder.Event(vcluster, corev1.EventTypeWarning, "Expiring", message)
+		meta.SetStatusCondition(&vcluster.Status.Conditions, metav1.Condition{
+			Type:    VirtualClusterConditionExpiring,
+			Status:  metav1.ConditionTrue,
+			Reason:  "Expiring",
+			Message: message,
+		})
+		changed = true
+	}
+	result.RequeueAfter = shorterRequeue(result.RequeueAfter, remaining)
+	return result, false, r.updateExpiryStatus(ctx, vcluster, changed)
+}
+
+// updateExpiryStatus persists the status if the expiry changed it.
+func (r *VirtualClusterReconciler) updateExpiryStatus(ctx context.Context, vcluster *corev1alpha1.VirtualCluster, changed bool) error {
+	if !changed {
+		return nil
+	}
+	if err := r.Status().Update(ctx, vcluster); err != nil && !errors.IsNotFound(err) {
+		log.FromContext(ctx).Error(err, "Failed to update VirtualCluster status")
+		return err
+	}
+	return nil
+}
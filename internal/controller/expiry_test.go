@@ -0,0 +1,240 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	corev1alpha1 "github.com/OpenVirtualCluster/openvirtualcluster-operator/api/v1alpha1"
+)
+
+// fakeActivityProber returns the request counts in order, repeating the last
+// one, and records the probed URLs.
+type fakeActivityProber struct {
+	requests []float64
+	probed   []string
+}
+
+func (p *fakeActivityProber) Requests(_ context.Context, url string, _ []byte) (float64, error) {
+	p.probed = append(p.probed, url)
+	requests := p.requests[0]
+	if len(p.requests) > 1 {
+		p.requests = p.requests[1:]
+	}
+	return requests, nil
+}
+
+// testMetrics are metrics of a virtual API server.
+const testMetrics = `# HELP apiserver_request_total Counter of apiserver requests
+# TYPE apiserver_request_total counter
+apiserver_request_total{code="200",resource="pods",verb="LIST"} 7
+apiserver_request_total{code="201",resource="deployments",verb="POST"} 3
+apiserver_request_total{code="200",resource="pods",verb="WATCH"} 40
+apiserver_request_total{code="200",resource="leases",verb="PUT"} 120
+apiserver_request_total{code="200",resource="",verb="GET"} 12
+`
+
+var _ = Describe("Expiry", func() {
+	var (
+		ctx        context.Context
+		engine     *fakeReleaseEngine
+		recorder   *record.FakeRecorder
+		reconciler *VirtualClusterReconciler
+		vc         *corev1alpha1.VirtualCluster
+	)
+
+	key := types.NamespacedName{Name: "expiry-test", Namespace: "default"}
+
+	build := func(objs ...client.Object) {
+		reconciler = NewTestReconciler(engine, recorder, append(objs, vc)...)
+	}
+
+	get := func() *corev1alpha1.VirtualCluster {
+		updated := &corev1alpha1.VirtualCluster{}
+		Expect(reconciler.Get(ctx, key, updated)).To(Succeed())
+		return updated
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		engine = &fakeReleaseEngine{exists: true}
+		recorder = record.NewFakeRecorder(10)
+
+		vc = CreateTestVirtualCluster(key.Name, key.Namespace, "")
+		vc.UID = "expiry-test-uid"
+		vc.CreationTimestamp = metav1.NewTime(time.Now().Add(-2 * time.Hour))
+		vc.Finalizers = []string{vclusterFinalizer}
+		vc.Status.Phase = corev1alpha1.VirtualClusterRunning
+		vc.Spec.Validation = &corev1alpha1.ValidationSpec{Mode: corev1alpha1.ValidationModeDisabled}
+	})
+
+	It("should delete the VirtualCluster through its finalizer once the TTL passed", func() {
+		vc.Spec.TTL = &metav1.Duration{Duration: time.Hour}
+		build()
+
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recorder.Events).To(Receive(ContainSubstring("expired by its TTL of 1h0m0s")))
+		Expect(engine.installed).To(BeEmpty())
+		Expect(engine.upgraded).To(BeEmpty())
+
+		updated := get()
+		Expect(updated.DeletionTimestamp).NotTo(BeNil())
+		Expect(updated.Finalizers).To(ContainElement(vclusterFinalizer))
+
+		_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(engine.uninstalled).To(ConsistOf(key.String()))
+	})
+
+	It("should report the expiry and warn shortly before it", func() {
+		expiresAt := metav1.NewTime(time.Now().Add(time.Hour).Truncate(time.Second))
+		vc.Spec.ExpiresAt = &expiresAt
+		build()
+
+		result, deleted, err := reconciler.reconcileExpiry(ctx, get())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(deleted).To(BeFalse())
+		Expect(result.RequeueAfter).To(BeNumerically("~", 50*time.Minute, time.Second))
+
+		updated := get()
+		Expect(updated.Status.ExpiresAt.Time).To(BeTemporally("==", expiresAt.Time))
+		Expect(meta.FindStatusCondition(updated.Status.Conditions, VirtualClusterConditionExpiring)).To(BeNil())
+		Expect(recorder.Events).NotTo(Receive())
+
+		soon := metav1.NewTime(time.Now().Add(5 * time.Minute).Truncate(time.Second))
+		updated.Spec.ExpiresAt = &soon
+		result, _, err = reconciler.reconcileExpiry(ctx, updated)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeNumerically("<=", 5*time.Minute))
+		Expect(meta.IsStatusConditionTrue(get().Status.Conditions, VirtualClusterConditionExpiring)).To(BeTrue())
+		Expect(recorder.Events).To(Receive(ContainSubstring("Warning Expiring VirtualCluster expires by its spec.expiresAt")))
+
+		// The warning is only emitted once
+		_, _, err = reconciler.reconcileExpiry(ctx, updated)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recorder.Events).NotTo(Receive())
+	})
+
+	It("should extend the idle timeout while the API server is used", func() {
+		lastActivity := metav1.NewTime(time.Now().Add(-30 * time.Minute).Truncate(time.Second))
+		vc.Spec.IdleTimeout = &metav1.Duration{Duration: time.Hour}
+		vc.Status.LastActivityTime = &lastActivity
+		build(readyControlPlane(key.Name, key.Namespace)...)
+		prober := &fakeActivityProber{requests: []float64{10, 10, 12}}
+		reconciler.ActivityProber = prober
+
+		// The first probe only records the request count
+		result, _, err := reconciler.reconcileExpiry(ctx, get())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(activityProbeInterval))
+		Expect(get().Status.ExpiresAt.Time).To(BeTemporally("==", lastActivity.Add(time.Hour)))
+
+		_, _, err = reconciler.reconcileExpiry(ctx, get())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(get().Status.LastActivityTime.Time).To(BeTemporally("==", lastActivity.Time))
+
+		_, _, err = reconciler.reconcileExpiry(ctx, get())
+		Expect(err).NotTo(HaveOccurred())
+		updated := get()
+		Expect(updated.Status.LastActivityTime.Time).To(BeTemporally("~", time.Now(), 2*time.Second))
+		Expect(updated.Status.ExpiresAt.Time).To(BeTemporally("~", time.Now().Add(time.Hour), 2*time.Second))
+		Expect(prober.probed).To(HaveEach("https://expiry-test.default.svc:443"))
+	})
+
+	It("should delete the VirtualCluster once it was idle for the timeout", func() {
+		lastActivity := metav1.NewTime(time.Now().Add(-2 * time.Hour))
+		vc.Spec.IdleTimeout = &metav1.Duration{Duration: time.Hour}
+		vc.Status.LastActivityTime = &lastActivity
+		build(readyControlPlane(key.Name, key.Namespace)...)
+		reconciler.ActivityProber = &fakeActivityProber{requests: []float64{10}}
+
+		_, deleted, err := reconciler.reconcileExpiry(ctx, get())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(deleted).To(BeTrue())
+		Expect(get().DeletionTimestamp).NotTo(BeNil())
+		Expect(recorder.Events).To(Receive(ContainSubstring("expired by its idle timeout of 1h0m0s")))
+	})
+
+	It("should ignore the idle timeout without an activity prober", func() {
+		vc.Spec.IdleTimeout = &metav1.Duration{Duration: time.Minute}
+		build()
+
+		result, deleted, err := reconciler.reconcileExpiry(ctx, get())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(deleted).To(BeFalse())
+		Expect(result.RequeueAfter).To(BeZero())
+		Expect(get().Status.ExpiresAt).To(BeNil())
+	})
+})
+
+var _ = Describe("ActivityProber", func() {
+	It("should count the requests of clients", func() {
+		Expect(clientRequests(strings.NewReader(testMetrics))).To(Equal(10.0))
+
+		_, err := clientRequests(strings.NewReader("process_open_fds 12\n"))
+		Expect(err).To(MatchError(ContainSubstring("apiserver_request_total not found")))
+	})
+
+	It("should read the metrics with the credentials of the kubeconfig", func() {
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/metrics" || r.Header.Get("Authorization") != "Bearer test-token" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			_, _ = w.Write([]byte(testMetrics))
+		}))
+		defer server.Close()
+
+		kubeconfig := `apiVersion: v1
+kind: Config
+clusters:
+- name: vcluster
+  cluster:
+    server: https://localhost:8443
+    insecure-skip-tls-verify: true
+contexts:
+- name: vcluster
+  context:
+    cluster: vcluster
+    user: vcluster
+current-context: vcluster
+users:
+- name: vcluster
+  user:
+    token: test-token
+`
+		prober := NewActivityProber(time.Second)
+		Expect(prober.Requests(context.Background(), server.URL, []byte(kubeconfig))).To(Equal(10.0))
+
+		_, err := prober.Requests(context.Background(), server.URL, []byte(strings.ReplaceAll(kubeconfig, "test-token", "other")))
+		Expect(err).To(MatchError(ContainSubstring("403")))
+	})
+})
@@ -17,65 +17,290 @@ limitations under the License.
 package controller
 
 import (
-	"os"
+	"context"
+	"fmt"
+	"strings"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+	helmtime "helm.sh/helm/v3/pkg/time"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	corev1alpha1 "github.com/OpenVirtualCluster/openvirtualcluster-operator/api/v1alpha1"
+	"github.com/OpenVirtualCluster/openvirtualcluster-operator/internal/helm"
 )
 
-// Skip all tests in this file since they require more complex mocking
-var _ = Describe("Helm Command Tests", func() {
+// fakeReleaseEngine records the calls made by the reconciler instead of
+// talking to a cluster.
+type fakeReleaseEngine struct {
+	exists    bool
+	existsErr error
+
+	installErr   error
+	upgradeErr   error
+	rollbackErr  error
+	uninstallErr error
+
+	installed   []helm.ReleaseRequest
+	upgraded    []helm.ReleaseRequest
+	rolledBack  []helm.RollbackRequest
+	deleted     []int
+	uninstalled []string
+
+	// history holds the revisions created by Install, Upgrade and Rollback,
+	// current the latest of them, which is returned by Get
+	history []*release.Release
+	current *release.Release
+	getErr  error
+}
+
+var _ helm.ReleaseEngine = &fakeReleaseEngine{}
+
+func (f *fakeReleaseEngine) Exists(_ context.Context, _, _ string) (bool, error) {
+	return f.exists, f.existsErr
+}
+
+func (f *fakeReleaseEngine) Get(_ context.Context, namespace, name string) (*release.Release, error) {
+	if f.getErr != nil {
+		return nil, f.getErr
+	}
+	if f.current == nil {
+		return nil, fmt.Errorf("failed to get release %s/%s: %w", namespace, name, helm.ErrReleaseNotFound)
+	}
+	return f.current, nil
+}
+
+func (f *fakeReleaseEngine) Install(_ context.Context, req helm.ReleaseRequest) (*release.Release, error) {
+	f.installed = append(f.installed, req)
+	if f.installErr != nil {
+		return nil, f.installErr
+	}
+	return f.record(fakeRelease(req, release.StatusDeployed)), nil
+}
+
+func (f *fakeReleaseEngine) Upgrade(_ context.Context, req helm.ReleaseRequest) (*release.Release, error) {
+	f.upgraded = append(f.upgraded, req)
+	if f.upgradeErr != nil {
+		failed := f.record(fakeRelease(req, release.StatusFailed))
+		// Like Helm, atomic upgrades roll back to the last deployed revision
+		if req.Options.Atomic {
+			for i := len(f.history) - 1; i >= 0; i-- {
+				if rel := f.history[i]; rel.Info.Status == release.StatusDeployed {
+					f.record(&release.Release{
+						Name:      rel.Name,
+						Namespace: rel.Namespace,
+						Info:      &release.Info{Status: release.StatusDeployed, Description: fmt.Sprintf("Rollback to %d", rel.Version)},
+						Chart:     rel.Chart,
+						Config:    rel.Config,
+					})
+					break
+				}
+			}
+		}
+		return failed, f.upgradeErr
+	}
+	return f.record(fakeRelease(req, release.StatusDeployed)), nil
+}
+
+func (f *fakeReleaseEngine) Rollback(_ context.Context, req helm.RollbackRequest) (*release.Release, error) {
+	f.rolledBack = append(f.rolledBack, req)
+	if f.rollbackErr != nil {
+		return nil, f.rollbackErr
+	}
+	for _, rel := range f.history {
+		if rel.Version == req.Revision {
+			return f.record(&release.Release{
+				Name:      rel.Name,
+				Namespace: rel.Namespace,
+				Info:      &release.Info{Status: release.StatusDeployed, Description: fmt.Sprintf("Rollback to %d", req.Revision)},
+				Chart:     rel.Chart,
+				Config:    rel.Config,
+			}), nil
+		}
+	}
+	return nil, fmt.Errorf("release has no revision %d", req.Revision)
+}
+
+func (f *fakeReleaseEngine) History(_ context.Context, namespace, name string) ([]*release.Release, error) {
+	if len(f.history) == 0 {
+		return nil, fmt.Errorf("failed to get history of release %s/%s: %w", namespace, name, helm.ErrReleaseNotFound)
+	}
+	return f.history, nil
+}
+
+func (f *fakeReleaseEngine) DeleteRevision(_ context.Context, namespace, name string, revision int) error {
+	for i, rel := range f.history {
+		if rel.Version == revision {
+			f.deleted = append(f.deleted, revision)
+			f.history = append(f.history[:i], f.history[i+1:]...)
+			f.current = nil
+			if len(f.history) > 0 {
+				f.current = f.history[len(f.history)-1]
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("failed to delete revision %d of release %s/%s: %w", revision, namespace, name, helm.ErrReleaseNotFound)
+}
+
+// record adds a revision to the history, superseding the deployed one.
+func (f *fakeReleaseEngine) record(rel *release.Release) *release.Release {
+	if rel.Info.Status == release.StatusDeployed {
+		for _, previous := range f.history {
+			if previous.Info.Status == release.StatusDeployed {
+				previous.Info.Status = release.StatusSuperseded
+			}
+		}
+	}
+	rel.Version = 1
+	if len(f.history) > 0 {
+		rel.Version = f.history[len(f.history)-1].Version + 1
+	}
+	rel.Info.LastDeployed = helmtime.Now()
+	f.history = append(f.history, rel)
+	f.current = rel
+	return rel
+}
+
+// fakeRelease returns a release of the requested chart and values.
+func fakeRelease(req helm.ReleaseRequest, status release.Status) *release.Release {
+	return &release.Release{
+		Name:      req.Name,
+		Namespace: req.Namespace,
+		Info:      &release.Info{Status: status},
+		Chart: &chart.Chart{Metadata: &chart.Metadata{
+			Name:    req.Chart.Name,
+			Version: strings.TrimPrefix(req.Chart.Version, "v"),
+		}},
+		Config: req.Values,
+	}
+}
+
+func (f *fakeReleaseEngine) Uninstall(_ context.Context, namespace, name string) error {
+	f.uninstalled = append(f.uninstalled, fmt.Sprintf("%s/%s", namespace, name))
+	if f.uninstallErr == nil {
+		f.history = nil
+		f.current = nil
+	}
+	return f.uninstallErr
+}
+
+var _ = Describe("Helm Release Tests", func() {
 	var (
-		testDir string
+		ctx        context.Context
+		engine     *fakeReleaseEngine
+		reconciler *VirtualClusterReconciler
+		vc         *corev1alpha1.VirtualCluster
+		values     map[string]interface{}
 	)
 
 	BeforeEach(func() {
-		// Create a temporary directory for test files
+		ctx = context.Background()
+
+		vc = CreateTestVirtualCluster("helm-test", "default", "")
+
+		// Pre-create the schema ConfigMap so no schema is fetched over the network
+		schemaConfigMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "vcluster-schema-v0-24-1",
+				Namespace: "default",
+			},
+			Data: map[string]string{
+				"values.schema.json": `{"type": "object"}`,
+			},
+		}
+
+		engine = &fakeReleaseEngine{}
+		reconciler = NewTestReconciler(engine, nil, vc, schemaConfigMap)
+
 		var err error
-		testDir, err = os.MkdirTemp("", "helm-test-")
+		values, err = reconciler.translateValues(ctx, vc, nil)
 		Expect(err).NotTo(HaveOccurred())
 	})
 
-	AfterEach(func() {
-		// Clean up test directory
-		os.RemoveAll(testDir)
-	})
-
 	Context("installOrUpgradeVCluster", func() {
-		It("should execute helm install when release doesn't exist", func() {
-			Skip("This test needs deeper mocking of helm commands and ensureSchemaConfigMap")
+		It("should install the release when it doesn't exist", func() {
+			Expect(reconciler.installOrUpgradeVCluster(ctx, vc, nil, values)).To(Succeed())
+
+			Expect(engine.upgraded).To(BeEmpty())
+			Expect(engine.installed).To(HaveLen(1))
+
+			req := engine.installed[0]
+			Expect(req.Name).To(Equal("helm-test"))
+			Expect(req.Namespace).To(Equal("default"))
+			Expect(req.CreateNamespace).To(BeFalse())
+			Expect(req.Chart).To(Equal(helm.ChartRef{
+				RepoURL: vclusterRepo,
+				Name:    vclusterChart,
+				Version: "v0.24.1",
+			}))
+			Expect(req.Values).To(HaveKeyWithValue("controlPlane",
+				HaveKeyWithValue("distro", HaveKeyWithValue("k3s", HaveKeyWithValue("image", map[string]interface{}{
+					"repository": "rancher/k3s",
+					"tag":        "v1.25.0-k3s1",
+				})))))
 		})
 
-		It("should execute helm upgrade when release exists", func() {
-			Skip("This test needs deeper mocking of helm commands and ensureSchemaConfigMap")
+		It("should upgrade the release when it exists", func() {
+			engine.exists = true
+
+			Expect(reconciler.installOrUpgradeVCluster(ctx, vc, nil, values)).To(Succeed())
+
+			Expect(engine.installed).To(BeEmpty())
+			Expect(engine.upgraded).To(HaveLen(1))
+			Expect(engine.upgraded[0].CreateNamespace).To(BeFalse())
 		})
 
-		It("should handle helm command errors correctly", func() {
-			Skip("This test needs deeper mocking of helm commands and ensureSchemaConfigMap")
+		It("should return Helm errors", func() {
+			engine.installErr = fmt.Errorf("chart not found")
+
+			err := reconciler.installOrUpgradeVCluster(ctx, vc, nil, values)
+			Expect(err).To(MatchError(ContainSubstring("chart not found")))
 		})
 	})
 
 	Context("helmReleaseExists", func() {
-		It("should return true when helm release exists", func() {
-			Skip("This test needs deeper mocking of helm commands")
+		It("should return true when the release exists", func() {
+			engine.exists = true
+
+			exists, err := reconciler.helmReleaseExists(ctx, vc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exists).To(BeTrue())
 		})
 
-		It("should return false when helm release doesn't exist", func() {
-			Skip("This test needs deeper mocking of helm commands")
+		It("should return false when the release doesn't exist", func() {
+			exists, err := reconciler.helmReleaseExists(ctx, vc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exists).To(BeFalse())
 		})
 
-		It("should handle helm list command errors", func() {
-			Skip("This test needs deeper mocking of helm commands")
+		It("should return list errors", func() {
+			engine.existsErr = fmt.Errorf("cluster unreachable")
+
+			_, err := reconciler.helmReleaseExists(ctx, vc)
+			Expect(err).To(HaveOccurred())
 		})
 	})
 
 	Context("finalizeVirtualCluster", func() {
-		It("should call helm uninstall when finalizing", func() {
-			Skip("This test needs deeper mocking of helm commands")
+		It("should uninstall the release", func() {
+			Expect(reconciler.finalizeVirtualCluster(ctx, vc)).To(Succeed())
+			Expect(engine.uninstalled).To(ConsistOf("default/helm-test"))
 		})
 
-		It("should handle errors during finalization", func() {
-			Skip("This test needs deeper mocking of helm commands")
+		It("should treat a missing release as already deleted", func() {
+			engine.uninstallErr = fmt.Errorf("uninstall: %w", helm.ErrReleaseNotFound)
+
+			Expect(reconciler.finalizeVirtualCluster(ctx, vc)).To(Succeed())
+		})
+
+		It("should return other uninstall errors", func() {
+			engine.uninstallErr = fmt.Errorf("cluster unreachable")
+
+			Expect(reconciler.finalizeVirtualCluster(ctx, vc)).NotTo(Succeed())
 		})
 	})
 })
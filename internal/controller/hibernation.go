@@ -0,0 +1,221 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	corev1alpha1 "github.com/OpenVirtualCluster/openvirtualcluster-operator/api/v1alpha1"
+	"github.com/OpenVirtualCluster/openvirtualcluster-operator/internal/hibernation"
+)
+
+// hibernatedReplicasAnnotation keeps the replicas of the control plane
+// workload while the VirtualCluster hibernates
+const hibernatedReplicasAnnotation = "core.openvc.dev/hibernated-replicas"
+
+// shorterRequeue returns the shorter of two requeue intervals, ignoring
+// zero intervals.
+func shorterRequeue(a, b time.Duration) time.Duration {
+	if a <= 0 || (b > 0 && b < a) {
+		return b
+	}
+	return a
+}
+
+// reconcileHibernation puts the VirtualCluster to sleep while it should
+// hibernate and wakes it up once it shouldn't anymore. It reports whether
+// the VirtualCluster hibernates, in which case no Helm operations run. The
+// result requeues the VirtualCluster for the next time a schedule fires.
+func (r *VirtualClusterReconciler) reconcileHibernation(ctx context.Context, vcluster *corev1alpha1.VirtualCluster) (ctrl.Result, bool, error) {
+	logger := log.FromContext(ctx)
+
+	spec := vcluster.Spec.Hibernation
+	hibernate := spec != nil && spec.Hibernate
+	var result ctrl.Result
+	if spec != nil && (spec.SleepSchedule != "" || spec.WakeSchedule != "") {
+		schedule, err := hibernation.Parse(spec.SleepSchedule, spec.WakeSchedule, spec.TimeZone)
+		if err != nil {
+			logger.Error(err, "Invalid hibernation schedule")
+			meta.SetStatusCondition(&vcluster.Status.Conditions, metav1.Condition{
+				Type:    VirtualClusterConditionError,
+				Status:  metav1.ConditionTrue,
+				Reason:  "InvalidHibernationSchedule",
+				Message: err.Error(),
+			})
+			if err := r.Status().Update(ctx, vcluster); err != nil {
+				logger.Error(err, "Failed to update VirtualCluster status")
+			}
+			r.Recorder.Event(vcluster, corev1.EventTypeWarning, "InvalidHibernationSchedule", err.Error())
+			return ctrl.Result{}, false, err
+		}
+
+		now := time.Now()
+		hibernate = hibernate || schedule.Asleep(now)
+		if next := schedule.Next(now); !next.IsZero() {
+			result.RequeueAfter = next.Sub(now)
+		}
+	}
+
+	if hibernate {
+		return r.hibernate(ctx, vcluster, result)
+	}
+	if vcluster.Status.Phase == corev1alpha1.VirtualClusterHibernated {
+		if err := r.wake(ctx, vcluster); err != nil {
+			return ctrl.Result{}, false, err
+		}
+	}
+	return result, false, nil
+}
+
+// hibernate scales the control plane to zero and, once it is stopped, removes
+// the pods the syncer created in the host cluster. The virtual cluster keeps
+// their workloads, so they are synced again when it wakes up.
+func (r *VirtualClusterReconciler) hibernate(ctx context.Context, vcluster *corev1alpha1.VirtualCluster, result ctrl.Result) (ctrl.Result, bool, error) {
+	logger := log.FromContext(ctx)
+
+	if err := r.scaleControlPlane(ctx, vcluster, true); err != nil {
+		logger.Error(err, "Failed to scale down the control plane")
+		return ctrl.Result{}, true, err
+	}
+
+	namespace := vcluster.ReleaseNamespace()
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(namespace), controlPlaneLabels(vcluster)); err != nil {
+		return ctrl.Result{}, true, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	message := "VirtualCluster is hibernated"
+	if len(pods.Items) > 0 {
+		// The syncer would sync the pods again while it is running
+		message = "Waiting for the control plane to stop"
+		result.RequeueAfter = shorterRequeue(result.RequeueAfter, controlPlaneRequeueInterval)
+	} else {
+		if err := r.List(ctx, pods, client.InNamespace(namespace), client.MatchingLabels{managedByLabel: vcluster.Name}); err != nil {
+			return ctrl.Result{}, true, fmt.Errorf("failed to list synced pods: %w", err)
+		}
+		for i := range pods.Items {
+			if err := r.Delete(ctx, &pods.Items[i]); client.IgnoreNotFound(err) != nil {
+				return ctrl.Result{}, true, fmt.Errorf("failed to delete synced pod %s: %w", pods.Items[i].Name, err)
+			}
+		}
+	}
+
+	hibernated := vcluster.Status.Phase != corev1alpha1.VirtualClusterHibernated
+	if !hibernated && vcluster.Status.Message == message {
+		return result, true, nil
+	}
+	vcluster.Status.Phase = corev1alpha1.VirtualClusterHibernated
+	vcluster.Status.Message = message
+	meta.SetStatusCondition(&vcluster.Status.Conditions, metav1.Condition{
+		Type:    VirtualClusterConditionAvailable,
+		Status:  metav1.ConditionFalse,
+		Reason:  "Hibernated",
+		Message: "VirtualCluster is hibernated",
+	})
+	if err := r.Status().Update(ctx, vcluster); err != nil {
+		logger.Error(err, "Failed to update VirtualCluster status")
+		return ctrl.Result{}, true, err
+	}
+
+	if hibernated {
+		logger.Info("VirtualCluster is hibernating")
+		r.Recorder.Event(vcluster, corev1.EventTypeNormal, "Hibernated", "VirtualCluster is hibernating")
+	}
+	return result, true, nil
+}
+
+// wake scales the control plane back up. The readiness checks of the
+// following reconcile report the VirtualCluster as running again.
+func (r *VirtualClusterReconciler) wake(ctx context.Context, vcluster *corev1alpha1.VirtualCluster) error {
+	if err := r.scaleControlPlane(ctx, vcluster, false); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to scale up the control plane")
+		return err
+	}
+
+	log.FromContext(ctx).Info("VirtualCluster is waking up")
+	vcluster.Status.Phase = corev1alpha1.VirtualClusterProvisioning
+	vcluster.Status.Message = "VirtualCluster is waking up"
+	r.Recorder.Event(vcluster, corev1.EventTypeNormal, "WokeUp", "VirtualCluster is waking up")
+	return nil
+}
+
+// scaleControlPlane scales the StatefulSet or Deployment of the control plane
+// to zero, keeping its replicas in an annotation, or restores them.
+func (r *VirtualClusterReconciler) scaleControlPlane(ctx context.Context, vcluster *corev1alpha1.VirtualCluster, down bool) error {
+	key := client.ObjectKey{Namespace: vcluster.ReleaseNamespace(), Name: vcluster.Name}
+
+	for _, workload := range []client.Object{&appsv1.StatefulSet{}, &appsv1.Deployment{}} {
+		if err := r.Get(ctx, key, workload); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+
+		var replicas **int32
+		switch w := workload.(type) {
+		case *appsv1.StatefulSet:
+			replicas = &w.Spec.Replicas
+		case *appsv1.Deployment:
+			replicas = &w.Spec.Replicas
+		}
+
+		annotations := workload.GetAnnotations()
+		saved, scaled := annotations[hibernatedReplicasAnnotation]
+		if down == scaled {
+			continue
+		}
+
+		if down {
+			current := int32(1)
+			if *replicas != nil {
+				current = **replicas
+			}
+			if annotations == nil {
+				annotations = map[string]string{}
+			}
+			annotations[hibernatedReplicasAnnotation] = strconv.Itoa(int(current))
+			*replicas = ptr.To[int32](0)
+		} else {
+			restored, err := strconv.ParseInt(saved, 10, 32)
+			if err != nil {
+				return fmt.Errorf("invalid %s annotation %q: %w", hibernatedReplicasAnnotation, saved, err)
+			}
+			*replicas = ptr.To(int32(restored))
+			delete(annotations, hibernatedReplicasAnnotation)
+		}
+		workload.SetAnnotations(annotations)
+
+		if err := r.Update(ctx, workload); err != nil {
+			return fmt.Errorf("failed to scale %s: %w", key.Name, err)
+		}
+	}
+	return nil
+}
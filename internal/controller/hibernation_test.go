@@ -0,0 +1,176 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	corev1alpha1 "github.com/OpenVirtualCluster/openvirtualcluster-operator/api/v1alpha1"
+)
+
+var _ = Describe("Hibernation", func() {
+	var (
+		ctx        context.Context
+		engine     *fakeReleaseEngine
+		recorder   *record.FakeRecorder
+		reconciler *VirtualClusterReconciler
+		vc         *corev1alpha1.VirtualCluster
+		objects    []client.Object
+	)
+
+	key := types.NamespacedName{Name: "hibernation-test", Namespace: "default"}
+
+	build := func() {
+		reconciler = NewTestReconciler(engine, recorder, append(objects, vc)...)
+	}
+
+	reconcileAndGet := func() (reconcile.Result, *corev1alpha1.VirtualCluster) {
+		result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+
+		updated := &corev1alpha1.VirtualCluster{}
+		Expect(reconciler.Get(ctx, key, updated)).To(Succeed())
+		return result, updated
+	}
+
+	statefulSet := func() *appsv1.StatefulSet {
+		sts := &appsv1.StatefulSet{}
+		Expect(reconciler.Get(ctx, key, sts)).To(Succeed())
+		return sts
+	}
+
+	podExists := func(name string) bool {
+		err := reconciler.Get(ctx, types.NamespacedName{Name: name, Namespace: key.Namespace}, &corev1.Pod{})
+		if errors.IsNotFound(err) {
+			return false
+		}
+		Expect(err).NotTo(HaveOccurred())
+		return true
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		engine = &fakeReleaseEngine{exists: true}
+		recorder = record.NewFakeRecorder(10)
+
+		vc = CreateTestVirtualCluster(key.Name, key.Namespace, "")
+		vc.Finalizers = []string{vclusterFinalizer}
+		vc.Status.Phase = corev1alpha1.VirtualClusterRunning
+		vc.Spec.Validation = &corev1alpha1.ValidationSpec{Mode: corev1alpha1.ValidationModeDisabled}
+
+		objects = append(readyControlPlane(key.Name, key.Namespace),
+			&corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Name: "hibernation-test-0", Namespace: key.Namespace,
+				Labels: map[string]string{"app": "vcluster", "release": key.Name},
+			}},
+			&corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Name: "nginx-x-default-x-hibernation-test", Namespace: key.Namespace,
+				Labels: map[string]string{managedByLabel: key.Name},
+			}},
+		)
+	})
+
+	It("should scale the control plane down and remove synced pods", func() {
+		vc.Spec.Hibernation = &corev1alpha1.HibernationSpec{Hibernate: true}
+		build()
+
+		result, updated := reconcileAndGet()
+		Expect(engine.installed).To(BeEmpty())
+		Expect(engine.upgraded).To(BeEmpty())
+
+		sts := statefulSet()
+		Expect(sts.Spec.Replicas).To(Equal(ptr.To[int32](0)))
+		Expect(sts.Annotations).To(HaveKeyWithValue(hibernatedReplicasAnnotation, "1"))
+
+		Expect(updated.Status.Phase).To(Equal(corev1alpha1.VirtualClusterHibernated))
+		Expect(updated.Status.Message).To(Equal("Waiting for the control plane to stop"))
+		Expect(meta.IsStatusConditionFalse(updated.Status.Conditions, VirtualClusterConditionAvailable)).To(BeTrue())
+		Expect(recorder.Events).To(Receive(ContainSubstring("Hibernated")))
+
+		// Synced pods are only removed once the syncer stopped
+		Expect(result.RequeueAfter).To(Equal(controlPlaneRequeueInterval))
+		Expect(podExists("nginx-x-default-x-hibernation-test")).To(BeTrue())
+
+		Expect(reconciler.Delete(ctx, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "hibernation-test-0", Namespace: key.Namespace}})).To(Succeed())
+		result, updated = reconcileAndGet()
+		Expect(result.RequeueAfter).To(BeZero())
+		Expect(podExists("nginx-x-default-x-hibernation-test")).To(BeFalse())
+		Expect(updated.Status.Message).To(Equal("VirtualCluster is hibernated"))
+		Expect(statefulSet().Annotations).To(HaveKeyWithValue(hibernatedReplicasAnnotation, "1"))
+	})
+
+	It("should scale the control plane back up when woken", func() {
+		vc.Spec.Hibernation = &corev1alpha1.HibernationSpec{Hibernate: true}
+		build()
+		reconcileAndGet()
+
+		updated := &corev1alpha1.VirtualCluster{}
+		Expect(reconciler.Get(ctx, key, updated)).To(Succeed())
+		updated.Spec.Hibernation.Hibernate = false
+		Expect(reconciler.Update(ctx, updated)).To(Succeed())
+
+		_, updated = reconcileAndGet()
+		sts := statefulSet()
+		Expect(sts.Spec.Replicas).To(Equal(ptr.To[int32](1)))
+		Expect(sts.Annotations).NotTo(HaveKey(hibernatedReplicasAnnotation))
+		Expect(updated.Status.Phase).NotTo(Equal(corev1alpha1.VirtualClusterHibernated))
+		Eventually(recorder.Events).Should(Receive(ContainSubstring("VirtualCluster is waking up")))
+	})
+
+	It("should hibernate on the sleep schedule and requeue for the next activation", func() {
+		// The wake schedule never fires, Feb 31 doesn't exist
+		vc.Spec.Hibernation = &corev1alpha1.HibernationSpec{
+			SleepSchedule: "* * * * *",
+			WakeSchedule:  "0 0 31 2 *",
+			TimeZone:      "Europe/Berlin",
+		}
+		build()
+
+		result, updated := reconcileAndGet()
+		Expect(updated.Status.Phase).To(Equal(corev1alpha1.VirtualClusterHibernated))
+		Expect(result.RequeueAfter).To(BeNumerically("<=", time.Minute))
+	})
+
+	It("should report an invalid schedule", func() {
+		vc.Spec.Hibernation = &corev1alpha1.HibernationSpec{SleepSchedule: "at night", WakeSchedule: "0 7 * * *"}
+		build()
+
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).To(MatchError(ContainSubstring("invalid sleep schedule")))
+
+		updated := &corev1alpha1.VirtualCluster{}
+		Expect(reconciler.Get(ctx, key, updated)).To(Succeed())
+		cond := meta.FindStatusCondition(updated.Status.Conditions, VirtualClusterConditionError)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Reason).To(Equal("InvalidHibernationSchedule"))
+		Expect(statefulSet().Spec.Replicas).To(Equal(ptr.To[int32](1)))
+	})
+})
@@ -0,0 +1,347 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	corev1alpha1 "github.com/OpenVirtualCluster/openvirtualcluster-operator/api/v1alpha1"
+)
+
+const (
+	// isolationNameSuffix is appended to the name of the VirtualCluster to
+	// name its isolation resources
+	isolationNameSuffix = "-isolation"
+
+	// apiServerPolicySuffix is appended to the name of the isolation
+	// resources to name the NetworkPolicy admitting traffic to the API server
+	apiServerPolicySuffix = "-apiserver"
+
+	// virtualAPIServerPort is the port the API server of a vcluster listens
+	// on in its pod
+	virtualAPIServerPort = 8443
+
+	// Reasons of the Isolated condition
+	isolationReasonEnforced       = "Enforced"
+	isolationReasonQuotaExhausted = "QuotaExhausted"
+	isolationReasonFailed         = "IsolationFailed"
+)
+
+var (
+	// defaultQuotaHard are the limits of the ResourceQuota preset
+	defaultQuotaHard = corev1.ResourceList{
+		corev1.ResourceRequestsCPU:            resource.MustParse("10"),
+		corev1.ResourceRequestsMemory:         resource.MustParse("20Gi"),
+		corev1.ResourceLimitsCPU:              resource.MustParse("20"),
+		corev1.ResourceLimitsMemory:           resource.MustParse("40Gi"),
+		corev1.ResourceRequestsStorage:        resource.MustParse("100Gi"),
+		corev1.ResourcePersistentVolumeClaims: resource.MustParse("20"),
+		corev1.ResourcePods:                   resource.MustParse("50"),
+		corev1.ResourceServices:               resource.MustParse("20"),
+	}
+
+	// defaultContainerLimits are the default limits of the LimitRange preset
+	defaultContainerLimits = corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("1"),
+		corev1.ResourceMemory: resource.MustParse("512Mi"),
+	}
+
+	// defaultContainerRequests are the default requests of the LimitRange
+	// preset
+	defaultContainerRequests = corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("100m"),
+		corev1.ResourceMemory: resource.MustParse("128Mi"),
+	}
+)
+
+// isolationName returns the name of the isolation resources of the
+// VirtualCluster.
+func isolationName(vcluster *corev1alpha1.VirtualCluster) string {
+	return vcluster.Name + isolationNameSuffix
+}
+
+// apiServerPolicyName returns the name of the NetworkPolicy admitting traffic
+// to the API server of the VirtualCluster.
+func apiServerPolicyName(vcluster *corev1alpha1.VirtualCluster) string {
+	return isolationName(vcluster) + apiServerPolicySuffix
+}
+
+// reconcileIsolation creates the ResourceQuota, LimitRange and NetworkPolicy
+// configured by spec.isolation in the namespace of the Helm release, deletes
+// the disabled ones and reports them in the status. Resources in the
+// namespace of the VirtualCluster are owned by it, the ones in a target
+// namespace are labeled with it like the namespace.
+func (r *VirtualClusterReconciler) reconcileIsolation(ctx context.Context, vcluster *corev1alpha1.VirtualCluster) error {
+	spec := vcluster.Spec.Isolation
+	if spec == nil {
+		if vcluster.Status.Isolation == nil {
+			return nil
+		}
+		if err := r.deleteIsolation(ctx, vcluster); err != nil {
+			return err
+		}
+		vcluster.Status.Isolation = nil
+		meta.RemoveStatusCondition(&vcluster.Status.Conditions, VirtualClusterConditionIsolated)
+		return nil
+	}
+
+	objectMeta := metav1.ObjectMeta{Name: isolationName(vcluster), Namespace: vcluster.ReleaseNamespace()}
+	status := &corev1alpha1.IsolationStatus{Namespace: objectMeta.Namespace}
+
+	quotaSpec := ptr.Deref(spec.ResourceQuota, corev1alpha1.IsolationResourceQuota{})
+	quota := &corev1.ResourceQuota{ObjectMeta: *objectMeta.DeepCopy()}
+	if ptr.Deref(quotaSpec.Enabled, true) {
+		hard := defaultQuotaHard
+		if len(quotaSpec.Hard) > 0 {
+			hard = quotaSpec.Hard
+		}
+		if err := r.writeIsolationObject(ctx, vcluster, "ResourceQuota", quota, func() {
+			quota.Spec.Hard = hard.DeepCopy()
+		}); err != nil {
+			return err
+		}
+		status.ResourceQuota = quota.Name
+		status.Hard = quota.Status.Hard
+		status.Used = quota.Status.Used
+	} else if err := r.deleteIsolationObject(ctx, vcluster, quota); err != nil {
+		return err
+	}
+
+	limitRangeSpec := ptr.Deref(spec.LimitRange, corev1alpha1.IsolationLimitRange{})
+	limitRange := &corev1.LimitRange{ObjectMeta: *objectMeta.DeepCopy()}
+	if ptr.Deref(limitRangeSpec.Enabled, true) {
+		limits, requests := defaultContainerLimits, defaultContainerRequests
+		if len(limitRangeSpec.Default) > 0 {
+			limits = limitRangeSpec.Default
+		}
+		if len(limitRangeSpec.DefaultRequest) > 0 {
+			requests = limitRangeSpec.DefaultRequest
+		}
+		if err := r.writeIsolationObject(ctx, vcluster, "LimitRange", limitRange, func() {
+			limitRange.Spec.Limits = []corev1.LimitRangeItem{{
+				Type:           corev1.LimitTypeContainer,
+				Default:        limits.DeepCopy(),
+				DefaultRequest: requests.DeepCopy(),
+			}}
+		}); err != nil {
+			return err
+		}
+		status.LimitRange = limitRange.Name
+	} else if err := r.deleteIsolationObject(ctx, vcluster, limitRange); err != nil {
+		return err
+	}
+
+	policySpec := ptr.Deref(spec.NetworkPolicy, corev1alpha1.IsolationNetworkPolicy{})
+	policy := &networkingv1.NetworkPolicy{ObjectMeta: *objectMeta.DeepCopy()}
+	apiServerPolicy := &networkingv1.NetworkPolicy{ObjectMeta: *objectMeta.DeepCopy()}
+	apiServerPolicy.Name = apiServerPolicyName(vcluster)
+	if ptr.Deref(policySpec.Enabled, true) {
+		if err := r.writeIsolationObject(ctx, vcluster, "NetworkPolicy", policy, func() {
+			policy.Spec = defaultDenyPolicy(policySpec.AllowFrom)
+		}); err != nil {
+			return err
+		}
+		if err := r.writeIsolationObject(ctx, vcluster, "NetworkPolicy", apiServerPolicy, func() {
+			apiServerPolicy.Spec = apiServerAccessPolicy(vcluster)
+		}); err != nil {
+			return err
+		}
+		status.NetworkPolicy = policy.Name
+	} else {
+		for _, obj := range []client.Object{policy, apiServerPolicy} {
+			if err := r.deleteIsolationObject(ctx, vcluster, obj); err != nil {
+				return err
+			}
+		}
+	}
+
+	vcluster.Status.Isolation = status
+
+	condition := metav1.Condition{
+		Type:    VirtualClusterConditionIsolated,
+		Status:  metav1.ConditionTrue,
+		Reason:  isolationReasonEnforced,
+		Message: fmt.Sprintf("Isolation resources are in place in namespace %s", status.Namespace),
+	}
+	if exhausted := exhaustedResources(status.Hard, status.Used); len(exhausted) > 0 {
+		condition.Reason = isolationReasonQuotaExhausted
+		condition.Message = fmt.Sprintf("ResourceQuota %s/%s is exhausted for %s",
+			status.Namespace, status.ResourceQuota, strings.Join(exhausted, ", "))
+
+		previous := meta.FindStatusCondition(vcluster.Status.Conditions, VirtualClusterConditionIsolated)
+		if previous == nil || previous.Reason != isolationReasonQuotaExhausted {
+			r.Recorder.Event(vcluster, corev1.EventTypeWarning, isolationReasonQuotaExhausted, condition.Message)
+		}
+	}
+	meta.SetStatusCondition(&vcluster.Status.Conditions, condition)
+	return nil
+}
+
+// defaultDenyPolicy returns a NetworkPolicy denying ingress to the pods of the
+// namespace from other namespaces, apart from the given peers.
+func defaultDenyPolicy(allowFrom []networkingv1.NetworkPolicyPeer) networkingv1.NetworkPolicySpec {
+	spec := networkingv1.NetworkPolicySpec{
+		PodSelector: metav1.LabelSelector{},
+		PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+		Ingress: []networkingv1.NetworkPolicyIngressRule{
+			{From: []networkingv1.NetworkPolicyPeer{{PodSelector: &metav1.LabelSelector{}}}},
+		},
+	}
+	if len(allowFrom) > 0 {
+		spec.Ingress = append(spec.Ingress, networkingv1.NetworkPolicyIngressRule{
+			From: allowFrom,
+		})
+	}
+	return spec
+}
+
+// apiServerAccessPolicy returns a NetworkPolicy admitting traffic from
+// anywhere to the API server port of the control plane pods of the
+// VirtualCluster, as the operator and the users of the kubeconfig connect to
+// it. Other pods of the namespace, including synced workloads, stay isolated.
+func apiServerAccessPolicy(vcluster *corev1alpha1.VirtualCluster) networkingv1.NetworkPolicySpec {
+	return networkingv1.NetworkPolicySpec{
+		PodSelector: metav1.LabelSelector{MatchLabels: controlPlaneLabels(vcluster)},
+		PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+		Ingress: []networkingv1.NetworkPolicyIngressRule{{
+			Ports: []networkingv1.NetworkPolicyPort{{
+				Protocol: ptr.To(corev1.ProtocolTCP),
+				Port:     ptr.To(intstr.FromInt32(virtualAPIServerPort)),
+			}},
+		}},
+	}
+}
+
+// exhaustedResources returns the resources of the quota whose usage reached
+// the limit. Limits of zero forbid a resource and aren't reported.
+func exhaustedResources(hard, used corev1.ResourceList) []string {
+	var exhausted []string
+	for name, limit := range hard {
+		usage, ok := used[name]
+		if ok && !limit.IsZero() && usage.Cmp(limit) >= 0 {
+			exhausted = append(exhausted, string(name))
+		}
+	}
+	sort.Strings(exhausted)
+	return exhausted
+}
+
+// writeIsolationObject creates or updates an isolation resource. Resources
+// that already exist and aren't labeled for the VirtualCluster are never taken
+// over.
+func (r *VirtualClusterReconciler) writeIsolationObject(ctx context.Context, vcluster *corev1alpha1.VirtualCluster, kind string, obj client.Object, mutate func()) error {
+	result, err := controllerutil.CreateOrUpdate(ctx, r.Client, obj, func() error {
+		if obj.GetResourceVersion() != "" && !isIsolationObjectOf(obj, vcluster) {
+			return fmt.Errorf("%s %s/%s already exists and is not managed by the VirtualCluster", kind, obj.GetNamespace(), obj.GetName())
+		}
+
+		labels := obj.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		labels["app.kubernetes.io/managed-by"] = "openvc-controller"
+		labels[virtualClusterNameLabel] = vcluster.Name
+		labels[virtualClusterNamespaceLabel] = vcluster.Namespace
+		obj.SetLabels(labels)
+
+		mutate()
+
+		// Owner references can't cross namespaces
+		if obj.GetNamespace() == vcluster.Namespace {
+			return ctrl.SetControllerReference(vcluster, obj, r.Scheme)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write %s %s/%s: %w", kind, obj.GetNamespace(), obj.GetName(), err)
+	}
+
+	if result != controllerutil.OperationResultNone {
+		log.FromContext(ctx).Info("Ensured isolation resource", "kind", kind, "name", client.ObjectKeyFromObject(obj), "operation", result)
+	}
+	return nil
+}
+
+// deleteIsolation deletes all isolation resources of the VirtualCluster.
+func (r *VirtualClusterReconciler) deleteIsolation(ctx context.Context, vcluster *corev1alpha1.VirtualCluster) error {
+	objectMeta := metav1.ObjectMeta{Name: isolationName(vcluster), Namespace: vcluster.ReleaseNamespace()}
+	apiServerPolicyMeta := metav1.ObjectMeta{Name: apiServerPolicyName(vcluster), Namespace: vcluster.ReleaseNamespace()}
+	for _, obj := range []client.Object{
+		&corev1.ResourceQuota{ObjectMeta: *objectMeta.DeepCopy()},
+		&corev1.LimitRange{ObjectMeta: *objectMeta.DeepCopy()},
+		&networkingv1.NetworkPolicy{ObjectMeta: *objectMeta.DeepCopy()},
+		&networkingv1.NetworkPolicy{ObjectMeta: apiServerPolicyMeta},
+	} {
+		if err := r.deleteIsolationObject(ctx, vcluster, obj); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteIsolationObject deletes an isolation resource if it is labeled for
+// the VirtualCluster.
+func (r *VirtualClusterReconciler) deleteIsolationObject(ctx context.Context, vcluster *corev1alpha1.VirtualCluster, obj client.Object) error {
+	if err := r.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if !isIsolationObjectOf(obj, vcluster) {
+		return nil
+	}
+	if err := r.Delete(ctx, obj); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("failed to delete %s/%s: %w", obj.GetNamespace(), obj.GetName(), err)
+	}
+	log.FromContext(ctx).Info("Deleted isolation resource", "name", client.ObjectKeyFromObject(obj))
+	return nil
+}
+
+// isIsolationObjectOf returns true if the object is labeled for the
+// VirtualCluster.
+func isIsolationObjectOf(obj client.Object, vcluster *corev1alpha1.VirtualCluster) bool {
+	return obj.GetLabels()[virtualClusterNameLabel] == vcluster.Name &&
+		obj.GetLabels()[virtualClusterNamespaceLabel] == vcluster.Namespace
+}
+
+// requestsForIsolationObject maps an isolation resource to the VirtualCluster
+// it is labeled with, so changes to it are reverted and the usage of the
+// quota is reported.
+func (r *VirtualClusterReconciler) requestsForIsolationObject(ctx context.Context, obj client.Object) []reconcile.Request {
+	name, namespace := obj.GetLabels()[virtualClusterNameLabel], obj.GetLabels()[virtualClusterNamespaceLabel]
+	if name == "" || namespace == "" {
+		return nil
+	}
+	if obj.GetName() != name+isolationNameSuffix && obj.GetName() != name+isolationNameSuffix+apiServerPolicySuffix {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: client.ObjectKey{Namespace: namespace, Name: name}}}
+}
@@ -0,0 +1,246 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	corev1alpha1 "github.com/OpenVirtualCluster/openvirtualcluster-operator/api/v1alpha1"
+)
+
+var _ = Describe("Isolation", func() {
+	var (
+		ctx        context.Context
+		recorder   *record.FakeRecorder
+		reconciler *VirtualClusterReconciler
+		vc         *corev1alpha1.VirtualCluster
+	)
+
+	newReconciler := func(objs ...client.Object) {
+		reconciler = NewTestReconciler(&fakeReleaseEngine{}, recorder, append(objs, vc)...)
+	}
+
+	key := func(namespace string) client.ObjectKey {
+		return client.ObjectKey{Namespace: namespace, Name: "isolated-isolation"}
+	}
+
+	labeledQuota := func(namespace string) *corev1.ResourceQuota {
+		return &corev1.ResourceQuota{ObjectMeta: metav1.ObjectMeta{
+			Name:      "isolated-isolation",
+			Namespace: namespace,
+			Labels: map[string]string{
+				virtualClusterNameLabel:      vc.Name,
+				virtualClusterNamespaceLabel: vc.Namespace,
+			},
+		}}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		recorder = record.NewFakeRecorder(10)
+
+		vc = CreateTestVirtualCluster("isolated", "tenant-a", "")
+		vc.UID = "isolated-uid"
+		vc.Spec.Isolation = &corev1alpha1.IsolationSpec{}
+	})
+
+	It("should create the preset resources owned by the VirtualCluster", func() {
+		newReconciler()
+		Expect(reconciler.reconcileIsolation(ctx, vc)).To(Succeed())
+
+		quota := &corev1.ResourceQuota{}
+		Expect(reconciler.Get(ctx, key("tenant-a"), quota)).To(Succeed())
+		Expect(quota.Spec.Hard).To(Equal(defaultQuotaHard))
+		Expect(metav1.IsControlledBy(quota, vc)).To(BeTrue())
+
+		limitRange := &corev1.LimitRange{}
+		Expect(reconciler.Get(ctx, key("tenant-a"), limitRange)).To(Succeed())
+		Expect(limitRange.Spec.Limits).To(ConsistOf(HaveField("DefaultRequest", defaultContainerRequests)))
+
+		policy := &networkingv1.NetworkPolicy{}
+		Expect(reconciler.Get(ctx, key("tenant-a"), policy)).To(Succeed())
+		Expect(policy.Spec.PodSelector).To(Equal(metav1.LabelSelector{}))
+		Expect(policy.Spec.PolicyTypes).To(ConsistOf(networkingv1.PolicyTypeIngress))
+		Expect(policy.Spec.Ingress).To(HaveLen(1))
+		Expect(metav1.IsControlledBy(policy, vc)).To(BeTrue())
+
+		Expect(vc.Status.Isolation).To(Equal(&corev1alpha1.IsolationStatus{
+			Namespace:     "tenant-a",
+			ResourceQuota: "isolated-isolation",
+			LimitRange:    "isolated-isolation",
+			NetworkPolicy: "isolated-isolation",
+		}))
+		condition := meta.FindStatusCondition(vc.Status.Conditions, VirtualClusterConditionIsolated)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(isolationReasonEnforced))
+	})
+
+	It("should label the resources in a target namespace instead of owning them", func() {
+		vc.Spec.TargetNamespace = &corev1alpha1.TargetNamespaceSpec{Name: "tenant-a-workloads"}
+		newReconciler()
+		Expect(reconciler.reconcileIsolation(ctx, vc)).To(Succeed())
+
+		quota := &corev1.ResourceQuota{}
+		Expect(reconciler.Get(ctx, key("tenant-a-workloads"), quota)).To(Succeed())
+		Expect(quota.OwnerReferences).To(BeEmpty())
+		Expect(quota.Labels).To(HaveKeyWithValue(virtualClusterNameLabel, "isolated"))
+		Expect(quota.Labels).To(HaveKeyWithValue(virtualClusterNamespaceLabel, "tenant-a"))
+
+		Expect(reconciler.requestsForIsolationObject(ctx, quota)).To(ConsistOf(reconcile.Request{
+			NamespacedName: client.ObjectKeyFromObject(vc),
+		}))
+	})
+
+	It("should apply the configured limits and peers and revert changes", func() {
+		vc.Spec.Isolation.ResourceQuota = &corev1alpha1.IsolationResourceQuota{
+			Hard: corev1.ResourceList{corev1.ResourcePods: resource.MustParse("5")},
+		}
+		vc.Spec.Isolation.NetworkPolicy = &corev1alpha1.IsolationNetworkPolicy{
+			AllowFrom: []networkingv1.NetworkPolicyPeer{{NamespaceSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"kubernetes.io/metadata.name": "ingress-nginx"},
+			}}},
+		}
+		newReconciler()
+		Expect(reconciler.reconcileIsolation(ctx, vc)).To(Succeed())
+
+		quota := &corev1.ResourceQuota{}
+		Expect(reconciler.Get(ctx, key("tenant-a"), quota)).To(Succeed())
+		quota.Spec.Hard[corev1.ResourcePods] = resource.MustParse("500")
+		Expect(reconciler.Update(ctx, quota)).To(Succeed())
+		Expect(reconciler.reconcileIsolation(ctx, vc)).To(Succeed())
+
+		Expect(reconciler.Get(ctx, key("tenant-a"), quota)).To(Succeed())
+		Expect(quota.Spec.Hard).To(Equal(corev1.ResourceList{corev1.ResourcePods: resource.MustParse("5")}))
+
+		policy := &networkingv1.NetworkPolicy{}
+		Expect(reconciler.Get(ctx, key("tenant-a"), policy)).To(Succeed())
+		Expect(policy.Spec.Ingress).To(HaveLen(2))
+		Expect(policy.Spec.Ingress[1].From).To(Equal(vc.Spec.Isolation.NetworkPolicy.AllowFrom))
+	})
+
+	It("should only open the API server port of the control plane pods", func() {
+		newReconciler()
+		Expect(reconciler.reconcileIsolation(ctx, vc)).To(Succeed())
+
+		// The default-deny policy doesn't admit any port from other namespaces
+		policy := &networkingv1.NetworkPolicy{}
+		Expect(reconciler.Get(ctx, key("tenant-a"), policy)).To(Succeed())
+		for _, rule := range policy.Spec.Ingress {
+			Expect(rule.Ports).To(BeEmpty())
+			Expect(rule.From).NotTo(BeEmpty())
+		}
+
+		apiServerPolicy := &networkingv1.NetworkPolicy{}
+		apiServerKey := client.ObjectKey{Namespace: "tenant-a", Name: "isolated-isolation-apiserver"}
+		Expect(reconciler.Get(ctx, apiServerKey, apiServerPolicy)).To(Succeed())
+		Expect(apiServerPolicy.Spec.PodSelector).To(Equal(metav1.LabelSelector{
+			MatchLabels: map[string]string{"app": "vcluster", "release": "isolated"},
+		}))
+		Expect(apiServerPolicy.Spec.Ingress).To(HaveLen(1))
+		Expect(apiServerPolicy.Spec.Ingress[0].From).To(BeEmpty())
+		Expect(apiServerPolicy.Spec.Ingress[0].Ports).To(ConsistOf(HaveField("Port", HaveValue(Equal(intstr.FromInt32(8443))))))
+		Expect(reconciler.requestsForIsolationObject(ctx, apiServerPolicy)).To(ConsistOf(reconcile.Request{
+			NamespacedName: client.ObjectKeyFromObject(vc),
+		}))
+
+		vc.Spec.Isolation.NetworkPolicy = &corev1alpha1.IsolationNetworkPolicy{Enabled: ptr.To(false)}
+		Expect(reconciler.reconcileIsolation(ctx, vc)).To(Succeed())
+		err := reconciler.Get(ctx, apiServerKey, &networkingv1.NetworkPolicy{})
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should delete disabled resources and all of them once isolation is removed", func() {
+		newReconciler()
+		Expect(reconciler.reconcileIsolation(ctx, vc)).To(Succeed())
+
+		vc.Spec.Isolation.NetworkPolicy = &corev1alpha1.IsolationNetworkPolicy{Enabled: ptr.To(false)}
+		Expect(reconciler.reconcileIsolation(ctx, vc)).To(Succeed())
+		err := reconciler.Get(ctx, key("tenant-a"), &networkingv1.NetworkPolicy{})
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+		Expect(vc.Status.Isolation.NetworkPolicy).To(BeEmpty())
+
+		vc.Spec.Isolation = nil
+		Expect(reconciler.reconcileIsolation(ctx, vc)).To(Succeed())
+		err = reconciler.Get(ctx, key("tenant-a"), &corev1.ResourceQuota{})
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+		err = reconciler.Get(ctx, key("tenant-a"), &corev1.LimitRange{})
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+		Expect(vc.Status.Isolation).To(BeNil())
+		Expect(meta.FindStatusCondition(vc.Status.Conditions, VirtualClusterConditionIsolated)).To(BeNil())
+	})
+
+	It("should not take over an existing ResourceQuota", func() {
+		quota := labeledQuota("tenant-a")
+		quota.Labels = nil
+		newReconciler(quota)
+
+		err := reconciler.reconcileIsolation(ctx, vc)
+		Expect(err).To(MatchError(ContainSubstring("ResourceQuota tenant-a/isolated-isolation already exists and is not managed by the VirtualCluster")))
+	})
+
+	It("should report an exhausted quota", func() {
+		quota := labeledQuota("tenant-a")
+		quota.Status = corev1.ResourceQuotaStatus{
+			Hard: corev1.ResourceList{
+				corev1.ResourcePods:     resource.MustParse("50"),
+				corev1.ResourceServices: resource.MustParse("20"),
+			},
+			Used: corev1.ResourceList{
+				corev1.ResourcePods:     resource.MustParse("50"),
+				corev1.ResourceServices: resource.MustParse("3"),
+			},
+		}
+		newReconciler(quota)
+		Expect(reconciler.reconcileIsolation(ctx, vc)).To(Succeed())
+
+		Expect(vc.Status.Isolation.Used).To(HaveKeyWithValue(corev1.ResourcePods, resource.MustParse("50")))
+		condition := meta.FindStatusCondition(vc.Status.Conditions, VirtualClusterConditionIsolated)
+		Expect(condition.Reason).To(Equal(isolationReasonQuotaExhausted))
+		Expect(condition.Message).To(ContainSubstring("exhausted for pods"))
+		Expect(recorder.Events).To(Receive(ContainSubstring("Warning QuotaExhausted")))
+
+		// The event is only recorded once
+		Expect(reconciler.reconcileIsolation(ctx, vc)).To(Succeed())
+		Expect(recorder.Events).NotTo(Receive())
+	})
+
+	It("should delete the resources in a target namespace when finalized", func() {
+		vc.Spec.TargetNamespace = &corev1alpha1.TargetNamespaceSpec{Name: "tenant-a-workloads"}
+		vc.Spec.DeletionPolicy = corev1alpha1.DeletionPolicyRetain
+		newReconciler()
+		Expect(reconciler.reconcileIsolation(ctx, vc)).To(Succeed())
+
+		Expect(reconciler.finalizeVirtualCluster(ctx, vc)).To(Succeed())
+		err := reconciler.Get(ctx, key("tenant-a-workloads"), &corev1.ResourceQuota{})
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+	})
+})
@@ -0,0 +1,323 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/clientcmd"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	corev1alpha1 "github.com/OpenVirtualCluster/openvirtualcluster-operator/api/v1alpha1"
+)
+
+const (
+	// defaultKubeconfigKey is the key holding the exported kubeconfig
+	defaultKubeconfigKey = "config"
+
+	// Labels of the objects created for a VirtualCluster, used to find the
+	// kubeconfig copies and target namespace, which can't be owned by it
+	virtualClusterNameLabel      = "core.openvc.dev/virtualcluster-name"
+	virtualClusterNamespaceLabel = "core.openvc.dev/virtualcluster-namespace"
+
+	// kubeconfigCopiesFromAnnotation opts a namespace in to receive kubeconfig
+	// copies. It holds a comma separated list of the namespaces of the
+	// VirtualClusters allowed to copy their kubeconfig into it, or "*".
+	kubeconfigCopiesFromAnnotation = "core.openvc.dev/kubeconfig-copies-from"
+)
+
+// kubeconfigSourceSecretName returns the name of the Secret the vcluster
+// syncer writes the kubeconfig to.
+func kubeconfigSourceSecretName(vcluster *corev1alpha1.VirtualCluster) string {
+	return "vc-" + vcluster.Name
+}
+
+// kubeconfigSecretName returns the name of the Secret the kubeconfig is
+// exported to.
+func kubeconfigSecretName(vcluster *corev1alpha1.VirtualCluster) string {
+	return vcluster.Name + "-kubeconfig"
+}
+
+// exportKubeconfig rewrites the kubeconfig written by the vcluster syncer and
+// stores it in the kubeconfig Secret and the Secrets of spec.kubeconfig.copyTo.
+// It returns false if the kubeconfig or the endpoint isn't available yet.
+func (r *VirtualClusterReconciler) exportKubeconfig(ctx context.Context, vcluster *corev1alpha1.VirtualCluster, class *corev1alpha1.VirtualClusterClass) (bool, error) {
+	logger := log.FromContext(ctx)
+
+	source := &corev1.Secret{}
+	err := r.Get(ctx, client.ObjectKey{Namespace: vcluster.ReleaseNamespace(), Name: kubeconfigSourceSecretName(vcluster)}, source)
+	if errors.IsNotFound(err) || (err == nil && len(source.Data[defaultKubeconfigKey]) == 0) {
+		logger.Info("Waiting for the kubeconfig of the vCluster", "secret", kubeconfigSourceSecretName(vcluster))
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	server, err := r.kubeconfigServer(ctx, vcluster, class)
+	if err != nil {
+		return false, err
+	}
+	if server == "" {
+		logger.Info("Waiting for the external endpoint of the vCluster")
+		return false, nil
+	}
+
+	var spec corev1alpha1.KubeconfigSpec
+	if vcluster.Spec.Kubeconfig != nil {
+		spec = *vcluster.Spec.Kubeconfig
+	}
+	kubeconfig, err := rewriteKubeconfig(source.Data[defaultKubeconfigKey], server, spec.ContextName)
+	if err != nil {
+		return false, err
+	}
+
+	// The Secret next to the VirtualCluster is owned by it
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: vcluster.Namespace, Name: kubeconfigSecretName(vcluster)}}
+	if err := r.writeKubeconfigSecret(ctx, vcluster, secret, defaultKubeconfigKey, kubeconfig, true); err != nil {
+		return false, err
+	}
+	vcluster.Status.KubeconfigSecretRef = &corev1alpha1.SecretKeyReference{
+		Name: secret.Name,
+		Key:  defaultKubeconfigKey,
+	}
+
+	desired := map[client.ObjectKey]bool{client.ObjectKeyFromObject(secret): true}
+	for _, target := range spec.CopyTo {
+		copied := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: target.Namespace, Name: target.Name}}
+		if copied.Namespace == "" {
+			copied.Namespace = vcluster.Namespace
+		}
+		allowed, err := r.kubeconfigCopyAllowed(ctx, vcluster, copied.Namespace)
+		if err != nil {
+			return false, err
+		}
+		if !allowed {
+			// Copies already written are deleted below, as they aren't desired
+			logger.Info("Namespace doesn't accept kubeconfig copies", "namespace", copied.Namespace)
+			r.Recorder.Event(vcluster, corev1.EventTypeWarning, "KubeconfigCopyRefused",
+				fmt.Sprintf("Namespace %s doesn't accept kubeconfig copies from namespace %s, annotate it with %s",
+					copied.Namespace, vcluster.Namespace, kubeconfigCopiesFromAnnotation))
+			continue
+		}
+		key := target.Key
+		if key == "" {
+			key = defaultKubeconfigKey
+		}
+		if err := r.writeKubeconfigSecret(ctx, vcluster, copied, key, kubeconfig, false); err != nil {
+			return false, err
+		}
+		desired[client.ObjectKeyFromObject(copied)] = true
+	}
+
+	// Remove copies that were dropped from copyTo
+	return true, r.deleteKubeconfigCopies(ctx, vcluster, desired)
+}
+
+// writeKubeconfigSecret creates or updates a Secret holding the kubeconfig.
+// Secrets created by someone else are only written if they are labeled for
+// the VirtualCluster, so existing Secrets are never taken over silently.
+func (r *VirtualClusterReconciler) writeKubeconfigSecret(ctx context.Context, vcluster *corev1alpha1.VirtualCluster, secret *corev1.Secret, key string, kubeconfig []byte, owned bool) error {
+	result, err := controllerutil.CreateOrUpdate(ctx, r.Client, secret, func() error {
+		if secret.ResourceVersion != "" && !isKubeconfigSecretOf(secret, vcluster) {
+			return fmt.Errorf("secret %s/%s already exists and is not managed by the VirtualCluster", secret.Namespace, secret.Name)
+		}
+
+		if secret.Labels == nil {
+			secret.Labels = map[string]string{}
+		}
+		secret.Labels["app.kubernetes.io/managed-by"] = "openvc-controller"
+		secret.Labels[virtualClusterNameLabel] = vcluster.Name
+		secret.Labels[virtualClusterNamespaceLabel] = vcluster.Namespace
+
+		if secret.Data == nil {
+			secret.Data = map[string][]byte{}
+		}
+		secret.Data[key] = kubeconfig
+
+		if owned {
+			return ctrl.SetControllerReference(vcluster, secret, r.Scheme)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write kubeconfig Secret %s/%s: %w", secret.Namespace, secret.Name, err)
+	}
+
+	if result != controllerutil.OperationResultNone {
+		log.FromContext(ctx).Info("Exported kubeconfig", "secret", client.ObjectKeyFromObject(secret), "operation", result)
+	}
+	return nil
+}
+
+// deleteKubeconfigCopies deletes the kubeconfig Secrets of the VirtualCluster
+// that aren't desired.
+func (r *VirtualClusterReconciler) deleteKubeconfigCopies(ctx context.Context, vcluster *corev1alpha1.VirtualCluster, desired map[client.ObjectKey]bool) error {
+	secrets := &corev1.SecretList{}
+	if err := r.List(ctx, secrets, client.MatchingLabels{
+		virtualClusterNameLabel:      vcluster.Name,
+		virtualClusterNamespaceLabel: vcluster.Namespace,
+	}); err != nil {
+		return fmt.Errorf("failed to list kubeconfig Secrets: %w", err)
+	}
+
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		if desired[client.ObjectKeyFromObject(secret)] {
+			continue
+		}
+		if err := r.Delete(ctx, secret); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete kubeconfig Secret %s/%s: %w", secret.Namespace, secret.Name, err)
+		}
+		log.FromContext(ctx).Info("Deleted kubeconfig Secret", "secret", client.ObjectKeyFromObject(secret))
+	}
+	return nil
+}
+
+// kubeconfigCopyAllowed reports whether the kubeconfig of the VirtualCluster
+// may be copied to the namespace. The operator can write Secrets in every
+// namespace, so other namespaces than the one of the VirtualCluster have to
+// opt in with an annotation.
+func (r *VirtualClusterReconciler) kubeconfigCopyAllowed(ctx context.Context, vcluster *corev1alpha1.VirtualCluster, namespace string) (bool, error) {
+	if namespace == vcluster.Namespace {
+		return true, nil
+	}
+
+	ns := &corev1.Namespace{}
+	if err := r.Get(ctx, client.ObjectKey{Name: namespace}, ns); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get namespace %s: %w", namespace, err)
+	}
+	for _, allowed := range strings.Split(ns.Annotations[kubeconfigCopiesFromAnnotation], ",") {
+		allowed = strings.TrimSpace(allowed)
+		if allowed == "*" || allowed == vcluster.Namespace {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// isKubeconfigSecretOf returns true if the Secret holds a kubeconfig of the
+// VirtualCluster.
+func isKubeconfigSecretOf(secret *corev1.Secret, vcluster *corev1alpha1.VirtualCluster) bool {
+	return secret.Labels[virtualClusterNameLabel] == vcluster.Name &&
+		secret.Labels[virtualClusterNamespaceLabel] == vcluster.Namespace
+}
+
+// kubeconfigServer returns the server URL of the exported kubeconfig, or an
+// empty string if the external endpoint isn't known yet.
+func (r *VirtualClusterReconciler) kubeconfigServer(ctx context.Context, vcluster *corev1alpha1.VirtualCluster, class *corev1alpha1.VirtualClusterClass) (string, error) {
+	spec := vcluster.Spec.Kubeconfig
+	if spec != nil && spec.Server != "" {
+		return spec.Server, nil
+	}
+
+	service := &corev1.Service{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: vcluster.ReleaseNamespace(), Name: vcluster.Name}, service); err != nil {
+		return "", err
+	}
+	port := apiServerPort(service)
+
+	if spec == nil || spec.Endpoint != corev1alpha1.KubeconfigEndpointExternal {
+		return serverURL(fmt.Sprintf("%s.%s.svc", service.Name, service.Namespace), port), nil
+	}
+
+	for _, ingress := range service.Status.LoadBalancer.Ingress {
+		if ingress.Hostname != "" {
+			return serverURL(ingress.Hostname, port), nil
+		}
+		if ingress.IP != "" {
+			return serverURL(ingress.IP, port), nil
+		}
+	}
+
+	// Without a load balancer the API server may be exposed by an ingress
+	values, err := r.translateValues(ctx, vcluster, class)
+	if err != nil {
+		return "", err
+	}
+	enabled, _, _ := unstructured.NestedBool(values, "controlPlane", "ingress", "enabled")
+	host, _, _ := unstructured.NestedString(values, "controlPlane", "ingress", "host")
+	if enabled && host != "" {
+		return serverURL(host, 443), nil
+	}
+	return "", nil
+}
+
+// serverURL returns the HTTPS URL of a host and port.
+func serverURL(host string, port int32) string {
+	return "https://" + net.JoinHostPort(host, strconv.Itoa(int(port)))
+}
+
+// rewriteKubeconfig points all clusters of the kubeconfig at server and
+// renames the current context if contextName is set.
+func rewriteKubeconfig(data []byte, server, contextName string) ([]byte, error) {
+	config, err := clientcmd.Load(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse kubeconfig: %w", err)
+	}
+
+	for _, cluster := range config.Clusters {
+		cluster.Server = server
+	}
+
+	if contextName != "" && contextName != config.CurrentContext {
+		if current, ok := config.Contexts[config.CurrentContext]; ok {
+			delete(config.Contexts, config.CurrentContext)
+			config.Contexts[contextName] = current
+		}
+		config.CurrentContext = contextName
+	}
+
+	return clientcmd.Write(*config)
+}
+
+// requestsForKubeconfigSecret maps the kubeconfig Secret written by the
+// vcluster syncer to its VirtualCluster.
+func (r *VirtualClusterReconciler) requestsForKubeconfigSecret(ctx context.Context, obj client.Object) []reconcile.Request {
+	name, ok := strings.CutPrefix(obj.GetName(), "vc-")
+	if !ok {
+		return nil
+	}
+
+	key, err := r.virtualClusterKeyForRelease(ctx, obj.GetNamespace(), name)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Failed to get namespace of kubeconfig Secret", "name", obj.GetName())
+		return nil
+	}
+	if err := r.Get(ctx, key, &corev1alpha1.VirtualCluster{}); err != nil {
+		if !errors.IsNotFound(err) {
+			log.FromContext(ctx).Error(err, "Failed to get VirtualCluster of kubeconfig Secret", "name", obj.GetName())
+		}
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: key}}
+}
@@ -0,0 +1,244 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	corev1alpha1 "github.com/OpenVirtualCluster/openvirtualcluster-operator/api/v1alpha1"
+)
+
+var _ = Describe("Kubeconfig Export", func() {
+	var (
+		ctx        context.Context
+		reconciler *VirtualClusterReconciler
+		vc         *corev1alpha1.VirtualCluster
+	)
+
+	newReconciler := func(objs ...client.Object) {
+		reconciler = NewTestReconciler(&fakeReleaseEngine{}, nil, append(objs, vc)...)
+	}
+
+	// exportedKubeconfig returns the server and current context of the
+	// kubeconfig stored in the key of the Secret.
+	exportedKubeconfig := func(namespace, name, key string) (string, string) {
+		secret := &corev1.Secret{}
+		Expect(reconciler.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, secret)).To(Succeed())
+		Expect(secret.Data).To(HaveKey(key))
+
+		config, err := clientcmd.Load(secret.Data[key])
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config.Clusters).To(HaveLen(1))
+		for _, cluster := range config.Clusters {
+			return cluster.Server, config.CurrentContext
+		}
+		return "", ""
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		vc = CreateTestVirtualCluster("kubeconfig-test", "default", "")
+	})
+
+	It("should wait for the kubeconfig of the vCluster", func() {
+		newReconciler(readyControlPlane("kubeconfig-test", "default")[1])
+
+		exported, err := reconciler.exportKubeconfig(ctx, vc, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(exported).To(BeFalse())
+		Expect(vc.Status.KubeconfigSecretRef).To(BeNil())
+	})
+
+	It("should point the kubeconfig at the Service", func() {
+		newReconciler(readyControlPlane("kubeconfig-test", "default")...)
+
+		exported, err := reconciler.exportKubeconfig(ctx, vc, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(exported).To(BeTrue())
+		Expect(vc.Status.KubeconfigSecretRef).To(Equal(&corev1alpha1.SecretKeyReference{
+			Name: "kubeconfig-test-kubeconfig",
+			Key:  "config",
+		}))
+
+		server, context := exportedKubeconfig("default", "kubeconfig-test-kubeconfig", "config")
+		Expect(server).To(Equal("https://kubeconfig-test.default.svc:443"))
+		Expect(context).To(Equal("my-vcluster"))
+
+		secret := &corev1.Secret{}
+		Expect(reconciler.Get(ctx, client.ObjectKey{Namespace: "default", Name: "kubeconfig-test-kubeconfig"}, secret)).To(Succeed())
+		Expect(metav1.IsControlledBy(secret, vc)).To(BeTrue())
+	})
+
+	It("should apply the server and context name of the spec", func() {
+		vc.Spec.Kubeconfig = &corev1alpha1.KubeconfigSpec{
+			Server:      "https://vcluster.example.com",
+			ContextName: "dev",
+		}
+		newReconciler(readyControlPlane("kubeconfig-test", "default")...)
+
+		_, err := reconciler.exportKubeconfig(ctx, vc, nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		server, context := exportedKubeconfig("default", "kubeconfig-test-kubeconfig", "config")
+		Expect(server).To(Equal("https://vcluster.example.com"))
+		Expect(context).To(Equal("dev"))
+	})
+
+	Context("with the External endpoint", func() {
+		BeforeEach(func() {
+			vc.Spec.Kubeconfig = &corev1alpha1.KubeconfigSpec{Endpoint: corev1alpha1.KubeconfigEndpointExternal}
+		})
+
+		It("should use the load balancer address", func() {
+			objs := readyControlPlane("kubeconfig-test", "default")
+			objs[1].(*corev1.Service).Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: "203.0.113.10"}}
+			newReconciler(objs...)
+
+			exported, err := reconciler.exportKubeconfig(ctx, vc, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exported).To(BeTrue())
+
+			server, _ := exportedKubeconfig("default", "kubeconfig-test-kubeconfig", "config")
+			Expect(server).To(Equal("https://203.0.113.10:443"))
+		})
+
+		It("should use the ingress host", func() {
+			vc.Spec.Values = &apiextensionsv1.JSON{Raw: []byte(`{"controlPlane": {"ingress": {"enabled": true, "host": "vc.example.com"}}}`)}
+			newReconciler(readyControlPlane("kubeconfig-test", "default")...)
+
+			_, err := reconciler.exportKubeconfig(ctx, vc, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			server, _ := exportedKubeconfig("default", "kubeconfig-test-kubeconfig", "config")
+			Expect(server).To(Equal("https://vc.example.com:443"))
+		})
+
+		It("should wait for the endpoint", func() {
+			newReconciler(readyControlPlane("kubeconfig-test", "default")...)
+
+			exported, err := reconciler.exportKubeconfig(ctx, vc, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exported).To(BeFalse())
+		})
+	})
+
+	Context("with copies", func() {
+		var ci *corev1.Namespace
+
+		BeforeEach(func() {
+			ci = &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name:        "ci",
+				Annotations: map[string]string{kubeconfigCopiesFromAnnotation: "staging, default"},
+			}}
+			vc.Spec.Kubeconfig = &corev1alpha1.KubeconfigSpec{CopyTo: []corev1alpha1.KubeconfigTarget{
+				{Namespace: "ci", Name: "vcluster-access", Key: "kubeconfig.yaml"},
+				{Name: "local-copy"},
+			}}
+		})
+
+		It("should copy the kubeconfig with the custom key", func() {
+			newReconciler(append(readyControlPlane("kubeconfig-test", "default"), ci)...)
+
+			_, err := reconciler.exportKubeconfig(ctx, vc, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			server, _ := exportedKubeconfig("ci", "vcluster-access", "kubeconfig.yaml")
+			Expect(server).To(Equal("https://kubeconfig-test.default.svc:443"))
+			exportedKubeconfig("default", "local-copy", "config")
+		})
+
+		It("should delete copies removed from the spec and on finalization", func() {
+			newReconciler(append(readyControlPlane("kubeconfig-test", "default"), ci)...)
+			_, err := reconciler.exportKubeconfig(ctx, vc, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			vc.Spec.Kubeconfig.CopyTo = vc.Spec.Kubeconfig.CopyTo[:1]
+			_, err = reconciler.exportKubeconfig(ctx, vc, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = reconciler.Get(ctx, client.ObjectKey{Namespace: "default", Name: "local-copy"}, &corev1.Secret{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+
+			Expect(reconciler.finalizeVirtualCluster(ctx, vc)).To(Succeed())
+			err = reconciler.Get(ctx, client.ObjectKey{Namespace: "ci", Name: "vcluster-access"}, &corev1.Secret{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should not overwrite Secrets it doesn't manage", func() {
+			objs := readyControlPlane("kubeconfig-test", "default")
+			objs = append(objs, ci, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ci", Name: "vcluster-access"},
+				Data:       map[string][]byte{"token": []byte("secret")},
+			})
+			newReconciler(objs...)
+
+			_, err := reconciler.exportKubeconfig(ctx, vc, nil)
+			Expect(err).To(MatchError(ContainSubstring("not managed by the VirtualCluster")))
+		})
+
+		It("should refuse copies to namespaces that didn't opt in", func() {
+			ci.Annotations = nil
+			kubeSystem := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}}
+			vc.Spec.Kubeconfig.CopyTo = append(vc.Spec.Kubeconfig.CopyTo,
+				corev1alpha1.KubeconfigTarget{Namespace: "kube-system", Name: "bootstrap-token"})
+			newReconciler(append(readyControlPlane("kubeconfig-test", "default"), ci, kubeSystem)...)
+
+			exported, err := reconciler.exportKubeconfig(ctx, vc, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exported).To(BeTrue())
+
+			err = reconciler.Get(ctx, client.ObjectKey{Namespace: "ci", Name: "vcluster-access"}, &corev1.Secret{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+			err = reconciler.Get(ctx, client.ObjectKey{Namespace: "kube-system", Name: "bootstrap-token"}, &corev1.Secret{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+			exportedKubeconfig("default", "local-copy", "config")
+			Expect(reconciler.Recorder.(*record.FakeRecorder).Events).To(Receive(ContainSubstring("KubeconfigCopyRefused")))
+		})
+
+		It("should delete copies once the namespace opts out", func() {
+			newReconciler(append(readyControlPlane("kubeconfig-test", "default"), ci)...)
+			_, err := reconciler.exportKubeconfig(ctx, vc, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(ci), ci)).To(Succeed())
+			ci.Annotations[kubeconfigCopiesFromAnnotation] = "staging"
+			Expect(reconciler.Update(ctx, ci)).To(Succeed())
+			_, err = reconciler.exportKubeconfig(ctx, vc, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = reconciler.Get(ctx, client.ObjectKey{Namespace: "ci", Name: "vcluster-access"}, &corev1.Secret{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+	})
+})
+
+var _ = Describe("rewriteKubeconfig", func() {
+	It("should reject invalid kubeconfigs", func() {
+		_, err := rewriteKubeconfig([]byte("clusters: ["), "https://example.com", "")
+		Expect(err).To(HaveOccurred())
+	})
+})
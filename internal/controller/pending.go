@@ -0,0 +1,132 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"helm.sh/helm/v3/pkg/release"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	corev1alpha1 "github.com/OpenVirtualCluster/openvirtualcluster-operator/api/v1alpha1"
+	"github.com/OpenVirtualCluster/openvirtualcluster-operator/internal/helm"
+)
+
+// Reasons of the ReleaseRecovered condition
+const (
+	reasonPendingReleaseRolledBack = "PendingReleaseRolledBack"
+	reasonPendingReleaseRemoved    = "PendingReleaseRemoved"
+)
+
+// pendingReleaseThreshold returns how long a release may stay pending before
+// it is considered stuck. It is never shorter than the timeout of the Helm
+// operations, so operations still in progress are left alone.
+func (r *VirtualClusterReconciler) pendingReleaseThreshold(vcluster *corev1alpha1.VirtualCluster, class *corev1alpha1.VirtualClusterClass) time.Duration {
+	return max(r.PendingReleaseTimeout, r.operationOptions(vcluster, class).Timeout)
+}
+
+// recoverPendingRelease recovers a release left in a pending state, e.g. by an
+// operator restart during an install or upgrade. Helm refuses to upgrade such
+// a release, so once it is pending for longer than the threshold it is rolled
+// back to the last deployed revision, or, if there is none, the pending
+// revision is removed so the next operation can proceed.
+func (r *VirtualClusterReconciler) recoverPendingRelease(ctx context.Context, vcluster *corev1alpha1.VirtualCluster, class *corev1alpha1.VirtualClusterClass) error {
+	if r.PendingReleaseTimeout <= 0 {
+		return nil
+	}
+	logger := log.FromContext(ctx)
+
+	rel, err := r.Helm.Get(ctx, vcluster.ReleaseNamespace(), vcluster.Name)
+	if helm.IsReleaseNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if rel.Info == nil || !rel.Info.Status.IsPending() {
+		return nil
+	}
+
+	pendingFor := time.Since(rel.Info.LastDeployed.Time)
+	if pendingFor < r.pendingReleaseThreshold(vcluster, class) {
+		logger.Info("Helm release has an operation in progress", "revision", rel.Version,
+			"status", rel.Info.Status, "pendingFor", pendingFor.Round(time.Second))
+		return nil
+	}
+
+	reason, message, err := r.resolvePendingRelease(ctx, vcluster, class, rel)
+	if err != nil {
+		logger.Error(err, "Failed to recover the pending Helm release", "revision", rel.Version, "status", rel.Info.Status)
+		r.Recorder.Event(vcluster, corev1.EventTypeWarning, "PendingReleaseRecoveryFailed",
+			fmt.Sprintf("Failed to recover revision %d stuck in %s: %v", rel.Version, rel.Info.Status, err))
+		return err
+	}
+	logger.Info("Recovered the pending Helm release", "revision", rel.Version, "status", rel.Info.Status, "reason", reason)
+
+	// Re-apply the spec on top of the recovered release
+	vcluster.Status.AppliedHash = ""
+	r.recordHistory(ctx, vcluster, class)
+	meta.SetStatusCondition(&vcluster.Status.Conditions, metav1.Condition{
+		Type:    VirtualClusterConditionReleaseRecovered,
+		Status:  metav1.ConditionTrue,
+		Reason:  reason,
+		Message: message,
+	})
+	r.Recorder.Event(vcluster, corev1.EventTypeWarning, "PendingReleaseRecovered", message)
+	return nil
+}
+
+// resolvePendingRelease rolls a pending upgrade or rollback back to the last
+// deployed revision. A pending install, or a release that was never deployed,
+// has nothing to roll back to, so its pending revision is removed instead.
+func (r *VirtualClusterReconciler) resolvePendingRelease(ctx context.Context, vcluster *corev1alpha1.VirtualCluster, class *corev1alpha1.VirtualClusterClass, pending *release.Release) (string, string, error) {
+	if pending.Info.Status != release.StatusPendingInstall {
+		releases, err := r.Helm.History(ctx, vcluster.ReleaseNamespace(), vcluster.Name)
+		if err != nil {
+			return "", "", err
+		}
+
+		for i := len(releases) - 1; i >= 0; i-- {
+			rel := releases[i]
+			if rel.Version >= pending.Version || rel.Info == nil || rel.Info.Status != release.StatusDeployed {
+				continue
+			}
+
+			if _, err := r.Helm.Rollback(ctx, helm.RollbackRequest{
+				Name:      vcluster.Name,
+				Namespace: vcluster.ReleaseNamespace(),
+				Revision:  rel.Version,
+				Options:   r.operationOptions(vcluster, class),
+			}); err != nil {
+				return "", "", err
+			}
+			return reasonPendingReleaseRolledBack, fmt.Sprintf("Revision %d was stuck in %s and was rolled back to revision %d",
+				pending.Version, pending.Info.Status, rel.Version), nil
+		}
+	}
+
+	if err := r.Helm.DeleteRevision(ctx, vcluster.ReleaseNamespace(), vcluster.Name, pending.Version); err != nil {
+		return "", "", err
+	}
+	return reasonPendingReleaseRemoved, fmt.Sprintf("Revision %d was stuck in %s and was removed",
+		pending.Version, pending.Info.Status), nil
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/release"
+	helmtime "helm.sh/helm/v3/pkg/time"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	corev1alpha1 "github.com/OpenVirtualCluster/openvirtualcluster-operator/api/v1alpha1"
+	"github.com/OpenVirtualCluster/openvirtualcluster-operator/internal/helm"
+)
+
+var _ = Describe("Pending Release Recovery", func() {
+	var (
+		ctx        context.Context
+		engine     *fakeReleaseEngine
+		recorder   *record.FakeRecorder
+		reconciler *VirtualClusterReconciler
+		vc         *corev1alpha1.VirtualCluster
+		values     map[string]interface{}
+	)
+
+	// interrupt records a revision left in status by an interrupted operation
+	// that started age ago
+	interrupt := func(status release.Status, age time.Duration) *release.Release {
+		rel := engine.record(fakeRelease(helm.ReleaseRequest{
+			Name:      vc.Name,
+			Namespace: vc.Namespace,
+			Chart:     helm.ChartRef{Version: "v0.25.0"},
+		}, status))
+		rel.Info.LastDeployed = helmtime.Time{Time: time.Now().Add(-age)}
+		return rel
+	}
+
+	recovered := func() *metav1.Condition {
+		return meta.FindStatusCondition(vc.Status.Conditions, VirtualClusterConditionReleaseRecovered)
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		vc = CreateTestVirtualCluster("pending-test", "default", "")
+		vc.Spec.Validation = &corev1alpha1.ValidationSpec{Mode: corev1alpha1.ValidationModeDisabled}
+
+		engine = &fakeReleaseEngine{}
+		recorder = record.NewFakeRecorder(10)
+		reconciler = NewTestReconciler(engine, recorder, vc)
+		reconciler.PendingReleaseTimeout = 15 * time.Minute
+
+		var err error
+		values, err = reconciler.translateValues(ctx, vc, nil)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	Context("with a deployed revision", func() {
+		BeforeEach(func() {
+			Expect(reconciler.installOrUpgradeVCluster(ctx, vc, nil, values)).To(Succeed())
+			engine.exists = true
+			vc.Spec.Chart.Version = "v0.25.0"
+		})
+
+		It("should roll back a stuck upgrade", func() {
+			interrupt(release.StatusPendingUpgrade, time.Hour)
+
+			Expect(reconciler.installOrUpgradeVCluster(ctx, vc, nil, values)).To(Succeed())
+			Expect(engine.rolledBack).To(HaveLen(1))
+			Expect(engine.rolledBack[0].Revision).To(Equal(1))
+			Expect(engine.deleted).To(BeEmpty())
+
+			// The spec is applied on top of the rolled back release
+			Expect(engine.upgraded).To(HaveLen(1))
+			Expect(vc.Status.History[0].Revision).To(Equal(int32(4)))
+			Expect(vc.Status.History[0].Outcome).To(Equal("deployed"))
+
+			cond := recovered()
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+			Expect(cond.Reason).To(Equal("PendingReleaseRolledBack"))
+			Expect(cond.Message).To(ContainSubstring("Revision 2 was stuck in pending-upgrade"))
+			Expect(recorder.Events).To(Receive(ContainSubstring("PendingReleaseRecovered")))
+		})
+
+		It("should leave an operation in progress alone", func() {
+			interrupt(release.StatusPendingUpgrade, time.Minute)
+
+			Expect(reconciler.recoverPendingRelease(ctx, vc, nil)).To(Succeed())
+			Expect(engine.rolledBack).To(BeEmpty())
+			Expect(engine.deleted).To(BeEmpty())
+			Expect(recovered()).To(BeNil())
+		})
+
+		It("should wait for the timeout of the upgrade policy", func() {
+			vc.Spec.UpgradePolicy = &corev1alpha1.UpgradePolicy{Timeout: &metav1.Duration{Duration: 2 * time.Hour}}
+			interrupt(release.StatusPendingUpgrade, time.Hour)
+
+			Expect(reconciler.recoverPendingRelease(ctx, vc, nil)).To(Succeed())
+			Expect(engine.rolledBack).To(BeEmpty())
+		})
+
+		It("should not recover releases when disabled", func() {
+			reconciler.PendingReleaseTimeout = 0
+			interrupt(release.StatusPendingRollback, time.Hour)
+
+			Expect(reconciler.recoverPendingRelease(ctx, vc, nil)).To(Succeed())
+			Expect(engine.rolledBack).To(BeEmpty())
+			Expect(engine.deleted).To(BeEmpty())
+		})
+
+		It("should report a failed recovery", func() {
+			interrupt(release.StatusPendingUpgrade, time.Hour)
+			engine.rollbackErr = context.DeadlineExceeded
+
+			Expect(reconciler.installOrUpgradeVCluster(ctx, vc, nil, values)).To(MatchError(context.DeadlineExceeded))
+			Expect(engine.upgraded).To(BeEmpty())
+			Expect(recorder.Events).To(Receive(ContainSubstring("PendingReleaseRecoveryFailed")))
+		})
+	})
+
+	It("should remove a stuck install and install again", func() {
+		interrupt(release.StatusPendingInstall, time.Hour)
+
+		Expect(reconciler.installOrUpgradeVCluster(ctx, vc, nil, values)).To(Succeed())
+		Expect(engine.deleted).To(Equal([]int{1}))
+		Expect(engine.rolledBack).To(BeEmpty())
+		Expect(engine.installed).To(HaveLen(1))
+
+		Expect(recovered().Reason).To(Equal("PendingReleaseRemoved"))
+		Expect(recorder.Events).To(Receive(ContainSubstring("Revision 1 was stuck in pending-install and was removed")))
+	})
+})
@@ -0,0 +1,229 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	corev1alpha1 "github.com/OpenVirtualCluster/openvirtualcluster-operator/api/v1alpha1"
+)
+
+const (
+	// controlPlaneRequeueInterval is how often an unhealthy control plane is
+	// checked again. Changes of its workload and Service trigger a reconcile
+	// earlier.
+	controlPlaneRequeueInterval = 10 * time.Second
+
+	// Annotations Helm sets on the objects of a release
+	helmReleaseNameAnnotation      = "meta.helm.sh/release-name"
+	helmReleaseNamespaceAnnotation = "meta.helm.sh/release-namespace"
+)
+
+// Reasons of the Available and Degraded conditions
+const (
+	healthReasonReady                = "ControlPlaneReady"
+	healthReasonControlPlaneMissing  = "ControlPlaneMissing"
+	healthReasonControlPlaneNotReady = "ControlPlaneNotReady"
+	healthReasonServiceMissing       = "ServiceMissing"
+	healthReasonAPIServerNotReady    = "APIServerNotReady"
+)
+
+// APIServerProber probes the API server of a virtual cluster.
+type APIServerProber interface {
+	// Probe returns an error unless the endpoint at url reports ready.
+	Probe(ctx context.Context, url string) error
+}
+
+// httpAPIServerProber probes API servers over HTTPS.
+type httpAPIServerProber struct {
+	client *http.Client
+}
+
+// NewAPIServerProber returns a prober calling the endpoint over HTTPS.
+func NewAPIServerProber(timeout time.Duration) APIServerProber {
+	return &httpAPIServerProber{
+		client: &http.Client{
+			Timeout: timeout,
+			Transport: &http.Transport{
+				// The serving certificate is issued by the virtual cluster itself.
+				// Only readiness is read and no credentials are sent, so it isn't
+				// verified.
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: true}, //nolint:gosec
+			},
+		},
+	}
+}
+
+func (p *httpAPIServerProber) Probe(ctx context.Context, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 256))
+		return fmt.Errorf("HTTP status code %d: %s", resp.StatusCode, body)
+	}
+	return nil
+}
+
+// controlPlaneHealth is the health of the control plane of a virtual cluster.
+type controlPlaneHealth struct {
+	Ready   bool
+	Reason  string
+	Message string
+}
+
+// checkControlPlane checks that the StatefulSet or Deployment of the release
+// is ready and, if a prober is configured, that the API server reports ready
+// through its Service.
+func (r *VirtualClusterReconciler) checkControlPlane(ctx context.Context, vcluster *corev1alpha1.VirtualCluster) (controlPlaneHealth, error) {
+	key := client.ObjectKey{Namespace: vcluster.ReleaseNamespace(), Name: vcluster.Name}
+
+	// The chart deploys the control plane as a StatefulSet, or as a Deployment
+	// without persistence
+	var desired, ready int32
+	statefulSet := &appsv1.StatefulSet{}
+	err := r.Get(ctx, key, statefulSet)
+	switch {
+	case err == nil:
+		desired, ready = replicasOf(statefulSet.Spec.Replicas), statefulSet.Status.ReadyReplicas
+		if statefulSet.Status.ObservedGeneration < statefulSet.Generation || statefulSet.Status.UpdatedReplicas < desired {
+			ready = 0
+		}
+	case errors.IsNotFound(err):
+		deployment := &appsv1.Deployment{}
+		if err := r.Get(ctx, key, deployment); err != nil {
+			if errors.IsNotFound(err) {
+				return controlPlaneHealth{
+					Reason:  healthReasonControlPlaneMissing,
+					Message: fmt.Sprintf("No StatefulSet or Deployment %s found", vcluster.Name),
+				}, nil
+			}
+			return controlPlaneHealth{}, err
+		}
+		desired, ready = replicasOf(deployment.Spec.Replicas), deployment.Status.ReadyReplicas
+		if deployment.Status.ObservedGeneration < deployment.Generation || deployment.Status.UpdatedReplicas < desired {
+			ready = 0
+		}
+	default:
+		return controlPlaneHealth{}, err
+	}
+
+	if ready < desired || desired == 0 {
+		return controlPlaneHealth{
+			Reason:  healthReasonControlPlaneNotReady,
+			Message: fmt.Sprintf("%d of %d control plane replicas are ready", ready, desired),
+		}, nil
+	}
+
+	service := &corev1.Service{}
+	if err := r.Get(ctx, key, service); err != nil {
+		if errors.IsNotFound(err) {
+			return controlPlaneHealth{
+				Reason:  healthReasonServiceMissing,
+				Message: fmt.Sprintf("Service %s not found", vcluster.Name),
+			}, nil
+		}
+		return controlPlaneHealth{}, err
+	}
+
+	if r.Prober != nil {
+		if err := r.Prober.Probe(ctx, apiServerURL(service)+"/readyz"); err != nil {
+			return controlPlaneHealth{
+				Reason:  healthReasonAPIServerNotReady,
+				Message: fmt.Sprintf("API server is not ready: %v", err),
+			}, nil
+		}
+	}
+
+	return controlPlaneHealth{
+		Ready:   true,
+		Reason:  healthReasonReady,
+		Message: "Control plane is ready",
+	}, nil
+}
+
+// replicasOf returns the desired replicas, which default to one.
+func replicasOf(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
+
+// apiServerURL returns the in-cluster URL of the API server behind the
+// Service.
+func apiServerURL(service *corev1.Service) string {
+	return fmt.Sprintf("https://%s.%s.svc:%d", service.Name, service.Namespace, apiServerPort(service))
+}
+
+// apiServerPort returns the port of the Service named https, falling back
+// to the first port and then to 443.
+func apiServerPort(service *corev1.Service) int32 {
+	for _, port := range service.Spec.Ports {
+		if port.Name == "https" {
+			return port.Port
+		}
+	}
+	if len(service.Spec.Ports) > 0 {
+		return service.Spec.Ports[0].Port
+	}
+	return 443
+}
+
+// requestsForReleaseObject maps an object deployed by a Helm release to the
+// VirtualCluster of the release.
+func (r *VirtualClusterReconciler) requestsForReleaseObject(ctx context.Context, obj client.Object) []reconcile.Request {
+	annotations := obj.GetAnnotations()
+	name := annotations[helmReleaseNameAnnotation]
+	namespace := annotations[helmReleaseNamespaceAnnotation]
+	if name == "" || namespace == "" {
+		return nil
+	}
+
+	key, err := r.virtualClusterKeyForRelease(ctx, namespace, name)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Failed to get namespace of release object", "name", obj.GetName())
+		return nil
+	}
+	if err := r.Get(ctx, key, &corev1alpha1.VirtualCluster{}); err != nil {
+		if !errors.IsNotFound(err) {
+			log.FromContext(ctx).Error(err, "Failed to get VirtualCluster of release object", "name", obj.GetName())
+		}
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: key}}
+}
@@ -0,0 +1,252 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	corev1alpha1 "github.com/OpenVirtualCluster/openvirtualcluster-operator/api/v1alpha1"
+)
+
+// fakeProber records the probed URLs and fails them with err.
+type fakeProber struct {
+	err    error
+	probed []string
+}
+
+func (p *fakeProber) Probe(_ context.Context, url string) error {
+	p.probed = append(p.probed, url)
+	return p.err
+}
+
+// testKubeconfig is a kubeconfig as written by the vcluster syncer.
+const testKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: my-vcluster
+  cluster:
+    server: https://localhost:8443
+    certificate-authority-data: Y2E=
+contexts:
+- name: my-vcluster
+  context:
+    cluster: my-vcluster
+    user: my-vcluster
+current-context: my-vcluster
+users:
+- name: my-vcluster
+  user:
+    client-certificate-data: Y2VydA==
+    client-key-data: a2V5
+`
+
+// readyControlPlane returns the ready StatefulSet, the Service and the
+// kubeconfig Secret the chart deploys for a release.
+func readyControlPlane(name, namespace string) []client.Object {
+	annotations := map[string]string{
+		helmReleaseNameAnnotation:      name,
+		helmReleaseNamespaceAnnotation: namespace,
+	}
+	return []client.Object{
+		&appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Annotations: annotations},
+			Spec:       appsv1.StatefulSetSpec{Replicas: ptr.To[int32](1)},
+			Status:     appsv1.StatefulSetStatus{ReadyReplicas: 1, UpdatedReplicas: 1},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Annotations: annotations},
+			Spec: corev1.ServiceSpec{Ports: []corev1.ServicePort{
+				{Name: "kubelet", Port: 10250},
+				{Name: "https", Port: 443},
+			}},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "vc-" + name, Namespace: namespace},
+			Data:       map[string][]byte{"config": []byte(testKubeconfig)},
+		},
+	}
+}
+
+var _ = Describe("Control Plane Readiness", func() {
+	var (
+		ctx        context.Context
+		engine     *fakeReleaseEngine
+		prober     *fakeProber
+		recorder   *record.FakeRecorder
+		reconciler *VirtualClusterReconciler
+		vc         *corev1alpha1.VirtualCluster
+	)
+
+	newReconciler := func(objs ...client.Object) {
+		objs = append(objs, vc, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "vcluster-schema-v0-24-1", Namespace: "default"},
+			Data:       map[string]string{"values.schema.json": `{"type": "object"}`},
+		})
+		reconciler = NewTestReconciler(engine, recorder, objs...)
+		reconciler.Prober = prober
+	}
+
+	reconcileVirtualCluster := func() (reconcile.Result, *corev1alpha1.VirtualCluster) {
+		result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(vc)})
+		Expect(err).NotTo(HaveOccurred())
+
+		updated := &corev1alpha1.VirtualCluster{}
+		Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(vc), updated)).To(Succeed())
+		return result, updated
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		vc = CreateTestVirtualCluster("readiness-test", "default", "")
+		vc.Finalizers = []string{vclusterFinalizer}
+		vc.Status.Phase = corev1alpha1.VirtualClusterProvisioning
+
+		engine = &fakeReleaseEngine{}
+		prober = &fakeProber{}
+		recorder = record.NewFakeRecorder(10)
+	})
+
+	AfterEach(func() {
+		os.Remove(filepath.Join(os.TempDir(), "values-readiness-test-default.yaml"))
+	})
+
+	It("should stay Provisioning until the control plane exists", func() {
+		newReconciler()
+
+		result, updated := reconcileVirtualCluster()
+		Expect(result.RequeueAfter).To(Equal(controlPlaneRequeueInterval))
+		Expect(engine.installed).To(HaveLen(1))
+		Expect(updated.Status.Phase).To(Equal(corev1alpha1.VirtualClusterProvisioning))
+
+		cond := meta.FindStatusCondition(updated.Status.Conditions, VirtualClusterConditionAvailable)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+		Expect(cond.Reason).To(Equal("ControlPlaneMissing"))
+		Expect(meta.FindStatusCondition(updated.Status.Conditions, VirtualClusterConditionDeploying).Reason).
+			To(Equal("WaitingForControlPlane"))
+	})
+
+	It("should stay Provisioning while the StatefulSet is not ready", func() {
+		objs := readyControlPlane("readiness-test", "default")
+		objs[0].(*appsv1.StatefulSet).Status.ReadyReplicas = 0
+		newReconciler(objs...)
+
+		_, updated := reconcileVirtualCluster()
+		Expect(updated.Status.Phase).To(Equal(corev1alpha1.VirtualClusterProvisioning))
+		Expect(meta.FindStatusCondition(updated.Status.Conditions, VirtualClusterConditionAvailable).Reason).
+			To(Equal("ControlPlaneNotReady"))
+		Expect(prober.probed).To(BeEmpty())
+	})
+
+	It("should stay Provisioning while the API server is not ready", func() {
+		prober.err = fmt.Errorf("connection refused")
+		newReconciler(readyControlPlane("readiness-test", "default")...)
+
+		_, updated := reconcileVirtualCluster()
+		Expect(updated.Status.Phase).To(Equal(corev1alpha1.VirtualClusterProvisioning))
+		Expect(meta.FindStatusCondition(updated.Status.Conditions, VirtualClusterConditionAvailable).Reason).
+			To(Equal("APIServerNotReady"))
+		Expect(prober.probed).To(ConsistOf("https://readiness-test.default.svc:443/readyz"))
+	})
+
+	It("should become Running when the control plane is healthy", func() {
+		newReconciler(readyControlPlane("readiness-test", "default")...)
+
+		_, updated := reconcileVirtualCluster()
+		Expect(updated.Status.Phase).To(Equal(corev1alpha1.VirtualClusterRunning))
+		Expect(meta.IsStatusConditionTrue(updated.Status.Conditions, VirtualClusterConditionAvailable)).To(BeTrue())
+		Expect(meta.IsStatusConditionFalse(updated.Status.Conditions, VirtualClusterConditionDegraded)).To(BeTrue())
+	})
+
+	It("should accept a ready Deployment", func() {
+		objs := readyControlPlane("readiness-test", "default")
+		objs[0] = &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "readiness-test", Namespace: "default"},
+			Status:     appsv1.DeploymentStatus{ReadyReplicas: 1, UpdatedReplicas: 1},
+		}
+		newReconciler(objs...)
+
+		_, updated := reconcileVirtualCluster()
+		Expect(updated.Status.Phase).To(Equal(corev1alpha1.VirtualClusterRunning))
+	})
+
+	It("should report a running VirtualCluster as Degraded", func() {
+		vc.Status.Phase = corev1alpha1.VirtualClusterRunning
+		objs := readyControlPlane("readiness-test", "default")
+		objs[0].(*appsv1.StatefulSet).Status.ReadyReplicas = 0
+		newReconciler(objs...)
+
+		_, updated := reconcileVirtualCluster()
+		Expect(updated.Status.Phase).To(Equal(corev1alpha1.VirtualClusterRunning))
+		Expect(meta.FindStatusCondition(updated.Status.Conditions, VirtualClusterConditionAvailable).Status).
+			To(Equal(metav1.ConditionFalse))
+
+		cond := meta.FindStatusCondition(updated.Status.Conditions, VirtualClusterConditionDegraded)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+		Expect(cond.Reason).To(Equal("ControlPlaneNotReady"))
+		Expect(recorder.Events).To(Receive(ContainSubstring("Degraded")))
+	})
+
+	It("should map release objects to their VirtualCluster", func() {
+		newReconciler()
+
+		objs := readyControlPlane("readiness-test", "default")
+		Expect(reconciler.requestsForReleaseObject(ctx, objs[0])).To(ConsistOf(reconcile.Request{
+			NamespacedName: client.ObjectKeyFromObject(vc),
+		}))
+
+		other := readyControlPlane("other", "default")
+		Expect(reconciler.requestsForReleaseObject(ctx, other[0])).To(BeEmpty())
+		Expect(reconciler.requestsForReleaseObject(ctx, &corev1.Service{})).To(BeEmpty())
+	})
+})
+
+var _ = Describe("APIServerProber", func() {
+	It("should succeed when the endpoint reports ready", func() {
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/readyz" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write([]byte("ok"))
+		}))
+		defer server.Close()
+
+		prober := NewAPIServerProber(time.Second)
+		Expect(prober.Probe(context.Background(), server.URL+"/readyz")).To(Succeed())
+		Expect(prober.Probe(context.Background(), server.URL+"/other")).To(MatchError(ContainSubstring("404")))
+	})
+})
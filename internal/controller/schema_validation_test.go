@@ -108,7 +108,7 @@ var _ = Describe("Schema Validation", func() {
 			}
 
 			// Validate against schema
-			err := reconciler.validateValuesAgainstSchema(ctx, vc, sampleSchema)
+			err := reconciler.validateValuesAgainstSchema(ctx, vc, nil, sampleSchema)
 			Expect(err).NotTo(HaveOccurred())
 		})
 
@@ -135,7 +135,7 @@ var _ = Describe("Schema Validation", func() {
 			}
 
 			// Validate against schema - should fail due to missing required field
-			err := reconciler.validateValuesAgainstSchema(ctx, vc, sampleSchema)
+			err := reconciler.validateValuesAgainstSchema(ctx, vc, nil, sampleSchema)
 			Expect(err).To(HaveOccurred())
 		})
 
@@ -163,7 +163,7 @@ var _ = Describe("Schema Validation", func() {
 			}
 
 			// Validate against schema - should fail due to wrong type
-			err := reconciler.validateValuesAgainstSchema(ctx, vc, sampleSchema)
+			err := reconciler.validateValuesAgainstSchema(ctx, vc, nil, sampleSchema)
 			Expect(err).To(HaveOccurred())
 		})
 
@@ -190,7 +190,7 @@ var _ = Describe("Schema Validation", func() {
 			}
 
 			// Validate against schema - should fail due to invalid enum value
-			err := reconciler.validateValuesAgainstSchema(ctx, vc, sampleSchema)
+			err := reconciler.validateValuesAgainstSchema(ctx, vc, nil, sampleSchema)
 			Expect(err).To(HaveOccurred())
 		})
 	})
@@ -0,0 +1,84 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	corev1alpha1 "github.com/OpenVirtualCluster/openvirtualcluster-operator/api/v1alpha1"
+)
+
+// Reasons of the Suspended condition
+const (
+	reasonSuspended = "Suspended"
+	reasonResumed   = "Resumed"
+)
+
+// reconcileSuspended reports a suspended VirtualCluster without touching its
+// release or removing its finalizer. It isn't requeued: clearing spec.suspend
+// changes the generation, which triggers the next reconcile.
+func (r *VirtualClusterReconciler) reconcileSuspended(ctx context.Context, vcluster *corev1alpha1.VirtualCluster) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	message := "Reconciliation is suspended"
+	if !vcluster.DeletionTimestamp.IsZero() {
+		message = "Reconciliation is suspended, the VirtualCluster is deleted once spec.suspend is cleared"
+	}
+
+	cond := meta.FindStatusCondition(vcluster.Status.Conditions, VirtualClusterConditionSuspended)
+	if cond != nil && cond.Status == metav1.ConditionTrue && cond.Message == message {
+		return ctrl.Result{}, nil
+	}
+
+	logger.Info("Reconciliation is suspended, skipping Helm operations")
+	meta.SetStatusCondition(&vcluster.Status.Conditions, metav1.Condition{
+		Type:    VirtualClusterConditionSuspended,
+		Status:  metav1.ConditionTrue,
+		Reason:  reasonSuspended,
+		Message: message,
+	})
+	if err := r.Status().Update(ctx, vcluster); err != nil {
+		logger.Error(err, "Failed to update VirtualCluster status")
+		return ctrl.Result{}, err
+	}
+
+	r.Recorder.Event(vcluster, corev1.EventTypeNormal, "Suspended", message)
+	return ctrl.Result{}, nil
+}
+
+// resumeReconcile marks a VirtualCluster that was suspended as resumed. The
+// condition is persisted with the status of the reconcile that follows.
+func (r *VirtualClusterReconciler) resumeReconcile(ctx context.Context, vcluster *corev1alpha1.VirtualCluster) {
+	if !meta.IsStatusConditionTrue(vcluster.Status.Conditions, VirtualClusterConditionSuspended) {
+		return
+	}
+
+	log.FromContext(ctx).Info("Reconciliation resumed")
+	meta.SetStatusCondition(&vcluster.Status.Conditions, metav1.Condition{
+		Type:    VirtualClusterConditionSuspended,
+		Status:  metav1.ConditionFalse,
+		Reason:  reasonResumed,
+		Message: "Reconciliation resumed",
+	})
+	r.Recorder.Event(vcluster, corev1.EventTypeNormal, "Resumed", "Reconciliation resumed")
+}
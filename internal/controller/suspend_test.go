@@ -0,0 +1,120 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	corev1alpha1 "github.com/OpenVirtualCluster/openvirtualcluster-operator/api/v1alpha1"
+)
+
+var _ = Describe("Suspend", func() {
+	var (
+		ctx        context.Context
+		engine     *fakeReleaseEngine
+		recorder   *record.FakeRecorder
+		reconciler *VirtualClusterReconciler
+		vc         *corev1alpha1.VirtualCluster
+	)
+
+	key := types.NamespacedName{Name: "suspend-test", Namespace: "default"}
+
+	build := func() {
+		reconciler = NewTestReconciler(engine, recorder, vc)
+	}
+
+	reconcileAndGet := func() (reconcile.Result, *corev1alpha1.VirtualCluster) {
+		result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+
+		updated := &corev1alpha1.VirtualCluster{}
+		Expect(reconciler.Get(ctx, key, updated)).To(Succeed())
+		return result, updated
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		engine = &fakeReleaseEngine{}
+		recorder = record.NewFakeRecorder(10)
+
+		vc = CreateTestVirtualCluster(key.Name, key.Namespace, "")
+		vc.Finalizers = []string{vclusterFinalizer}
+		vc.Status.Phase = corev1alpha1.VirtualClusterProvisioning
+		vc.Spec.Validation = &corev1alpha1.ValidationSpec{Mode: corev1alpha1.ValidationModeDisabled}
+		vc.Spec.Suspend = true
+	})
+
+	It("should not run Helm operations while suspended", func() {
+		build()
+
+		result, updated := reconcileAndGet()
+		Expect(result).To(Equal(reconcile.Result{}))
+		Expect(engine.installed).To(BeEmpty())
+		Expect(engine.upgraded).To(BeEmpty())
+
+		cond := meta.FindStatusCondition(updated.Status.Conditions, VirtualClusterConditionSuspended)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+		Expect(cond.Reason).To(Equal("Suspended"))
+		Expect(updated.Status.Phase).To(Equal(corev1alpha1.VirtualClusterProvisioning))
+		Expect(recorder.Events).To(Receive(ContainSubstring("Reconciliation is suspended")))
+
+		// The condition and event are only reported once
+		reconcileAndGet()
+		Expect(recorder.Events).NotTo(Receive())
+	})
+
+	It("should keep the finalizer of a suspended VirtualCluster being deleted", func() {
+		now := metav1.Now()
+		vc.DeletionTimestamp = &now
+		build()
+
+		_, updated := reconcileAndGet()
+		Expect(engine.uninstalled).To(BeEmpty())
+		Expect(updated.Finalizers).To(ContainElement(vclusterFinalizer))
+
+		cond := meta.FindStatusCondition(updated.Status.Conditions, VirtualClusterConditionSuspended)
+		Expect(cond.Message).To(ContainSubstring("deleted once spec.suspend is cleared"))
+	})
+
+	It("should resume once suspend is cleared", func() {
+		build()
+		reconcileAndGet()
+
+		updated := &corev1alpha1.VirtualCluster{}
+		Expect(reconciler.Get(ctx, key, updated)).To(Succeed())
+		updated.Spec.Suspend = false
+		Expect(reconciler.Update(ctx, updated)).To(Succeed())
+
+		_, updated = reconcileAndGet()
+		Expect(engine.installed).To(HaveLen(1))
+
+		cond := meta.FindStatusCondition(updated.Status.Conditions, VirtualClusterConditionSuspended)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+		Expect(cond.Reason).To(Equal("Resumed"))
+		Eventually(recorder.Events).Should(Receive(ContainSubstring("Reconciliation resumed")))
+	})
+})
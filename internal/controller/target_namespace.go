@@ -0,0 +1,115 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	corev1alpha1 "github.com/OpenVirtualCluster/openvirtualcluster-operator/api/v1alpha1"
+)
+
+// podSecurityEnforceLabel is the label enforcing a Pod Security Admission
+// level in a namespace
+const podSecurityEnforceLabel = "pod-security.kubernetes.io/enforce"
+
+// isNamespaceOf reports whether the namespace was created for the
+// VirtualCluster.
+func isNamespaceOf(ns *corev1.Namespace, vcluster *corev1alpha1.VirtualCluster) bool {
+	return ns.Labels[virtualClusterNameLabel] == vcluster.Name &&
+		ns.Labels[virtualClusterNamespaceLabel] == vcluster.Namespace
+}
+
+// ensureTargetNamespace creates the target namespace of the VirtualCluster
+// and keeps its labels, annotations and Pod Security level up to date.
+// Owner references can't cross namespaces, so the namespace is labeled with
+// the VirtualCluster instead. Namespaces that already exist and aren't
+// labeled for the VirtualCluster are never taken over.
+func (r *VirtualClusterReconciler) ensureTargetNamespace(ctx context.Context, vcluster *corev1alpha1.VirtualCluster) error {
+	spec := vcluster.Spec.TargetNamespace
+	if spec == nil {
+		vcluster.Status.TargetNamespace = ""
+		return nil
+	}
+
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: vcluster.ReleaseNamespace()}}
+	result, err := controllerutil.CreateOrUpdate(ctx, r.Client, ns, func() error {
+		if ns.ResourceVersion != "" && !isNamespaceOf(ns, vcluster) {
+			return fmt.Errorf("namespace %s already exists and is not managed by the VirtualCluster", ns.Name)
+		}
+		if !ns.DeletionTimestamp.IsZero() {
+			return fmt.Errorf("namespace %s is being deleted", ns.Name)
+		}
+
+		if ns.Labels == nil {
+			ns.Labels = map[string]string{}
+		}
+		for key, value := range spec.Labels {
+			ns.Labels[key] = value
+		}
+		if spec.PodSecurity != "" {
+			ns.Labels[podSecurityEnforceLabel] = string(spec.PodSecurity)
+		}
+		ns.Labels["app.kubernetes.io/managed-by"] = "openvc-controller"
+		ns.Labels[virtualClusterNameLabel] = vcluster.Name
+		ns.Labels[virtualClusterNamespaceLabel] = vcluster.Namespace
+
+		if len(spec.Annotations) > 0 && ns.Annotations == nil {
+			ns.Annotations = map[string]string{}
+		}
+		for key, value := range spec.Annotations {
+			ns.Annotations[key] = value
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to ensure target namespace %s: %w", ns.Name, err)
+	}
+
+	if result != controllerutil.OperationResultNone {
+		log.FromContext(ctx).Info("Ensured target namespace", "namespace", ns.Name, "operation", result)
+	}
+	vcluster.Status.TargetNamespace = ns.Name
+	return nil
+}
+
+// virtualClusterKeyForRelease returns the key of the VirtualCluster deploying
+// the release with the name to the namespace. Releases in a target namespace
+// belong to the VirtualCluster the namespace is labeled with, all others to
+// the VirtualCluster of the same name in the namespace.
+func (r *VirtualClusterReconciler) virtualClusterKeyForRelease(ctx context.Context, namespace, name string) (client.ObjectKey, error) {
+	key := client.ObjectKey{Namespace: namespace, Name: name}
+
+	ns := &corev1.Namespace{}
+	if err := r.Get(ctx, client.ObjectKey{Name: namespace}, ns); err != nil {
+		if errors.IsNotFound(err) {
+			return key, nil
+		}
+		return key, err
+	}
+	if ns.Labels[virtualClusterNameLabel] == name && ns.Labels[virtualClusterNamespaceLabel] != "" {
+		key.Namespace = ns.Labels[virtualClusterNamespaceLabel]
+	}
+	return key, nil
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	corev1alpha1 "github.com/OpenVirtualCluster/openvirtualcluster-operator/api/v1alpha1"
+)
+
+var _ = Describe("Target Namespace", func() {
+	var (
+		ctx        context.Context
+		engine     *fakeReleaseEngine
+		reconciler *VirtualClusterReconciler
+		vc         *corev1alpha1.VirtualCluster
+	)
+
+	newReconciler := func(objs ...client.Object) {
+		reconciler = NewTestReconciler(engine, nil, append(objs, vc)...)
+	}
+
+	targetNamespace := func() *corev1.Namespace {
+		ns := &corev1.Namespace{}
+		Expect(reconciler.Get(ctx, client.ObjectKey{Name: "vc-tenant-a-target-test"}, ns)).To(Succeed())
+		return ns
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		engine = &fakeReleaseEngine{}
+
+		vc = CreateTestVirtualCluster("target-test", "tenant-a", "")
+		vc.Spec.Validation = &corev1alpha1.ValidationSpec{Mode: corev1alpha1.ValidationModeDisabled}
+		vc.Spec.TargetNamespace = &corev1alpha1.TargetNamespaceSpec{
+			Labels:      map[string]string{"team": "a"},
+			Annotations: map[string]string{"scheduler.alpha.kubernetes.io/node-selector": "pool=tenants"},
+			PodSecurity: corev1alpha1.PodSecurityLevelBaseline,
+		}
+	})
+
+	It("should create the namespace labeled for the VirtualCluster", func() {
+		newReconciler()
+
+		Expect(reconciler.ensureTargetNamespace(ctx, vc)).To(Succeed())
+		Expect(vc.Status.TargetNamespace).To(Equal("vc-tenant-a-target-test"))
+
+		ns := targetNamespace()
+		Expect(ns.Labels).To(HaveKeyWithValue("team", "a"))
+		Expect(ns.Labels).To(HaveKeyWithValue("pod-security.kubernetes.io/enforce", "baseline"))
+		Expect(ns.Labels).To(HaveKeyWithValue(virtualClusterNameLabel, "target-test"))
+		Expect(ns.Labels).To(HaveKeyWithValue(virtualClusterNamespaceLabel, "tenant-a"))
+		Expect(ns.Annotations).To(HaveKeyWithValue("scheduler.alpha.kubernetes.io/node-selector", "pool=tenants"))
+	})
+
+	It("should update the namespace when the spec changes", func() {
+		newReconciler()
+		Expect(reconciler.ensureTargetNamespace(ctx, vc)).To(Succeed())
+
+		vc.Spec.TargetNamespace.Labels["team"] = "b"
+		vc.Spec.TargetNamespace.PodSecurity = corev1alpha1.PodSecurityLevelRestricted
+		Expect(reconciler.ensureTargetNamespace(ctx, vc)).To(Succeed())
+
+		ns := targetNamespace()
+		Expect(ns.Labels).To(HaveKeyWithValue("team", "b"))
+		Expect(ns.Labels).To(HaveKeyWithValue("pod-security.kubernetes.io/enforce", "restricted"))
+	})
+
+	It("should not take over an existing namespace", func() {
+		vc.Spec.TargetNamespace.Name = "kube-system"
+		newReconciler(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}})
+
+		err := reconciler.ensureTargetNamespace(ctx, vc)
+		Expect(err).To(MatchError(ContainSubstring("namespace kube-system already exists and is not managed by the VirtualCluster")))
+		Expect(vc.Status.TargetNamespace).To(BeEmpty())
+	})
+
+	It("should deploy the release into the target namespace", func() {
+		newReconciler()
+		Expect(reconciler.ensureTargetNamespace(ctx, vc)).To(Succeed())
+
+		values, err := reconciler.translateValues(ctx, vc, nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(reconciler.installOrUpgradeVCluster(ctx, vc, nil, values)).To(Succeed())
+		Expect(engine.installed).To(HaveLen(1))
+		Expect(engine.installed[0].Namespace).To(Equal("vc-tenant-a-target-test"))
+	})
+
+	It("should find the release objects in the target namespace", func() {
+		objs := readyControlPlane("target-test", "vc-tenant-a-target-test")
+		newReconciler(objs...)
+		Expect(reconciler.ensureTargetNamespace(ctx, vc)).To(Succeed())
+
+		Expect(reconciler.requestsForReleaseObject(ctx, objs[0])).To(ConsistOf(reconcile.Request{
+			NamespacedName: client.ObjectKeyFromObject(vc),
+		}))
+		Expect(reconciler.requestsForKubeconfigSecret(ctx, objs[2])).To(ConsistOf(reconcile.Request{
+			NamespacedName: client.ObjectKeyFromObject(vc),
+		}))
+
+		health, err := reconciler.checkControlPlane(ctx, vc)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(health.Reason).To(Equal(healthReasonReady))
+	})
+
+	DescribeTable("deleting the VirtualCluster",
+		func(policy corev1alpha1.DeletionPolicy, deleted bool) {
+			vc.Spec.DeletionPolicy = policy
+			vc.Finalizers = []string{vclusterFinalizer}
+			vc.Status.Phase = corev1alpha1.VirtualClusterRunning
+			now := metav1.Now()
+			vc.DeletionTimestamp = &now
+			newReconciler()
+			Expect(reconciler.ensureTargetNamespace(ctx, vc)).To(Succeed())
+
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(vc)})
+			Expect(err).NotTo(HaveOccurred())
+
+			err = reconciler.Get(ctx, client.ObjectKey{Name: "vc-tenant-a-target-test"}, &corev1.Namespace{})
+			Expect(errors.IsNotFound(err)).To(Equal(deleted))
+		},
+		Entry("deletes the namespace with the Delete policy", corev1alpha1.DeletionPolicyDelete, true),
+		Entry("keeps the namespace with the Retain policy", corev1alpha1.DeletionPolicyRetain, false),
+	)
+})
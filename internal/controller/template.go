@@ -0,0 +1,58 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	corev1alpha1 "github.com/OpenVirtualCluster/openvirtualcluster-operator/api/v1alpha1"
+)
+
+// templateHashAnnotation holds the hash of the template a VirtualCluster of a
+// pool or set was created from
+const templateHashAnnotation = "core.openvc.dev/template-hash"
+
+// templateHash returns the hash of a VirtualCluster template.
+func templateHash(template *corev1alpha1.VirtualClusterTemplate) string {
+	data, _ := json.Marshal(template)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// applyTemplate sets the spec of the VirtualCluster to the one of the
+// template and adds its labels and annotations, keeping the ones set by
+// others.
+func applyTemplate(vcluster *corev1alpha1.VirtualCluster, template *corev1alpha1.VirtualClusterTemplate, hash string) {
+	template = template.DeepCopy()
+	vcluster.Spec = template.Spec
+
+	if vcluster.Labels == nil {
+		vcluster.Labels = map[string]string{}
+	}
+	for k, v := range template.Metadata.Labels {
+		vcluster.Labels[k] = v
+	}
+	if vcluster.Annotations == nil {
+		vcluster.Annotations = map[string]string{}
+	}
+	for k, v := range template.Metadata.Annotations {
+		vcluster.Annotations[k] = v
+	}
+	vcluster.Annotations[templateHashAnnotation] = hash
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"time"
 
+	appsv1 "k8s.io/api/apps/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -32,6 +33,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	corev1alpha1 "github.com/OpenVirtualCluster/openvirtualcluster-operator/api/v1alpha1"
+	"github.com/OpenVirtualCluster/openvirtualcluster-operator/internal/helm"
 )
 
 // TestReconcilerSetup creates a test reconciler with the necessary dependencies
@@ -54,6 +56,50 @@ func TestReconcilerSetup() (*VirtualClusterReconciler, client.Client, *runtime.S
 	return reconciler, fakeClient, s
 }
 
+// NewTestClient returns a fake client holding the objects. The kinds of the
+// operator and the workloads it reads have the status subresource, and the
+// field indexes of the controllers are registered.
+func NewTestClient(objs ...client.Object) (client.Client, *runtime.Scheme) {
+	s := runtime.NewScheme()
+	_ = corev1alpha1.AddToScheme(s)
+	_ = scheme.AddToScheme(s)
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(s).
+		WithObjects(objs...).
+		WithStatusSubresource(
+			&corev1alpha1.VirtualCluster{},
+			&corev1alpha1.VirtualClusterPool{},
+			&corev1alpha1.VirtualClusterClaim{},
+			&corev1alpha1.VirtualClusterSet{},
+			&appsv1.StatefulSet{},
+			&appsv1.Deployment{},
+		).
+		WithIndex(&corev1alpha1.VirtualCluster{}, classRefIndex, classRefIndexer).
+		WithIndex(&corev1alpha1.VirtualCluster{}, valuesFromConfigMapIndex, valuesFromIndexer("ConfigMap")).
+		WithIndex(&corev1alpha1.VirtualCluster{}, valuesFromSecretIndex, valuesFromIndexer("Secret")).
+		WithIndex(&corev1alpha1.VirtualClusterClaim{}, claimPoolIndex, claimPoolIndexer).
+		Build()
+
+	return fakeClient, s
+}
+
+// NewTestReconciler creates a test reconciler backed by a fake client holding
+// the objects. A nil recorder is replaced by a fake one.
+func NewTestReconciler(engine helm.ReleaseEngine, recorder record.EventRecorder, objs ...client.Object) *VirtualClusterReconciler {
+	if recorder == nil {
+		recorder = record.NewFakeRecorder(10)
+	}
+
+	fakeClient, s := NewTestClient(objs...)
+	return &VirtualClusterReconciler{
+		Client:   fakeClient,
+		Scheme:   s,
+		Recorder: recorder,
+		Helm:     engine,
+	}
+}
+
 // CreateTestVirtualCluster creates a test VirtualCluster instance
 func CreateTestVirtualCluster(name, namespace string, values string) *corev1alpha1.VirtualCluster {
 	if values == "" {
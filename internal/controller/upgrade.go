@@ -0,0 +1,200 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"helm.sh/helm/v3/pkg/release"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	corev1alpha1 "github.com/OpenVirtualCluster/openvirtualcluster-operator/api/v1alpha1"
+	"github.com/OpenVirtualCluster/openvirtualcluster-operator/internal/helm"
+)
+
+// defaultMaxHistory is the number of revisions kept for a release, matching
+// the default of the helm CLI.
+const defaultMaxHistory = 10
+
+// upgradeRolledBackError is returned instead of retrying an upgrade that
+// failed and was rolled back, as every attempt adds a failed and a rollback
+// revision to the history and pushes the deployed revisions out of it.
+type upgradeRolledBackError struct {
+	hash string
+}
+
+func (e *upgradeRolledBackError) Error() string {
+	return fmt.Sprintf("upgrade to %s failed and was rolled back, change the spec or values to retry", e.hash)
+}
+
+// isUpgradeRolledBack returns true if err reports an upgrade that isn't
+// retried since it was rolled back.
+func isUpgradeRolledBack(err error) bool {
+	var rolledBack *upgradeRolledBackError
+	return errors.As(err, &rolledBack)
+}
+
+// upgradePolicy returns the upgrade policy of the VirtualCluster, falling
+// back to the one of its class.
+func (r *VirtualClusterReconciler) upgradePolicy(vcluster *corev1alpha1.VirtualCluster, class *corev1alpha1.VirtualClusterClass) corev1alpha1.UpgradePolicy {
+	if vcluster.Spec.UpgradePolicy != nil {
+		return *vcluster.Spec.UpgradePolicy
+	}
+	if class != nil && class.Spec.UpgradePolicy != nil {
+		return *class.Spec.UpgradePolicy
+	}
+	return corev1alpha1.UpgradePolicy{}
+}
+
+// operationOptions returns the options of the Helm operations of the
+// VirtualCluster.
+func (r *VirtualClusterReconciler) operationOptions(vcluster *corev1alpha1.VirtualCluster, class *corev1alpha1.VirtualClusterClass) helm.OperationOptions {
+	policy := r.upgradePolicy(vcluster, class)
+	opts := helm.OperationOptions{
+		Atomic:     policy.Atomic,
+		Wait:       policy.Wait,
+		MaxHistory: defaultMaxHistory,
+	}
+	if policy.MaxHistory != nil {
+		opts.MaxHistory = int(*policy.MaxHistory)
+	}
+	if policy.Timeout != nil {
+		opts.Timeout = policy.Timeout.Duration
+	}
+	return opts
+}
+
+// recordHistory lists the latest revisions of the release in the status.
+// Failing to read the history doesn't fail the reconcile.
+func (r *VirtualClusterReconciler) recordHistory(ctx context.Context, vcluster *corev1alpha1.VirtualCluster, class *corev1alpha1.VirtualClusterClass) {
+	releases, err := r.Helm.History(ctx, vcluster.ReleaseNamespace(), vcluster.Name)
+	if helm.IsReleaseNotFound(err) {
+		vcluster.Status.History = nil
+		return
+	}
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Failed to get the history of the Helm release")
+		return
+	}
+
+	limit := r.operationOptions(vcluster, class).MaxHistory
+	history := make([]corev1alpha1.ReleaseRevision, 0, limit)
+	for i := len(releases) - 1; i >= 0 && len(history) < limit; i-- {
+		history = append(history, releaseRevision(releases[i]))
+	}
+	vcluster.Status.History = history
+}
+
+// releaseRevision describes a revision of a release for the status.
+func releaseRevision(rel *release.Release) corev1alpha1.ReleaseRevision {
+	revision := corev1alpha1.ReleaseRevision{Revision: int32(rel.Version)}
+	if rel.Chart != nil && rel.Chart.Metadata != nil {
+		revision.ChartVersion = rel.Chart.Metadata.Version
+	}
+	if hash, err := releaseHash(revision.ChartVersion, rel.Config); err == nil {
+		revision.ValuesHash = hash
+	}
+	if rel.Info != nil {
+		revision.Outcome = rel.Info.Status.String()
+		revision.Description = rel.Info.Description
+		revision.Timestamp = metav1.NewTime(rel.Info.LastDeployed.Time)
+	}
+	return revision
+}
+
+// rollbackToRevision rolls the release back to the revision requested by
+// spec.rollback, once. Afterwards the release is left alone until the
+// request is removed.
+func (r *VirtualClusterReconciler) rollbackToRevision(ctx context.Context, vcluster *corev1alpha1.VirtualCluster, class *corev1alpha1.VirtualClusterClass) error {
+	logger := log.FromContext(ctx)
+	revision := vcluster.Spec.Rollback.Revision
+
+	if vcluster.Status.RollbackRevision != revision {
+		logger.Info("Rolling back the release", "release", vcluster.Name, "revision", revision)
+		if _, err := r.Helm.Rollback(ctx, helm.RollbackRequest{
+			Name:      vcluster.Name,
+			Namespace: vcluster.ReleaseNamespace(),
+			Revision:  int(revision),
+			Options:   r.operationOptions(vcluster, class),
+		}); err != nil {
+			logger.Error(err, "Failed to roll back the release", "revision", revision)
+			return err
+		}
+
+		vcluster.Status.RollbackRevision = revision
+		// Re-apply the spec once the rollback request is removed
+		vcluster.Status.AppliedHash = ""
+		r.recordHistory(ctx, vcluster, class)
+		r.Recorder.Event(vcluster, corev1.EventTypeNormal, "RolledBack",
+			fmt.Sprintf("Helm release was rolled back to revision %d", revision))
+	}
+
+	meta.SetStatusCondition(&vcluster.Status.Conditions, metav1.Condition{
+		Type:    VirtualClusterConditionDrifted,
+		Status:  metav1.ConditionFalse,
+		Reason:  "RollbackRequested",
+		Message: fmt.Sprintf("Helm release is held at revision %d by spec.rollback", revision),
+	})
+	return nil
+}
+
+// rollbackFailedUpgrade rolls a failed upgrade back to the last deployed
+// revision.
+func (r *VirtualClusterReconciler) rollbackFailedUpgrade(ctx context.Context, vcluster *corev1alpha1.VirtualCluster, class *corev1alpha1.VirtualClusterClass) (int, error) {
+	releases, err := r.Helm.History(ctx, vcluster.ReleaseNamespace(), vcluster.Name)
+	if err != nil {
+		return 0, err
+	}
+
+	// A failed upgrade leaves the previous revision deployed
+	for i := len(releases) - 1; i >= 0; i-- {
+		rel := releases[i]
+		if rel.Info == nil || rel.Info.Status != release.StatusDeployed {
+			continue
+		}
+
+		if _, err := r.Helm.Rollback(ctx, helm.RollbackRequest{
+			Name:      vcluster.Name,
+			Namespace: vcluster.ReleaseNamespace(),
+			Revision:  rel.Version,
+			Options:   r.operationOptions(vcluster, class),
+		}); err != nil {
+			return 0, err
+		}
+		return rel.Version, nil
+	}
+	return 0, fmt.Errorf("no deployed revision to roll back to")
+}
+
+// atomicRollbackRevision returns the revision Helm rolled a failed atomic
+// upgrade back to, if the latest revision of the release is deployed again.
+func (r *VirtualClusterReconciler) atomicRollbackRevision(ctx context.Context, vcluster *corev1alpha1.VirtualCluster) (int, bool) {
+	rel, err := r.Helm.Get(ctx, vcluster.ReleaseNamespace(), vcluster.Name)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Failed to get the Helm release after the failed upgrade")
+		return 0, false
+	}
+	if rel.Info == nil || rel.Info.Status != release.StatusDeployed {
+		return 0, false
+	}
+	return rel.Version, true
+}
@@ -0,0 +1,229 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+
+	corev1alpha1 "github.com/OpenVirtualCluster/openvirtualcluster-operator/api/v1alpha1"
+	"github.com/OpenVirtualCluster/openvirtualcluster-operator/internal/helm"
+)
+
+var _ = Describe("Upgrade Policy", func() {
+	var (
+		ctx        context.Context
+		engine     *fakeReleaseEngine
+		recorder   *record.FakeRecorder
+		reconciler *VirtualClusterReconciler
+		vc         *corev1alpha1.VirtualCluster
+		values     map[string]interface{}
+	)
+
+	// upgrade changes the chart version so the next call upgrades the release
+	upgrade := func(version string) error {
+		vc.Spec.Chart.Version = version
+		return reconciler.installOrUpgradeVCluster(ctx, vc, nil, values)
+	}
+
+	revisions := func() []string {
+		var result []string
+		for _, revision := range vc.Status.History {
+			result = append(result, fmt.Sprintf("%d:%s:%s", revision.Revision, revision.ChartVersion, revision.Outcome))
+		}
+		return result
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		vc = CreateTestVirtualCluster("upgrade-test", "default", "")
+		vc.Spec.Validation = &corev1alpha1.ValidationSpec{Mode: corev1alpha1.ValidationModeDisabled}
+
+		engine = &fakeReleaseEngine{}
+		recorder = record.NewFakeRecorder(10)
+		reconciler = NewTestReconciler(engine, recorder, vc)
+
+		var err error
+		values, err = reconciler.translateValues(ctx, vc, nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(reconciler.installOrUpgradeVCluster(ctx, vc, nil, values)).To(Succeed())
+		engine.exists = true
+	})
+
+	It("should map the upgrade policy to Helm options", func() {
+		Expect(reconciler.operationOptions(vc, nil)).To(Equal(helm.OperationOptions{MaxHistory: 10}))
+
+		vc.Spec.UpgradePolicy = &corev1alpha1.UpgradePolicy{
+			Atomic:     true,
+			Wait:       true,
+			MaxHistory: ptr.To[int32](3),
+			Timeout:    &metav1.Duration{Duration: 2 * time.Minute},
+		}
+		Expect(reconciler.operationOptions(vc, nil)).To(Equal(helm.OperationOptions{
+			Atomic:     true,
+			Wait:       true,
+			MaxHistory: 3,
+			Timeout:    2 * time.Minute,
+		}))
+
+		Expect(upgrade("v0.25.0")).To(Succeed())
+		Expect(engine.upgraded[0].Options.Atomic).To(BeTrue())
+	})
+
+	It("should record the history newest first", func() {
+		Expect(revisions()).To(Equal([]string{"1:0.24.1:deployed"}))
+
+		vc.Spec.UpgradePolicy = &corev1alpha1.UpgradePolicy{MaxHistory: ptr.To[int32](2)}
+		Expect(upgrade("v0.25.0")).To(Succeed())
+		Expect(upgrade("v0.26.0")).To(Succeed())
+		Expect(revisions()).To(Equal([]string{"3:0.26.0:deployed", "2:0.25.0:superseded"}))
+
+		hash, err := releaseHash("v0.26.0", engine.upgraded[1].Values)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(vc.Status.History[0].ValuesHash).To(Equal(hash))
+		Expect(vc.Status.History[0].Timestamp.IsZero()).To(BeFalse())
+	})
+
+	It("should leave a failed upgrade without rollbackOnFailure", func() {
+		engine.upgradeErr = fmt.Errorf("timed out waiting for the condition")
+
+		Expect(upgrade("v0.25.0")).To(MatchError(ContainSubstring("timed out")))
+		Expect(engine.rolledBack).To(BeEmpty())
+		Expect(revisions()).To(Equal([]string{"2:0.25.0:failed", "1:0.24.1:deployed"}))
+	})
+
+	It("should roll back a failed upgrade with rollbackOnFailure", func() {
+		vc.Spec.UpgradePolicy = &corev1alpha1.UpgradePolicy{RollbackOnFailure: true}
+		engine.upgradeErr = fmt.Errorf("timed out waiting for the condition")
+
+		err := upgrade("v0.25.0")
+		Expect(err).To(MatchError(ContainSubstring("rolled back to revision 1")))
+		Expect(engine.rolledBack).To(HaveLen(1))
+		Expect(engine.rolledBack[0].Revision).To(Equal(1))
+		Expect(revisions()).To(Equal([]string{"3:0.24.1:deployed", "2:0.25.0:failed", "1:0.24.1:superseded"}))
+		Expect(recorder.Events).To(Receive(ContainSubstring("UpgradeRolledBack")))
+	})
+
+	It("should not retry an upgrade that was rolled back until the desired state changes", func() {
+		vc.Spec.UpgradePolicy = &corev1alpha1.UpgradePolicy{RollbackOnFailure: true}
+		engine.upgradeErr = fmt.Errorf("timed out waiting for the condition")
+
+		Expect(upgrade("v0.25.0")).To(MatchError(ContainSubstring("rolled back to revision 1")))
+		Expect(vc.Status.FailedHash).NotTo(BeEmpty())
+
+		err := upgrade("v0.25.0")
+		Expect(isUpgradeRolledBack(err)).To(BeTrue())
+		Expect(engine.upgraded).To(HaveLen(1))
+		Expect(engine.rolledBack).To(HaveLen(1))
+		Expect(revisions()).To(HaveLen(3))
+
+		engine.upgradeErr = nil
+		Expect(upgrade("v0.25.1")).To(Succeed())
+		Expect(engine.upgraded).To(HaveLen(2))
+		Expect(vc.Status.FailedHash).To(BeEmpty())
+	})
+
+	It("should leave the rollback of atomic upgrades to Helm", func() {
+		vc.Spec.UpgradePolicy = &corev1alpha1.UpgradePolicy{Atomic: true, RollbackOnFailure: true}
+		engine.upgradeErr = fmt.Errorf("release failed, and has been rolled back due to atomic being set")
+
+		Expect(upgrade("v0.25.0")).To(HaveOccurred())
+		Expect(engine.rolledBack).To(BeEmpty())
+	})
+
+	It("should not retry an atomic upgrade that Helm rolled back", func() {
+		vc.Spec.UpgradePolicy = &corev1alpha1.UpgradePolicy{Atomic: true}
+		engine.upgradeErr = fmt.Errorf("release failed, and has been rolled back due to atomic being set")
+
+		Expect(upgrade("v0.25.0")).To(HaveOccurred())
+		Expect(revisions()).To(Equal([]string{"3:0.24.1:deployed", "2:0.25.0:failed", "1:0.24.1:superseded"}))
+		Expect(vc.Status.FailedHash).NotTo(BeEmpty())
+		Expect(recorder.Events).To(Receive(ContainSubstring("UpgradeRolledBack")))
+
+		// Resyncs don't add revisions for the same desired state
+		err := upgrade("v0.25.0")
+		Expect(isUpgradeRolledBack(err)).To(BeTrue())
+		Expect(engine.upgraded).To(HaveLen(1))
+		Expect(revisions()).To(HaveLen(3))
+
+		engine.upgradeErr = nil
+		Expect(upgrade("v0.25.1")).To(Succeed())
+		Expect(vc.Status.FailedHash).To(BeEmpty())
+	})
+
+	It("should report a failed rollback", func() {
+		vc.Spec.UpgradePolicy = &corev1alpha1.UpgradePolicy{RollbackOnFailure: true}
+		engine.upgradeErr = fmt.Errorf("timed out waiting for the condition")
+		engine.rollbackErr = fmt.Errorf("cluster unreachable")
+
+		Expect(upgrade("v0.25.0")).To(MatchError(ContainSubstring("rollback failed: cluster unreachable")))
+	})
+
+	Context("with spec.rollback", func() {
+		BeforeEach(func() {
+			Expect(upgrade("v0.25.0")).To(Succeed())
+			vc.Spec.Rollback = &corev1alpha1.RollbackSpec{Revision: 1}
+		})
+
+		It("should roll back to the revision once", func() {
+			Expect(reconciler.installOrUpgradeVCluster(ctx, vc, nil, values)).To(Succeed())
+			Expect(engine.rolledBack).To(HaveLen(1))
+			Expect(vc.Status.RollbackRevision).To(Equal(int32(1)))
+			Expect(vc.Status.History[0].ChartVersion).To(Equal("0.24.1"))
+			Expect(recorder.Events).To(Receive(ContainSubstring("RolledBack")))
+
+			// The release is held at the revision, even though the spec differs
+			Expect(reconciler.installOrUpgradeVCluster(ctx, vc, nil, values)).To(Succeed())
+			Expect(engine.rolledBack).To(HaveLen(1))
+			Expect(engine.upgraded).To(HaveLen(1))
+		})
+
+		It("should re-apply the spec once the rollback is removed", func() {
+			Expect(reconciler.installOrUpgradeVCluster(ctx, vc, nil, values)).To(Succeed())
+
+			vc.Spec.Rollback = nil
+			Expect(reconciler.installOrUpgradeVCluster(ctx, vc, nil, values)).To(Succeed())
+			Expect(engine.upgraded).To(HaveLen(2))
+			Expect(vc.Status.RollbackRevision).To(BeZero())
+			Expect(vc.Status.History[0].ChartVersion).To(Equal("0.25.0"))
+		})
+
+		It("should return rollback errors", func() {
+			vc.Spec.Rollback.Revision = 7
+
+			Expect(reconciler.installOrUpgradeVCluster(ctx, vc, nil, values)).To(MatchError(ContainSubstring("no revision 7")))
+			Expect(vc.Status.RollbackRevision).To(BeZero())
+		})
+	})
+})
+
+var _ = Describe("releaseRevision", func() {
+	It("should describe pending revisions", func() {
+		revision := releaseRevision(fakeRelease(helm.ReleaseRequest{}, "pending-upgrade"))
+		Expect(revision.Outcome).To(Equal("pending-upgrade"))
+		Expect(revision.ValuesHash).NotTo(BeEmpty())
+	})
+})
@@ -19,6 +19,7 @@ package controller
 import (
 	"context"
 	"os"
+	"path/filepath"
 
 	corev1alpha1 "github.com/OpenVirtualCluster/openvirtualcluster-operator/api/v1alpha1"
 	. "github.com/onsi/ginkgo/v2"
@@ -96,7 +97,7 @@ var _ = Describe("VirtualCluster Controller Utils", func() {
 	})
 
 	Context("File Operations", func() {
-		It("should create and clean up temporary files", func() {
+		It("should not write the values to a temporary file", func() {
 			// Create a test VirtualCluster
 			vc := &corev1alpha1.VirtualCluster{
 				ObjectMeta: metav1.ObjectMeta{
@@ -111,18 +112,13 @@ var _ = Describe("VirtualCluster Controller Utils", func() {
 				},
 			}
 
-			// Test the creation of values file
-			valuesFile, err := reconciler.createValuesFile(ctx, vc)
+			// The values are handed to Helm in memory
+			values, err := reconciler.translateValues(ctx, vc, nil)
 			Expect(err).NotTo(HaveOccurred())
-			Expect(valuesFile).NotTo(BeEmpty())
+			Expect(values).NotTo(BeEmpty())
 
-			// Check if file exists
-			_, err = os.Stat(valuesFile)
-			Expect(err).NotTo(HaveOccurred())
-
-			// Clean up
-			err = os.Remove(valuesFile)
-			Expect(err).NotTo(HaveOccurred())
+			// Nothing may be left at the path of the former values file
+			Expect(filepath.Join(os.TempDir(), "values-test-file-ops-default.yaml")).NotTo(BeAnExistingFile())
 		})
 	})
 
@@ -0,0 +1,36 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	corev1alpha1 "github.com/OpenVirtualCluster/openvirtualcluster-operator/api/v1alpha1"
+)
+
+// validationMode returns the validation mode of the VirtualCluster or its
+// class, falling back to the default of the operator.
+func (r *VirtualClusterReconciler) validationMode(vcluster *corev1alpha1.VirtualCluster, class *corev1alpha1.VirtualClusterClass) corev1alpha1.ValidationMode {
+	if vcluster.Spec.Validation != nil && vcluster.Spec.Validation.Mode != "" {
+		return vcluster.Spec.Validation.Mode
+	}
+	if class != nil && class.Spec.Validation != nil && class.Spec.Validation.Mode != "" {
+		return class.Spec.Validation.Mode
+	}
+	if r.DefaultValidationMode != "" {
+		return r.DefaultValidationMode
+	}
+	return corev1alpha1.ValidationModeWarn
+}
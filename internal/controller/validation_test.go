@@ -0,0 +1,173 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	corev1alpha1 "github.com/OpenVirtualCluster/openvirtualcluster-operator/api/v1alpha1"
+	"github.com/OpenVirtualCluster/openvirtualcluster-operator/internal/schema"
+)
+
+var _ = Describe("Validation Modes", func() {
+	// The image tag of the test values is a string, so this schema rejects it
+	const strictSchema = `{
+		"type": "object",
+		"properties": {
+			"controlPlane": {"type": "object", "properties": {
+				"distro": {"type": "object", "properties": {
+					"k3s": {"type": "object", "properties": {
+						"image": {"type": "object", "properties": {
+							"tag": {"type": "integer"}
+						}}
+					}}
+				}}
+			}}
+		}
+	}`
+
+	var (
+		ctx        context.Context
+		engine     *fakeReleaseEngine
+		recorder   *record.FakeRecorder
+		reconciler *VirtualClusterReconciler
+		vc         *corev1alpha1.VirtualCluster
+		values     map[string]interface{}
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		vc = CreateTestVirtualCluster("validation-test", "default", "")
+		vc.Finalizers = []string{vclusterFinalizer}
+		vc.Status.Phase = corev1alpha1.VirtualClusterProvisioning
+
+		schemaConfigMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "vcluster-schema-v0-24-1", Namespace: "default"},
+			Data:       map[string]string{"values.schema.json": strictSchema},
+		}
+
+		engine = &fakeReleaseEngine{}
+		recorder = record.NewFakeRecorder(10)
+		reconciler = NewTestReconciler(engine, recorder, vc, schemaConfigMap)
+
+		var err error
+		values, err = reconciler.translateValues(ctx, vc, nil)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should default to Warn", func() {
+		Expect(reconciler.validationMode(vc, nil)).To(Equal(corev1alpha1.ValidationModeWarn))
+
+		reconciler.DefaultValidationMode = corev1alpha1.ValidationModeEnforce
+		Expect(reconciler.validationMode(vc, nil)).To(Equal(corev1alpha1.ValidationModeEnforce))
+
+		vc.Spec.Validation = &corev1alpha1.ValidationSpec{Mode: corev1alpha1.ValidationModeDisabled}
+		Expect(reconciler.validationMode(vc, nil)).To(Equal(corev1alpha1.ValidationModeDisabled))
+	})
+
+	It("should install invalid values in Warn mode", func() {
+		Expect(reconciler.installOrUpgradeVCluster(ctx, vc, nil, values)).To(Succeed())
+		Expect(engine.installed).To(HaveLen(1))
+
+		cond := meta.FindStatusCondition(vc.Status.Conditions, VirtualClusterConditionValidated)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+	})
+
+	It("should skip validation in Disabled mode", func() {
+		vc.Spec.Validation = &corev1alpha1.ValidationSpec{Mode: corev1alpha1.ValidationModeDisabled}
+
+		Expect(reconciler.installOrUpgradeVCluster(ctx, vc, nil, values)).To(Succeed())
+		Expect(engine.installed).To(HaveLen(1))
+
+		cond := meta.FindStatusCondition(vc.Status.Conditions, VirtualClusterConditionValidated)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Reason).To(Equal("ValidationDisabled"))
+	})
+
+	It("should not install invalid values in Enforce mode", func() {
+		vc.Spec.Validation = &corev1alpha1.ValidationSpec{Mode: corev1alpha1.ValidationModeEnforce}
+
+		err := reconciler.installOrUpgradeVCluster(ctx, vc, nil, values)
+		validationErr, ok := schema.AsValidationError(err)
+		Expect(ok).To(BeTrue())
+		Expect(validationErr.Paths()).To(ConsistOf("$.controlPlane.distro.k3s.image.tag"))
+		Expect(engine.installed).To(BeEmpty())
+	})
+
+	It("should report skipped enforcement for a chart without a schema", func() {
+		vc.Spec.Validation = &corev1alpha1.ValidationSpec{Mode: corev1alpha1.ValidationModeEnforce}
+		vc.Spec.Chart.Source = &corev1alpha1.ChartSource{
+			Offline: &corev1alpha1.OfflineChartSource{
+				ConfigMapRef: &corev1.LocalObjectReference{Name: "vcluster-chart"},
+			},
+		}
+		Expect(reconciler.Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "vcluster-chart", Namespace: "default"},
+			BinaryData: map[string][]byte{chartArchiveKey: packageTestChart("")},
+		})).To(Succeed())
+
+		Expect(reconciler.installOrUpgradeVCluster(ctx, vc, nil, values)).To(Succeed())
+		Expect(engine.installed).To(HaveLen(1))
+
+		cond := meta.FindStatusCondition(vc.Status.Conditions, VirtualClusterConditionValidated)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Status).To(Equal(metav1.ConditionUnknown))
+		Expect(cond.Reason).To(Equal("SchemaEnforcementSkipped"))
+		Expect(recorder.Events).To(Receive(ContainSubstring("Warning SchemaEnforcementSkipped")))
+
+		// The event is only emitted when enforcement starts being skipped
+		engine.exists = true
+		vc.Spec.Values = nil
+		values, err := reconciler.translateValues(ctx, vc, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reconciler.installOrUpgradeVCluster(ctx, vc, nil, values)).To(Succeed())
+		Expect(engine.upgraded).To(HaveLen(1))
+		Expect(recorder.Events).NotTo(Receive())
+	})
+
+	It("should fail the VirtualCluster in Enforce mode", func() {
+		reconciler.DefaultValidationMode = corev1alpha1.ValidationModeEnforce
+
+		result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(vc)})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(reconcile.Result{}))
+		Expect(engine.installed).To(BeEmpty())
+
+		updated := &corev1alpha1.VirtualCluster{}
+		Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(vc), updated)).To(Succeed())
+		Expect(updated.Status.Phase).To(Equal(corev1alpha1.VirtualClusterFailed))
+		cond := meta.FindStatusCondition(updated.Status.Conditions, VirtualClusterConditionError)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Reason).To(Equal("SchemaValidationFailed"))
+
+		Expect(recorder.Events).To(Receive(And(
+			ContainSubstring("SchemaValidationFailed"),
+			ContainSubstring("$.controlPlane.distro.k3s.image.tag"),
+		)))
+	})
+})
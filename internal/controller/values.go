@@ -0,0 +1,101 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	corev1alpha1 "github.com/OpenVirtualCluster/openvirtualcluster-operator/api/v1alpha1"
+	"github.com/OpenVirtualCluster/openvirtualcluster-operator/internal/translate"
+)
+
+// chartVersionFor returns the chart version of the VirtualCluster or its
+// class, falling back to the default version.
+func (r *VirtualClusterReconciler) chartVersionFor(vcluster *corev1alpha1.VirtualCluster, class *corev1alpha1.VirtualClusterClass) string {
+	if version := r.chartFor(vcluster, class).Version; version != "" {
+		return version
+	}
+	return vclusterVersion
+}
+
+// translateValues composes the values of the VirtualCluster and translates
+// them for its chart version. Renamed and dropped keys are reported in the
+// ValuesTranslated condition.
+func (r *VirtualClusterReconciler) translateValues(ctx context.Context, vcluster *corev1alpha1.VirtualCluster, class *corev1alpha1.VirtualClusterClass) (map[string]interface{}, error) {
+	logger := log.FromContext(ctx)
+
+	values, err := r.composeValues(ctx, vcluster, class)
+	if err != nil {
+		return nil, err
+	}
+
+	result := translate.Values(values, r.chartVersionFor(vcluster, class))
+	if dropped := result.Dropped(); len(dropped) > 0 {
+		logger.Info("Dropped values not supported by the chart", "keys", changeList(dropped))
+	}
+	meta.SetStatusCondition(&vcluster.Status.Conditions, translationCondition(result))
+
+	return result.Values, nil
+}
+
+// translationCondition summarizes a translation as a condition.
+func translationCondition(result *translate.Result) metav1.Condition {
+	renamed, dropped := result.Renamed(), result.Dropped()
+
+	if len(dropped) > 0 {
+		message := fmt.Sprintf("Dropped keys not supported by the chart: %s", changeList(dropped))
+		if len(renamed) > 0 {
+			message += fmt.Sprintf("; renamed keys: %s", changeList(renamed))
+		}
+		return metav1.Condition{
+			Type:    VirtualClusterConditionTranslated,
+			Status:  metav1.ConditionFalse,
+			Reason:  "KeysDropped",
+			Message: message,
+		}
+	}
+
+	if len(renamed) > 0 {
+		return metav1.Condition{
+			Type:    VirtualClusterConditionTranslated,
+			Status:  metav1.ConditionTrue,
+			Reason:  "KeysRenamed",
+			Message: fmt.Sprintf("Renamed legacy keys: %s", changeList(renamed)),
+		}
+	}
+
+	return metav1.Condition{
+		Type:    VirtualClusterConditionTranslated,
+		Status:  metav1.ConditionTrue,
+		Reason:  "Unchanged",
+		Message: "Values match the chart version",
+	}
+}
+
+func changeList(changes []translate.Change) string {
+	items := make([]string, 0, len(changes))
+	for _, c := range changes {
+		items = append(items, c.String())
+	}
+	return strings.Join(items, ", ")
+}
@@ -0,0 +1,200 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"helm.sh/helm/v3/pkg/strvals"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/yaml"
+
+	corev1alpha1 "github.com/OpenVirtualCluster/openvirtualcluster-operator/api/v1alpha1"
+)
+
+const (
+	// defaultValuesKey is the key read from a values reference without valuesKey
+	defaultValuesKey = "values.yaml"
+
+	// Field indexes of the ConfigMaps and Secrets referenced by valuesFrom
+	valuesFromConfigMapIndex = ".spec.valuesFrom.configMap"
+	valuesFromSecretIndex    = ".spec.valuesFrom.secret"
+)
+
+// composeValues merges the default values of the operator, the base values
+// of the class, the values referenced by spec.valuesFrom in order and then
+// spec.values on top of them.
+func (r *VirtualClusterReconciler) composeValues(ctx context.Context, vcluster *corev1alpha1.VirtualCluster, class *corev1alpha1.VirtualClusterClass) (map[string]interface{}, error) {
+	logger := log.FromContext(ctx)
+
+	// Copy the defaults, as setting a target path modifies nested maps in place
+	result := make(map[string]interface{})
+	if len(r.DefaultValues) > 0 {
+		result = runtime.DeepCopyJSON(r.DefaultValues)
+	}
+	if class != nil {
+		values, err := class.GetValues()
+		if err != nil {
+			return nil, err
+		}
+		result = mergeValues(result, values)
+	}
+	for _, ref := range vcluster.Spec.ValuesFrom {
+		data, found, err := r.readValuesReference(ctx, vcluster.Namespace, ref)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			logger.Info("Skipping optional values reference", "kind", ref.Kind, "name", ref.Name)
+			continue
+		}
+
+		if ref.TargetPath != "" {
+			// The value is set verbatim, so secrets never get reinterpreted
+			if err := strvals.ParseLiteralInto(fmt.Sprintf("%s=%s", ref.TargetPath, data), result); err != nil {
+				return nil, fmt.Errorf("failed to set %s from %s/%s: %w", ref.TargetPath, ref.Kind, ref.Name, err)
+			}
+			continue
+		}
+
+		values := make(map[string]interface{})
+		if err := yaml.Unmarshal([]byte(data), &values); err != nil {
+			return nil, fmt.Errorf("failed to parse values from %s/%s: %w", ref.Kind, ref.Name, err)
+		}
+		result = mergeValues(result, values)
+	}
+
+	values, err := vcluster.GetValues()
+	if err != nil {
+		return nil, err
+	}
+	return mergeValues(result, values), nil
+}
+
+// readValuesReference returns the data of the referenced key. Missing
+// optional objects and keys are reported as not found without an error.
+func (r *VirtualClusterReconciler) readValuesReference(ctx context.Context, namespace string, ref corev1alpha1.ValuesReference) (string, bool, error) {
+	key := ref.ValuesKey
+	if key == "" {
+		key = defaultValuesKey
+	}
+	objKey := client.ObjectKey{Namespace: namespace, Name: ref.Name}
+
+	var (
+		data  string
+		found bool
+	)
+	switch ref.Kind {
+	case "ConfigMap":
+		configMap := &corev1.ConfigMap{}
+		if err := r.Get(ctx, objKey, configMap); err != nil {
+			if errors.IsNotFound(err) && ref.Optional {
+				return "", false, nil
+			}
+			return "", false, fmt.Errorf("failed to get values ConfigMap %s: %w", ref.Name, err)
+		}
+		if v, ok := configMap.Data[key]; ok {
+			data, found = v, true
+		} else if v, ok := configMap.BinaryData[key]; ok {
+			data, found = string(v), true
+		}
+	case "Secret":
+		secret := &corev1.Secret{}
+		if err := r.Get(ctx, objKey, secret); err != nil {
+			if errors.IsNotFound(err) && ref.Optional {
+				return "", false, nil
+			}
+			return "", false, fmt.Errorf("failed to get values Secret %s: %w", ref.Name, err)
+		}
+		if v, ok := secret.Data[key]; ok {
+			data, found = string(v), true
+		}
+	default:
+		return "", false, fmt.Errorf("unsupported values reference kind %q", ref.Kind)
+	}
+
+	if !found && !ref.Optional {
+		return "", false, fmt.Errorf("%s %s has no key %s", ref.Kind, ref.Name, key)
+	}
+	return data, found, nil
+}
+
+// mergeValues deep merges override into base. Nested maps are merged, any
+// other value in override replaces the one in base.
+func mergeValues(base, override map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(base))
+	for k, v := range base {
+		out[k] = v
+	}
+	for k, v := range override {
+		if overrideMap, ok := v.(map[string]interface{}); ok {
+			if baseMap, ok := out[k].(map[string]interface{}); ok {
+				out[k] = mergeValues(baseMap, overrideMap)
+				continue
+			}
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// valuesFromIndexer returns an indexer listing the names of the objects of
+// the given kind referenced by spec.valuesFrom.
+func valuesFromIndexer(kind string) client.IndexerFunc {
+	return func(obj client.Object) []string {
+		vcluster, ok := obj.(*corev1alpha1.VirtualCluster)
+		if !ok {
+			return nil
+		}
+		var names []string
+		for _, ref := range vcluster.Spec.ValuesFrom {
+			if ref.Kind == kind {
+				names = append(names, ref.Name)
+			}
+		}
+		return names
+	}
+}
+
+// requestsForValuesObject returns a map function enqueuing the VirtualClusters
+// whose valuesFrom references the changed object.
+func (r *VirtualClusterReconciler) requestsForValuesObject(index string) func(context.Context, client.Object) []reconcile.Request {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		vclusters := &corev1alpha1.VirtualClusterList{}
+		if err := r.List(ctx, vclusters,
+			client.InNamespace(obj.GetNamespace()),
+			client.MatchingFields{index: obj.GetName()},
+		); err != nil {
+			log.FromContext(ctx).Error(err, "Failed to list VirtualClusters referencing values", "name", obj.GetName())
+			return nil
+		}
+
+		requests := make([]reconcile.Request, 0, len(vclusters.Items))
+		for _, vcluster := range vclusters.Items {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: client.ObjectKeyFromObject(&vcluster),
+			})
+		}
+		return requests
+	}
+}
@@ -0,0 +1,197 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	corev1alpha1 "github.com/OpenVirtualCluster/openvirtualcluster-operator/api/v1alpha1"
+)
+
+var _ = Describe("Values From", func() {
+	var (
+		ctx        context.Context
+		reconciler *VirtualClusterReconciler
+		vc         *corev1alpha1.VirtualCluster
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		base := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "base-values", Namespace: "default"},
+			Data: map[string]string{
+				defaultValuesKey: "sync:\n  ingresses:\n    enabled: true\ntelemetry:\n  disabled: false\n",
+			},
+		}
+		database := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "database", Namespace: "default"},
+			Data: map[string][]byte{
+				"password": []byte("s3cr3t,with=chars"),
+			},
+		}
+
+		vc = CreateTestVirtualCluster("values-from-test", "default", `{"telemetry": {"disabled": true}}`)
+		vc.Spec.ValuesFrom = []corev1alpha1.ValuesReference{
+			{Kind: "ConfigMap", Name: "base-values"},
+			{Kind: "Secret", Name: "database", ValuesKey: "password", TargetPath: "external.database.password"},
+		}
+
+		reconciler = NewTestReconciler(nil, nil, base, database, vc)
+	})
+
+	It("should merge referenced values in order before spec.values", func() {
+		values, err := reconciler.composeValues(ctx, vc, nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(values).To(HaveKeyWithValue("sync", map[string]interface{}{
+			"ingresses": map[string]interface{}{"enabled": true},
+		}))
+		// spec.values takes precedence over referenced values
+		Expect(values).To(HaveKeyWithValue("telemetry", map[string]interface{}{"disabled": true}))
+		// Values at a target path are set verbatim
+		Expect(values).To(HaveKeyWithValue("external", map[string]interface{}{
+			"database": map[string]interface{}{"password": "s3cr3t,with=chars"},
+		}))
+	})
+
+	It("should merge the default values of the operator beneath everything else", func() {
+		reconciler.DefaultValues = map[string]interface{}{
+			"sync":      map[string]interface{}{"ingresses": map[string]interface{}{"enabled": false}},
+			"external":  map[string]interface{}{"database": map[string]interface{}{"host": "db"}},
+			"telemetry": map[string]interface{}{"disabled": false},
+		}
+
+		values, err := reconciler.composeValues(ctx, vc, nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(values).To(HaveKeyWithValue("sync", map[string]interface{}{
+			"ingresses": map[string]interface{}{"enabled": true},
+		}))
+		Expect(values).To(HaveKeyWithValue("telemetry", map[string]interface{}{"disabled": true}))
+		Expect(values).To(HaveKeyWithValue("external", map[string]interface{}{
+			"database": map[string]interface{}{"host": "db", "password": "s3cr3t,with=chars"},
+		}))
+		// The defaults are shared by all VirtualClusters and must not change
+		Expect(reconciler.DefaultValues).To(HaveKeyWithValue("external", map[string]interface{}{
+			"database": map[string]interface{}{"host": "db"},
+		}))
+	})
+
+	It("should fail on a missing reference", func() {
+		vc.Spec.ValuesFrom = append(vc.Spec.ValuesFrom, corev1alpha1.ValuesReference{Kind: "ConfigMap", Name: "missing"})
+
+		_, err := reconciler.composeValues(ctx, vc, nil)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should fail on a missing key", func() {
+		vc.Spec.ValuesFrom = []corev1alpha1.ValuesReference{{Kind: "Secret", Name: "database"}}
+
+		_, err := reconciler.composeValues(ctx, vc, nil)
+		Expect(err).To(MatchError(ContainSubstring(defaultValuesKey)))
+	})
+
+	It("should skip missing optional references", func() {
+		vc.Spec.ValuesFrom = append(vc.Spec.ValuesFrom,
+			corev1alpha1.ValuesReference{Kind: "ConfigMap", Name: "missing", Optional: true},
+			corev1alpha1.ValuesReference{Kind: "Secret", Name: "database", ValuesKey: "missing", Optional: true},
+		)
+
+		values, err := reconciler.composeValues(ctx, vc, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(values).To(HaveKey("external"))
+	})
+
+	It("should enqueue VirtualClusters referencing a changed object", func() {
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "database", Namespace: "default"}}
+		requests := reconciler.requestsForValuesObject(valuesFromSecretIndex)(ctx, secret)
+		Expect(requests).To(HaveLen(1))
+		Expect(requests[0].NamespacedName).To(Equal(types.NamespacedName{Namespace: "default", Name: vc.Name}))
+
+		unrelated := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "database", Namespace: "default"}}
+		Expect(reconciler.requestsForValuesObject(valuesFromConfigMapIndex)(ctx, unrelated)).To(BeEmpty())
+	})
+})
+
+var _ = Describe("mergeValues", func() {
+	It("should deep merge nested maps and replace other values", func() {
+		base := map[string]interface{}{
+			"sync":  map[string]interface{}{"ingresses": true, "services": true},
+			"plain": []interface{}{"a"},
+		}
+		override := map[string]interface{}{
+			"sync":  map[string]interface{}{"ingresses": false},
+			"plain": []interface{}{"b"},
+		}
+
+		Expect(mergeValues(base, override)).To(Equal(map[string]interface{}{
+			"sync":  map[string]interface{}{"ingresses": false, "services": true},
+			"plain": []interface{}{"b"},
+		}))
+		// The inputs are left untouched
+		Expect(base["sync"]).To(HaveKeyWithValue("ingresses", true))
+	})
+})
+
+var _ = Describe("Values Translation", func() {
+	var (
+		ctx        context.Context
+		reconciler *VirtualClusterReconciler
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		reconciler = NewTestReconciler(nil, nil)
+	})
+
+	It("should report renamed legacy keys", func() {
+		vc := CreateTestVirtualCluster("translate-test", "default", "")
+
+		values, err := reconciler.translateValues(ctx, vc, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(values).To(HaveKey("controlPlane"))
+
+		cond := meta.FindStatusCondition(vc.Status.Conditions, VirtualClusterConditionTranslated)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+		Expect(cond.Reason).To(Equal("KeysRenamed"))
+		Expect(cond.Message).To(ContainSubstring("vcluster.image -> controlPlane.distro.k3s.image"))
+	})
+
+	It("should report dropped keys instead of silently removing them", func() {
+		vc := CreateTestVirtualCluster("translate-test", "default", `{"unknown": {"key": "value"}, "sync": {}}`)
+
+		values, err := reconciler.translateValues(ctx, vc, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(values).To(Equal(map[string]interface{}{"sync": map[string]interface{}{}}))
+
+		cond := meta.FindStatusCondition(vc.Status.Conditions, VirtualClusterConditionTranslated)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+		Expect(cond.Reason).To(Equal("KeysDropped"))
+		Expect(cond.Message).To(ContainSubstring("unknown.key"))
+	})
+})
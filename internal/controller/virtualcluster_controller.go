@@ -18,35 +18,38 @@ package controller
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
-	"os"
-	"os/exec"
-	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 
-	"sigs.k8s.io/yaml"
-
-	"github.com/xeipuuv/gojsonschema"
+	"github.com/Masterminds/semver/v3"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	corev1alpha1 "github.com/OpenVirtualCluster/openvirtualcluster-operator/api/v1alpha1"
+	"github.com/OpenVirtualCluster/openvirtualcluster-operator/internal/helm"
+	"github.com/OpenVirtualCluster/openvirtualcluster-operator/internal/schema"
 )
 
 const (
 	// Define a constant for the vCluster version
-	vclusterVersion = "v0.24.1"
+	vclusterVersion = corev1alpha1.DefaultChartVersion
 	// Define a constant for the vCluster chart name
 	vclusterChart = "vcluster"
 	// Define a constant for the vCluster chart repo
@@ -59,6 +62,27 @@ const (
 	VirtualClusterConditionDeploying = "Deploying"
 	VirtualClusterConditionError     = "Error"
 	VirtualClusterConditionValidated = "SchemaValidated"
+	// VirtualClusterConditionTranslated reports legacy value keys that were
+	// renamed or dropped for the chart version
+	VirtualClusterConditionTranslated = "ValuesTranslated"
+	// VirtualClusterConditionDrifted reports whether the Helm release drifted
+	// from the desired state
+	VirtualClusterConditionDrifted = "Drifted"
+	// VirtualClusterConditionDegraded reports a running VirtualCluster whose
+	// control plane became unhealthy
+	VirtualClusterConditionDegraded = "Degraded"
+	// VirtualClusterConditionReleaseRecovered reports that a Helm release
+	// stuck in a pending state was recovered
+	VirtualClusterConditionReleaseRecovered = "ReleaseRecovered"
+	// VirtualClusterConditionSuspended reports whether the reconciliation is
+	// suspended through spec.suspend
+	VirtualClusterConditionSuspended = "Suspended"
+	// VirtualClusterConditionExpiring reports a VirtualCluster that is about
+	// to be deleted as it expires
+	VirtualClusterConditionExpiring = "Expiring"
+	// VirtualClusterConditionIsolated reports whether the isolation resources
+	// of spec.isolation are in place in the host namespace
+	VirtualClusterConditionIsolated = "Isolated"
 )
 
 // VirtualClusterReconciler reconciles a VirtualCluster object
@@ -66,18 +90,67 @@ type VirtualClusterReconciler struct {
 	client.Client
 	Scheme   *runtime.Scheme
 	Recorder record.EventRecorder
+	Helm     helm.ReleaseEngine
+
+	// DefaultValidationMode is used for VirtualClusters that don't set
+	// spec.validation.mode. Defaults to Warn.
+	DefaultValidationMode corev1alpha1.ValidationMode
+
+	// OfflineChartDir is a directory in the operator image holding packaged
+	// charts. When set, VirtualClusters without a chart repository are
+	// deployed from it without network access.
+	OfflineChartDir string
+
+	// DefaultValues are operator-level values merged beneath the values of
+	// every VirtualCluster.
+	DefaultValues map[string]interface{}
+
+	// ResyncInterval is the interval running VirtualClusters are checked for
+	// drift of their Helm release. Zero disables the periodic resync.
+	ResyncInterval time.Duration
+
+	// DefaultSelfHeal is used for VirtualClusters that don't set
+	// spec.drift.selfHeal.
+	DefaultSelfHeal bool
+
+	// Prober probes the /readyz endpoint of virtual API servers. If nil, only
+	// the readiness of the control plane workload is checked.
+	Prober APIServerProber
+
+	// PendingReleaseTimeout is how long a Helm release may stay in a pending
+	// state before it is recovered. Zero disables the recovery.
+	PendingReleaseTimeout time.Duration
+
+	// DeletionTimeout is how long the deletion of a VirtualCluster may wait for
+	// its resources to be gone before it reports an error. Zero waits without
+	// reporting.
+	DeletionTimeout time.Duration
+
+	// ActivityProber reads the activity of virtual API servers for
+	// spec.idleTimeout. If nil, the idle timeout doesn't apply.
+	ActivityProber ActivityProber
+
+	// requestCounts holds the last request count of each API server probed
+	// for activity, keyed by the UID of the VirtualCluster.
+	requestCounts sync.Map
 }
 
 // +kubebuilder:rbac:groups=core.openvc.dev,resources=virtualclusters,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core.openvc.dev,resources=virtualclusters/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=core.openvc.dev,resources=virtualclusters/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core.openvc.dev,resources=virtualclusterclasses,verbs=get;list;watch
 // +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch;create;update;delete
 // +kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;create;update;delete
 // +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;delete
 // +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;delete
+// +kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list;watch;delete
+// +kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=resourcequotas,verbs=get;list;watch;create;update;delete
+// +kubebuilder:rbac:groups=core,resources=limitranges,verbs=get;list;watch;create;update;delete
 // +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;delete
 // +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch;create;update;delete
 // +kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list;watch;create;update;delete
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create;update;delete
 // +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
@@ -136,6 +209,68 @@ func (r *VirtualClusterReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		return ctrl.Result{}, nil
 	}
 
+	// Leave the release alone while the reconciliation is suspended
+	if vcluster.Spec.Suspend {
+		return r.reconcileSuspended(ctx, vcluster)
+	}
+	r.resumeReconcile(ctx, vcluster)
+
+	// Resolve the class providing the chart, base values and policies
+	class, err := r.resolveClass(ctx, vcluster)
+	if err != nil {
+		if vcluster.ObjectMeta.DeletionTimestamp.IsZero() {
+			logger.Error(err, "Failed to resolve VirtualClusterClass")
+
+			// Update status to Failed
+			vcluster.Status.Phase = corev1alpha1.VirtualClusterFailed
+			vcluster.Status.Message = err.Error()
+
+			meta.SetStatusCondition(&vcluster.Status.Conditions, metav1.Condition{
+				Type:    VirtualClusterConditionError,
+				Status:  metav1.ConditionTrue,
+				Reason:  "ClassNotFound",
+				Message: err.Error(),
+			})
+
+			if err := r.Status().Update(ctx, vcluster); err != nil {
+				logger.Error(err, "Failed to update VirtualCluster status")
+			}
+
+			r.Recorder.Event(vcluster, corev1.EventTypeWarning, "ClassNotFound", err.Error())
+
+			// Creating the class triggers a new reconcile
+			if errors.IsNotFound(err) {
+				return ctrl.Result{}, nil
+			}
+			return ctrl.Result{}, err
+		}
+
+		// Finalizing with the default policy would delete the data of a
+		// VirtualCluster whose class retains or orphans it
+		if vcluster.Spec.DeletionPolicy == "" && vcluster.Status.DeletionPolicy == "" {
+			logger.Error(err, "Failed to resolve VirtualClusterClass, not finalizing without its deletion policy")
+
+			message := fmt.Sprintf("Deletion policy of the class is unknown, set spec.deletionPolicy to finalize: %v", err)
+			meta.SetStatusCondition(&vcluster.Status.Conditions, metav1.Condition{
+				Type:    VirtualClusterConditionError,
+				Status:  metav1.ConditionTrue,
+				Reason:  "ClassNotFound",
+				Message: message,
+			})
+
+			if err := r.Status().Update(ctx, vcluster); err != nil {
+				logger.Error(err, "Failed to update VirtualCluster status")
+			}
+
+			r.Recorder.Event(vcluster, corev1.EventTypeWarning, "ClassNotFound", message)
+			return ctrl.Result{}, err
+		}
+		logger.Error(err, "Failed to resolve VirtualClusterClass, finalizing with the recorded deletion policy")
+	} else {
+		// Record the deletion policy while the class is resolvable
+		vcluster.Status.DeletionPolicy = r.deletionPolicy(vcluster, class)
+	}
+
 	// Check if the VirtualCluster instance is marked to be deleted
 	if !vcluster.ObjectMeta.DeletionTimestamp.IsZero() {
 		// The object is being deleted
@@ -153,7 +288,9 @@ func (r *VirtualClusterReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 
 		if controllerutil.ContainsFinalizer(vcluster, vclusterFinalizer) {
 			// Run finalization logic
-			if err := r.finalizeVirtualCluster(ctx, vcluster); err != nil {
+			if err := r.finalizeVirtualCluster(ctx, vcluster); err != nil && forceDelete(vcluster) {
+				logger.Error(err, "Failed to finalize VirtualCluster, removing it anyway as it is force deleted")
+			} else if err != nil {
 				// If finalization fails, return error so that we can retry
 				logger.Error(err, "Failed to finalize VirtualCluster")
 
@@ -173,6 +310,18 @@ func (r *VirtualClusterReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 				return ctrl.Result{}, err
 			}
 
+			// Wait for the deleted resources to be gone
+			if !forceDelete(vcluster) {
+				remaining, err := r.remainingResources(ctx, vcluster)
+				if err != nil {
+					logger.Error(err, "Failed to check the resources of the VirtualCluster")
+					return ctrl.Result{}, err
+				}
+				if len(remaining) > 0 {
+					return r.waitForDeletion(ctx, vcluster, remaining)
+				}
+			}
+
 			// Remove finalizer once finalization is done
 			controllerutil.RemoveFinalizer(vcluster, vclusterFinalizer)
 			err = r.Update(ctx, vcluster)
@@ -184,6 +333,19 @@ func (r *VirtualClusterReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		return ctrl.Result{}, nil
 	}
 
+	// Delete the VirtualCluster once it expired
+	expiryResult, expired, err := r.reconcileExpiry(ctx, vcluster)
+	if err != nil || expired {
+		return expiryResult, err
+	}
+
+	// Scale the vcluster to zero while it hibernates
+	hibernationResult, hibernated, err := r.reconcileHibernation(ctx, vcluster)
+	requeueAfter := shorterRequeue(hibernationResult.RequeueAfter, expiryResult.RequeueAfter)
+	if err != nil || hibernated {
+		return ctrl.Result{RequeueAfter: requeueAfter}, err
+	}
+
 	// Update status to Provisioning
 	if vcluster.Status.Phase == corev1alpha1.VirtualClusterPending {
 		vcluster.Status.Phase = corev1alpha1.VirtualClusterProvisioning
@@ -202,67 +364,260 @@ func (r *VirtualClusterReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		}
 	}
 
-	// Create the values file
-	valuesFile, err := r.createValuesFile(ctx, vcluster)
-	if err != nil {
-		logger.Error(err, "Failed to create values file")
+	// Skip the Helm operation unless the spec, the effective values or the
+	// release changed since the last apply
+	upToDate := r.releaseUpToDate(ctx, vcluster, class)
+	if !upToDate {
+		// Create the target namespace before Helm deploys into it
+		if err := r.ensureTargetNamespace(ctx, vcluster); err != nil {
+			logger.Error(err, "Failed to ensure target namespace")
+
+			// Update status to Failed
+			vcluster.Status.Phase = corev1alpha1.VirtualClusterFailed
+			vcluster.Status.Message = err.Error()
+
+			meta.SetStatusCondition(&vcluster.Status.Conditions, metav1.Condition{
+				Type:    VirtualClusterConditionError,
+				Status:  metav1.ConditionTrue,
+				Reason:  "TargetNamespaceFailed",
+				Message: err.Error(),
+			})
+
+			if err := r.Status().Update(ctx, vcluster); err != nil {
+				logger.Error(err, "Failed to update VirtualCluster status")
+			}
+
+			r.Recorder.Event(vcluster, corev1.EventTypeWarning, "TargetNamespaceFailed", err.Error())
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Keep the isolation resources in place on every reconcile, and before
+	// Helm deploys so they apply to the control plane as well
+	if err := r.reconcileIsolation(ctx, vcluster); err != nil {
+		logger.Error(err, "Failed to reconcile isolation resources")
 
 		// Update status to Failed
 		vcluster.Status.Phase = corev1alpha1.VirtualClusterFailed
-		vcluster.Status.Message = fmt.Sprintf("Failed to create values file: %v", err)
+		vcluster.Status.Message = err.Error()
+
+		meta.SetStatusCondition(&vcluster.Status.Conditions, metav1.Condition{
+			Type:    VirtualClusterConditionIsolated,
+			Status:  metav1.ConditionFalse,
+			Reason:  isolationReasonFailed,
+			Message: err.Error(),
+		})
 
 		meta.SetStatusCondition(&vcluster.Status.Conditions, metav1.Condition{
 			Type:    VirtualClusterConditionError,
 			Status:  metav1.ConditionTrue,
-			Reason:  "ValuesFileCreationFailed",
-			Message: fmt.Sprintf("Failed to create values file: %v", err),
+			Reason:  isolationReasonFailed,
+			Message: err.Error(),
 		})
 
 		if err := r.Status().Update(ctx, vcluster); err != nil {
 			logger.Error(err, "Failed to update VirtualCluster status")
 		}
 
+		r.Recorder.Event(vcluster, corev1.EventTypeWarning, isolationReasonFailed, err.Error())
 		return ctrl.Result{}, err
 	}
 
-	// Install or upgrade the vCluster
-	err = r.installOrUpgradeVCluster(ctx, vcluster, valuesFile)
+	if !upToDate {
+		// Get the values translated for the chart version. They are handed to
+		// Helm in memory and never written to disk, as they may hold
+		// credentials from referenced Secrets.
+		values, err := r.translateValues(ctx, vcluster, class)
+		if err != nil {
+			logger.Error(err, "Failed to get values from VirtualCluster")
+
+			// Update status to Failed
+			vcluster.Status.Phase = corev1alpha1.VirtualClusterFailed
+			vcluster.Status.Message = fmt.Sprintf("Failed to compose values: %v", err)
+
+			meta.SetStatusCondition(&vcluster.Status.Conditions, metav1.Condition{
+				Type:    VirtualClusterConditionError,
+				Status:  metav1.ConditionTrue,
+				Reason:  "ValuesCompositionFailed",
+				Message: fmt.Sprintf("Failed to compose values: %v", err),
+			})
+
+			if err := r.Status().Update(ctx, vcluster); err != nil {
+				logger.Error(err, "Failed to update VirtualCluster status")
+			}
+
+			return ctrl.Result{}, err
+		}
+
+		// Persist the ValuesTranslated condition before Helm runs
+		if err := r.Status().Update(ctx, vcluster); err != nil {
+			logger.Error(err, "Failed to update VirtualCluster status")
+			return ctrl.Result{}, err
+		}
+
+		// Install or upgrade the vCluster
+		err = r.installOrUpgradeVCluster(ctx, vcluster, class, values)
+		if validationErr, ok := schema.AsValidationError(err); ok {
+			logger.Error(err, "Values failed schema validation, not deploying vCluster")
+
+			// Update status to Failed
+			vcluster.Status.Phase = corev1alpha1.VirtualClusterFailed
+			vcluster.Status.Message = fmt.Sprintf("Values failed schema validation: %v", err)
+
+			meta.SetStatusCondition(&vcluster.Status.Conditions, metav1.Condition{
+				Type:    VirtualClusterConditionError,
+				Status:  metav1.ConditionTrue,
+				Reason:  "SchemaValidationFailed",
+				Message: err.Error(),
+			})
+
+			if err := r.Status().Update(ctx, vcluster); err != nil {
+				logger.Error(err, "Failed to update VirtualCluster status")
+				return ctrl.Result{}, err
+			}
+
+			// Record an event
+			r.Recorder.Event(vcluster, corev1.EventTypeWarning, "SchemaValidationFailed",
+				fmt.Sprintf("Values failed schema validation at %s", strings.Join(validationErr.Paths(), ", ")))
+
+			// Retrying won't help until the values change, which triggers a new
+			// reconcile, but the VirtualCluster may still expire
+			return ctrl.Result{RequeueAfter: expiryResult.RequeueAfter}, nil
+		}
+		if isUpgradeRolledBack(err) {
+			// The rollback was reported when it happened
+			vcluster.Status.Phase = corev1alpha1.VirtualClusterFailed
+			vcluster.Status.Message = err.Error()
+
+			meta.SetStatusCondition(&vcluster.Status.Conditions, metav1.Condition{
+				Type:    VirtualClusterConditionError,
+				Status:  metav1.ConditionTrue,
+				Reason:  "UpgradeRolledBack",
+				Message: err.Error(),
+			})
+
+			if err := r.Status().Update(ctx, vcluster); err != nil {
+				logger.Error(err, "Failed to update VirtualCluster status")
+				return ctrl.Result{}, err
+			}
+
+			// Changing the spec or values triggers a new reconcile
+			return ctrl.Result{RequeueAfter: expiryResult.RequeueAfter}, nil
+		}
+		if isChartVersionMismatch(err) {
+			logger.Error(err, "Offline chart doesn't match the requested chart version")
+
+			// Update status to Failed
+			vcluster.Status.Phase = corev1alpha1.VirtualClusterFailed
+			vcluster.Status.Message = err.Error()
+
+			meta.SetStatusCondition(&vcluster.Status.Conditions, metav1.Condition{
+				Type:    VirtualClusterConditionError,
+				Status:  metav1.ConditionTrue,
+				Reason:  "ChartVersionMismatch",
+				Message: err.Error(),
+			})
+
+			if err := r.Status().Update(ctx, vcluster); err != nil {
+				logger.Error(err, "Failed to update VirtualCluster status")
+			}
+
+			// Record an event
+			r.Recorder.Event(vcluster, corev1.EventTypeWarning, "ChartVersionMismatch", err.Error())
+
+			// Retry in case the archive is replaced
+			return ctrl.Result{}, err
+		}
+		if err != nil {
+			logger.Error(err, "Failed to install or upgrade vCluster")
+
+			// Update status to Failed
+			vcluster.Status.Phase = corev1alpha1.VirtualClusterFailed
+			vcluster.Status.Message = fmt.Sprintf("Failed to install or upgrade vCluster: %v", err)
+
+			meta.SetStatusCondition(&vcluster.Status.Conditions, metav1.Condition{
+				Type:    VirtualClusterConditionError,
+				Status:  metav1.ConditionTrue,
+				Reason:  "HelmOperationFailed",
+				Message: fmt.Sprintf("Failed to install or upgrade vCluster: %v", err),
+			})
+
+			meta.SetStatusCondition(&vcluster.Status.Conditions, metav1.Condition{
+				Type:    VirtualClusterConditionAvailable,
+				Status:  metav1.ConditionFalse,
+				Reason:  "HelmOperationFailed",
+				Message: "VirtualCluster is not available due to Helm operation failure",
+			})
+
+			if err := r.Status().Update(ctx, vcluster); err != nil {
+				logger.Error(err, "Failed to update VirtualCluster status")
+			}
+
+			// Record an event
+			r.Recorder.Event(vcluster, corev1.EventTypeWarning, "InstallFailed",
+				fmt.Sprintf("Failed to install or upgrade vCluster: %v", err))
+
+			return ctrl.Result{}, err
+		}
+	}
+	vcluster.Status.ObservedGeneration = vcluster.Generation
+
+	// The release being deployed doesn't mean the virtual cluster works, so
+	// only a healthy control plane makes it available
+	health, err := r.checkControlPlane(ctx, vcluster)
 	if err != nil {
-		logger.Error(err, "Failed to install or upgrade vCluster")
+		logger.Error(err, "Failed to check the control plane")
+		return ctrl.Result{}, err
+	}
+	if !health.Ready {
+		logger.Info("Control plane is not ready", "reason", health.Reason, "message", health.Message)
 
-		// Update status to Failed
-		vcluster.Status.Phase = corev1alpha1.VirtualClusterFailed
-		vcluster.Status.Message = fmt.Sprintf("Failed to install or upgrade vCluster: %v", err)
+		degraded := false
+		if vcluster.Status.Phase == corev1alpha1.VirtualClusterRunning {
+			vcluster.Status.Message = fmt.Sprintf("VirtualCluster is degraded: %s", health.Message)
+			degraded = !meta.IsStatusConditionTrue(vcluster.Status.Conditions, VirtualClusterConditionDegraded)
 
-		meta.SetStatusCondition(&vcluster.Status.Conditions, metav1.Condition{
-			Type:    VirtualClusterConditionError,
-			Status:  metav1.ConditionTrue,
-			Reason:  "HelmOperationFailed",
-			Message: fmt.Sprintf("Failed to install or upgrade vCluster: %v", err),
-		})
+			meta.SetStatusCondition(&vcluster.Status.Conditions, metav1.Condition{
+				Type:    VirtualClusterConditionDegraded,
+				Status:  metav1.ConditionTrue,
+				Reason:  health.Reason,
+				Message: health.Message,
+			})
+		} else {
+			vcluster.Status.Phase = corev1alpha1.VirtualClusterProvisioning
+			vcluster.Status.Message = fmt.Sprintf("Waiting for the control plane: %s", health.Message)
+
+			meta.SetStatusCondition(&vcluster.Status.Conditions, metav1.Condition{
+				Type:    VirtualClusterConditionDeploying,
+				Status:  metav1.ConditionTrue,
+				Reason:  "WaitingForControlPlane",
+				Message: health.Message,
+			})
+		}
 
 		meta.SetStatusCondition(&vcluster.Status.Conditions, metav1.Condition{
 			Type:    VirtualClusterConditionAvailable,
 			Status:  metav1.ConditionFalse,
-			Reason:  "HelmOperationFailed",
-			Message: "VirtualCluster is not available due to Helm operation failure",
+			Reason:  health.Reason,
+			Message: health.Message,
 		})
 
 		if err := r.Status().Update(ctx, vcluster); err != nil {
 			logger.Error(err, "Failed to update VirtualCluster status")
+			return ctrl.Result{}, err
 		}
 
-		// Record an event
-		r.Recorder.Event(vcluster, corev1.EventTypeWarning, "InstallFailed",
-			fmt.Sprintf("Failed to install or upgrade vCluster: %v", err))
+		if degraded {
+			r.Recorder.Event(vcluster, corev1.EventTypeWarning, "Degraded", health.Message)
+		}
 
-		return ctrl.Result{}, err
+		return ctrl.Result{RequeueAfter: controlPlaneRequeueInterval}, nil
 	}
 
 	// Check if the status should be updated to Running
-	if vcluster.Status.Phase != corev1alpha1.VirtualClusterRunning {
+	deployed := vcluster.Status.Phase != corev1alpha1.VirtualClusterRunning
+	if deployed {
 		vcluster.Status.Phase = corev1alpha1.VirtualClusterRunning
-		vcluster.Status.Message = "VirtualCluster is running"
 
 		meta.SetStatusCondition(&vcluster.Status.Conditions, metav1.Condition{
 			Type:    VirtualClusterConditionDeploying,
@@ -271,122 +626,79 @@ func (r *VirtualClusterReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 			Message: "VirtualCluster has been deployed",
 		})
 
-		meta.SetStatusCondition(&vcluster.Status.Conditions, metav1.Condition{
-			Type:    VirtualClusterConditionAvailable,
-			Status:  metav1.ConditionTrue,
-			Reason:  "Running",
-			Message: "VirtualCluster is available and running",
-		})
-
 		meta.SetStatusCondition(&vcluster.Status.Conditions, metav1.Condition{
 			Type:    VirtualClusterConditionError,
 			Status:  metav1.ConditionFalse,
 			Reason:  "NoError",
 			Message: "No errors detected",
 		})
+	}
+	vcluster.Status.Message = "VirtualCluster is running"
+
+	meta.SetStatusCondition(&vcluster.Status.Conditions, metav1.Condition{
+		Type:    VirtualClusterConditionAvailable,
+		Status:  metav1.ConditionTrue,
+		Reason:  "Running",
+		Message: "VirtualCluster is available and running",
+	})
+
+	meta.SetStatusCondition(&vcluster.Status.Conditions, metav1.Condition{
+		Type:    VirtualClusterConditionDegraded,
+		Status:  metav1.ConditionFalse,
+		Reason:  health.Reason,
+		Message: health.Message,
+	})
+
+	// Export the kubeconfig written by the control plane
+	exported, exportErr := r.exportKubeconfig(ctx, vcluster, class)
+	if exportErr != nil {
+		logger.Error(exportErr, "Failed to export kubeconfig")
+		r.Recorder.Event(vcluster, corev1.EventTypeWarning, "KubeconfigExportFailed",
+			fmt.Sprintf("Failed to export kubeconfig: %v", exportErr))
+	}
 
-		if err := r.Status().Update(ctx, vcluster); err != nil {
-			logger.Error(err, "Failed to update VirtualCluster status")
-			return ctrl.Result{}, err
-		}
+	// Always persist the status as the applied hash and the Drifted
+	// condition change on every reconcile
+	if err := r.Status().Update(ctx, vcluster); err != nil {
+		logger.Error(err, "Failed to update VirtualCluster status")
+		return ctrl.Result{}, err
+	}
 
+	if deployed {
 		// Record an event
 		r.Recorder.Event(vcluster, corev1.EventTypeNormal, "Deployed",
 			"VirtualCluster has been successfully deployed")
 	}
 
-	return ctrl.Result{}, nil
-}
-
-// createValuesFile creates a temporary values file for the vCluster Helm chart
-func (r *VirtualClusterReconciler) createValuesFile(ctx context.Context, vcluster *corev1alpha1.VirtualCluster) (string, error) {
-	logger := log.FromContext(ctx)
-
-	// Get the values
-	values, err := vcluster.GetValues()
-	if err != nil {
-		logger.Error(err, "Failed to get values from VirtualCluster")
-		return "", err
-	}
-
-	// Transform values to vCluster format according to schema
-	transformedValues := make(map[string]interface{})
-
-	// Map 'vcluster' section to appropriate controlPlane fields
-	if vclusterConfig, ok := values["vcluster"].(map[string]interface{}); ok {
-		// In schema, the K3s/K8s image is defined within controlPlane
-		controlPlane := make(map[string]interface{})
-
-		// Set the image if specified
-		if image, ok := vclusterConfig["image"].(string); ok {
-			controlPlane["image"] = image
-		}
-
-		transformedValues["controlPlane"] = controlPlane
-	}
-
-	// Map 'storage' (persistence) section to correct fields according to schema
-	if storage, ok := values["storage"].(map[string]interface{}); ok {
-		if persistence, ok := storage["persistence"].(bool); ok {
-			// Set storage-related fields
-			if !persistence {
-				// If persistence is false, we need to disable it in the vcluster chart
-				transformedValues["networking"] = map[string]interface{}{
-					"podCIDR":         "10.42.0.0/16",
-					"serviceCIDR":     "10.43.0.0/16",
-					"isolation":       "enabled",
-					"cloudController": false,
-				}
-			}
-		}
+	if exportErr != nil {
+		return ctrl.Result{}, exportErr
 	}
-
-	// Map 'telemetry' section to correct schema field
-	if telemetry, ok := values["telemetry"].(map[string]interface{}); ok {
-		telemetryConfig := make(map[string]interface{})
-		if disabled, ok := telemetry["disabled"].(bool); ok {
-			telemetryConfig["enabled"] = !disabled
-		}
-		transformedValues["telemetry"] = telemetryConfig
+	if !exported {
+		return ctrl.Result{RequeueAfter: controlPlaneRequeueInterval}, nil
 	}
 
-	// Copy any other fields that match the schema
-	validRootFields := []string{
-		"controlPlane", "experimental", "exportKubeConfig", "external",
-		"global", "integrations", "networking", "plugin", "plugins",
-		"policies", "pro", "rbac", "serviceCIDR", "sleepMode", "sync", "telemetry",
-	}
+	// Check the release for drift again after the resync interval, or
+	// earlier when a hibernation schedule fires or the VirtualCluster expires
+	return ctrl.Result{RequeueAfter: shorterRequeue(r.ResyncInterval, requeueAfter)}, nil
+}
 
-	for _, field := range validRootFields {
-		if v, ok := values[field]; ok {
-			transformedValues[field] = v
-		}
-	}
+// installOrUpgradeVCluster installs or upgrades the vCluster using Helm with
+// the values returned by translateValues
+func (r *VirtualClusterReconciler) installOrUpgradeVCluster(ctx context.Context, vcluster *corev1alpha1.VirtualCluster, class *corev1alpha1.VirtualClusterClass, values map[string]interface{}) error {
+	logger := log.FromContext(ctx)
+	logger.Info("Installing or upgrading vCluster", "namespace", vcluster.Namespace, "name", vcluster.Name)
 
-	// Marshal to YAML
-	transformedYaml, err := yaml.Marshal(transformedValues)
-	if err != nil {
-		logger.Error(err, "Failed to marshal transformed values to YAML")
-		return "", err
+	// Helm refuses to operate on a release left pending by an interrupted
+	// operation
+	if err := r.recoverPendingRelease(ctx, vcluster, class); err != nil {
+		return err
 	}
 
-	// Create the values file
-	valuesFilePath := filepath.Join(os.TempDir(), fmt.Sprintf("values-%s-%s.yaml", vcluster.Name, vcluster.Namespace))
-	logger.Info("Writing values file", "path", valuesFilePath)
-	err = os.WriteFile(valuesFilePath, transformedYaml, 0644)
-	if err != nil {
-		logger.Error(err, "Failed to write values file")
-		return "", err
+	// A requested rollback holds the release at a previous revision
+	if vcluster.Spec.Rollback != nil {
+		return r.rollbackToRevision(ctx, vcluster, class)
 	}
-
-	logger.Info("Created values file", "path", valuesFilePath)
-	return valuesFilePath, nil
-}
-
-// installOrUpgradeVCluster installs or upgrades the vCluster using Helm
-func (r *VirtualClusterReconciler) installOrUpgradeVCluster(ctx context.Context, vcluster *corev1alpha1.VirtualCluster, valuesFile string) error {
-	logger := log.FromContext(ctx)
-	logger.Info("Installing or upgrading vCluster", "namespace", vcluster.Namespace, "name", vcluster.Name)
+	vcluster.Status.RollbackRevision = 0
 
 	// Check if the Helm release exists
 	exists, err := r.helmReleaseExists(ctx, vcluster)
@@ -394,33 +706,48 @@ func (r *VirtualClusterReconciler) installOrUpgradeVCluster(ctx context.Context,
 		return err
 	}
 
-	// Prepare the Helm command
-	var cmd *exec.Cmd
 	releaseName := vcluster.Name
-	namespace := vcluster.Namespace
+	namespace := vcluster.ReleaseNamespace()
 
 	// Get the chart version from spec if provided, otherwise use default
-	chartVersion := vclusterVersion
-	if vcluster.Spec.Chart.Version != "" {
-		chartVersion = vcluster.Spec.Chart.Version
-		logger.Info("Using chart version from spec", "version", chartVersion)
-	} else {
-		logger.Info("Using default chart version", "version", chartVersion)
-	}
+	chartVersion := r.chartVersionFor(vcluster, class)
+	logger.Info("Using chart version", "version", chartVersion)
 
-	// Ensure schema ConfigMap exists
-	schemaData, err := r.ensureSchemaConfigMap(ctx, vcluster, chartVersion)
+	// Resolve the source serving the chart
+	chart, err := r.resolveChart(ctx, vcluster, class, chartVersion)
 	if err != nil {
-		logger.Error(err, "Failed to ensure schema ConfigMap exists")
-		// Continue anyway, just log the error
-		logger.Info("Continuing without schema validation")
+		return err
 	}
+	vcluster.Status.HelmChart = chart.Location
+	vcluster.Status.ChartSource = chart.SourceType
 
-	// Validate values against schema if we have the schema, but don't fail if validation fails
-	if schemaData != "" {
-		if err := r.validateValuesAgainstSchema(ctx, vcluster, schemaData); err != nil {
-			logger.Error(err, "Schema validation failed")
-			// Don't return error, we continue but update the status
+	var schemaData string
+	if chart.offline() {
+		// Offline charts carry their own schema and never touch the network
+		schemaData = chart.Schema
+	} else {
+		// Ensure schema ConfigMap exists
+		schemaData, err = r.ensureSchemaConfigMap(ctx, vcluster, chartVersion)
+		if err != nil {
+			logger.Error(err, "Failed to ensure schema ConfigMap exists")
+			// Continue anyway, just log the error
+			logger.Info("Continuing without schema validation")
+		}
+	}
+
+	// Validate values against schema if we have the schema. Only the Enforce
+	// mode stops the installation when validation fails.
+	mode := r.validationMode(vcluster, class)
+	if mode == corev1alpha1.ValidationModeDisabled {
+		meta.SetStatusCondition(&vcluster.Status.Conditions, metav1.Condition{
+			Type:    VirtualClusterConditionValidated,
+			Status:  metav1.ConditionUnknown,
+			Reason:  "ValidationDisabled",
+			Message: "Values schema validation is disabled",
+		})
+	} else if schemaData != "" {
+		if err := r.validateValuesAgainstSchema(ctx, vcluster, class, schemaData); err != nil {
+			logger.Error(err, "Schema validation failed", "mode", mode)
 			meta.SetStatusCondition(&vcluster.Status.Conditions, metav1.Condition{
 				Type:    VirtualClusterConditionValidated,
 				Status:  metav1.ConditionFalse,
@@ -428,6 +755,10 @@ func (r *VirtualClusterReconciler) installOrUpgradeVCluster(ctx context.Context,
 				Message: fmt.Sprintf("Values schema validation failed: %v", err),
 			})
 
+			if mode == corev1alpha1.ValidationModeEnforce {
+				return err
+			}
+
 			if vcluster.Status.Message == "" || !strings.Contains(vcluster.Status.Message, "Schema validation") {
 				oldMessage := vcluster.Status.Message
 				vcluster.Status.Message = fmt.Sprintf("Schema validation failed, helm install might fail: %v. %s", err, oldMessage)
@@ -450,65 +781,139 @@ func (r *VirtualClusterReconciler) installOrUpgradeVCluster(ctx context.Context,
 				logger.Error(err, "Failed to update status with validation success")
 			}
 		}
+	} else if mode == corev1alpha1.ValidationModeEnforce {
+		// Report the skipped enforcement once instead of on every reconcile
+		previous := meta.FindStatusCondition(vcluster.Status.Conditions, VirtualClusterConditionValidated)
+		if previous == nil || previous.Reason != "SchemaEnforcementSkipped" {
+			r.Recorder.Event(vcluster, corev1.EventTypeWarning, "SchemaEnforcementSkipped",
+				fmt.Sprintf("Chart version %s has no values schema, the values are deployed without validation", chartVersion))
+		}
+		meta.SetStatusCondition(&vcluster.Status.Conditions, metav1.Condition{
+			Type:    VirtualClusterConditionValidated,
+			Status:  metav1.ConditionUnknown,
+			Reason:  "SchemaEnforcementSkipped",
+			Message: fmt.Sprintf("No values schema is available for chart version %s, schema enforcement was skipped", chartVersion),
+		})
+	}
+
+	req := helm.ReleaseRequest{
+		Name:      releaseName,
+		Namespace: namespace,
+		Chart:     chart.Ref,
+		Values:    values,
+		Options:   r.operationOptions(vcluster, class),
+	}
+
+	desiredHash, err := releaseHash(chartVersion, req.Values)
+	if err != nil {
+		return err
 	}
 
-	// Set correct environment for Helm
-	helmEnv := append(os.Environ(), fmt.Sprintf("HOME=%s", os.Getenv("HOME")))
+	// Nothing changed since the last apply, so only drift of the release from
+	// the applied state is a reason to touch it
+	healing := false
+	if vcluster.Status.AppliedHash == desiredHash {
+		drift, err := r.detectDrift(ctx, vcluster, chartVersion, desiredHash)
+		if err != nil {
+			logger.Error(err, "Failed to check the release for drift")
+			return err
+		}
+
+		if drift == nil {
+			meta.SetStatusCondition(&vcluster.Status.Conditions, metav1.Condition{
+				Type:    VirtualClusterConditionDrifted,
+				Status:  metav1.ConditionFalse,
+				Reason:  driftReasonInSync,
+				Message: "Helm release matches the desired state",
+			})
+			logger.Info("Release is in sync, skipping Helm operation", "release", releaseName)
+			return nil
+		}
+
+		logger.Info("Release drifted from the desired state", "release", releaseName, "reason", drift.Reason)
+		if !r.selfHeal(vcluster, class) {
+			r.reportDrift(vcluster, drift)
+			return nil
+		}
 
-	// Add the vCluster repo if not exists
-	addRepoCmd := exec.Command("helm", "repo", "add", "loft", vclusterRepo)
-	addRepoCmd.Env = helmEnv
-	if output, err := addRepoCmd.CombinedOutput(); err != nil {
-		logger.Error(err, "Failed to add Helm repo", "output", string(output))
-		// Continue anyway, just log the error
-		logger.Info("Continuing with Helm install despite repo add failure")
+		meta.SetStatusCondition(&vcluster.Status.Conditions, metav1.Condition{
+			Type:    VirtualClusterConditionDrifted,
+			Status:  metav1.ConditionTrue,
+			Reason:  drift.Reason,
+			Message: drift.Message,
+		})
+		healing = true
 	}
 
-	// Update the Helm repos
-	updateRepoCmd := exec.Command("helm", "repo", "update")
-	updateRepoCmd.Env = helmEnv
-	if output, err := updateRepoCmd.CombinedOutput(); err != nil {
-		logger.Error(err, "Failed to update Helm repos", "output", string(output))
-		// Continue anyway, just log the error
-		logger.Info("Continuing with Helm install despite repo update failure")
+	// Don't retry an upgrade that was rolled back before
+	if exists && vcluster.Status.FailedHash == desiredHash {
+		logger.Info("Not retrying the upgrade that was rolled back", "release", releaseName, "hash", desiredHash)
+		return &upgradeRolledBackError{hash: desiredHash}
 	}
 
 	if exists {
 		logger.Info("Upgrading the release", "release", releaseName)
 		// Upgrade the release
-		cmd = exec.Command(
-			"helm", "upgrade",
-			releaseName,
-			fmt.Sprintf("loft/%s", vclusterChart),
-			"--version", chartVersion,
-			"--namespace", namespace,
-			"--values", valuesFile,
-		)
+		_, err = r.Helm.Upgrade(ctx, req)
 	} else {
 		logger.Info("Installing the release", "release", releaseName)
-		// Install the release
-		cmd = exec.Command(
-			"helm", "install",
-			releaseName,
-			fmt.Sprintf("loft/%s", vclusterChart),
-			"--version", chartVersion,
-			"--namespace", namespace,
-			"--create-namespace",
-			"--values", valuesFile,
-		)
-	}
-
-	// Set environment variables
-	cmd.Env = helmEnv
-
-	// Execute the command
-	output, err := cmd.CombinedOutput()
+		// Install the release. The target namespace is created by the
+		// operator, so it carries the labels tracking its ownership.
+		_, err = r.Helm.Install(ctx, req)
+	}
 	if err != nil {
-		logger.Error(err, "Failed to execute Helm command", "output", string(output))
-		return fmt.Errorf("failed to execute Helm command: %v, output: %s", err, string(output))
+		logger.Error(err, "Failed to execute Helm operation")
+
+		policy := r.upgradePolicy(vcluster, class)
+		switch {
+		case exists && policy.Atomic:
+			// Atomic upgrades are rolled back by Helm itself
+			if revision, rolledBack := r.atomicRollbackRevision(ctx, vcluster); rolledBack {
+				logger.Info("Helm rolled back the failed atomic upgrade", "revision", revision)
+				vcluster.Status.FailedHash = desiredHash
+				r.Recorder.Event(vcluster, corev1.EventTypeWarning, "UpgradeRolledBack",
+					fmt.Sprintf("Atomic upgrade failed, Helm rolled the release back as revision %d", revision))
+			}
+		case exists && policy.RollbackOnFailure:
+			revision, rollbackErr := r.rollbackFailedUpgrade(ctx, vcluster, class)
+			if rollbackErr != nil {
+				logger.Error(rollbackErr, "Failed to roll back the failed upgrade")
+				err = fmt.Errorf("%w; rollback failed: %v", err, rollbackErr)
+			} else {
+				logger.Info("Rolled back the failed upgrade", "revision", revision)
+				vcluster.Status.FailedHash = desiredHash
+				r.Recorder.Event(vcluster, corev1.EventTypeWarning, "UpgradeRolledBack",
+					fmt.Sprintf("Upgrade failed, Helm release was rolled back to revision %d", revision))
+				err = fmt.Errorf("%w; rolled back to revision %d", err, revision)
+			}
+		}
+
+		r.recordHistory(ctx, vcluster, class)
+		return err
+	}
+	vcluster.Status.AppliedHash = desiredHash
+	vcluster.Status.FailedHash = ""
+	r.recordHistory(ctx, vcluster, class)
+
+	if healing {
+		meta.SetStatusCondition(&vcluster.Status.Conditions, metav1.Condition{
+			Type:    VirtualClusterConditionDrifted,
+			Status:  metav1.ConditionFalse,
+			Reason:  driftReasonSelfHealed,
+			Message: "Desired state was re-applied to the drifted Helm release",
+		})
+		r.Recorder.Event(vcluster, corev1.EventTypeNormal, "DriftCorrected",
+			"Desired state was re-applied to the drifted Helm release")
+	} else {
+		meta.SetStatusCondition(&vcluster.Status.Conditions, metav1.Condition{
+			Type:    VirtualClusterConditionDrifted,
+			Status:  metav1.ConditionFalse,
+			Reason:  driftReasonInSync,
+			Message: "Helm release matches the desired state",
+		})
 	}
 
-	logger.Info("Successfully executed Helm command", "output", string(output))
+	logger.Info("Successfully executed Helm operation", "release", releaseName)
 	return nil
 }
 
@@ -517,8 +922,13 @@ func (r *VirtualClusterReconciler) installOrUpgradeVCluster(ctx context.Context,
 func (r *VirtualClusterReconciler) ensureSchemaConfigMap(ctx context.Context, vcluster *corev1alpha1.VirtualCluster, version string) (string, error) {
 	logger := log.FromContext(ctx)
 
+	// Schemas are published for chart versions, not for constraints
+	if _, err := semver.NewVersion(version); err != nil {
+		return "", fmt.Errorf("no schema is published for chart version constraint %s", version)
+	}
+
 	// Define ConfigMap name based on version
-	configMapName := fmt.Sprintf("vcluster-schema-%s", strings.ReplaceAll(version, ".", "-"))
+	configMapName := schema.ConfigMapName(version)
 	configMapNamespace := vcluster.Namespace
 
 	// Check if ConfigMap already exists
@@ -526,7 +936,7 @@ func (r *VirtualClusterReconciler) ensureSchemaConfigMap(ctx context.Context, vc
 	err := r.Get(ctx, client.ObjectKey{Namespace: configMapNamespace, Name: configMapName}, configMap)
 	if err == nil {
 		logger.Info("Schema ConfigMap already exists", "name", configMapName, "namespace", configMapNamespace)
-		return configMap.Data["values.schema.json"], nil
+		return configMap.Data[schema.Key], nil
 	}
 
 	if !errors.IsNotFound(err) {
@@ -577,7 +987,7 @@ func (r *VirtualClusterReconciler) ensureSchemaConfigMap(ctx context.Context, vc
 			},
 		},
 		Data: map[string]string{
-			"values.schema.json": string(schemaContent),
+			schema.Key: string(schemaContent),
 		},
 	}
 
@@ -602,96 +1012,21 @@ func (r *VirtualClusterReconciler) ensureSchemaConfigMap(ctx context.Context, vc
 }
 
 // validateValuesAgainstSchema validates the values against the schema
-func (r *VirtualClusterReconciler) validateValuesAgainstSchema(ctx context.Context, vcluster *corev1alpha1.VirtualCluster, schemaData string) error {
+func (r *VirtualClusterReconciler) validateValuesAgainstSchema(ctx context.Context, vcluster *corev1alpha1.VirtualCluster, class *corev1alpha1.VirtualClusterClass, schemaData string) error {
 	logger := log.FromContext(ctx)
 	logger.Info("Validating values against schema")
 
-	// Get the values
-	values, err := vcluster.GetValues()
+	// Get the values translated for the chart version
+	transformedValues, err := r.translateValues(ctx, vcluster, class)
 	if err != nil {
 		logger.Error(err, "Failed to get values from VirtualCluster")
 		return err
 	}
 
-	// Transform values to vCluster format according to schema
-	transformedValues := make(map[string]interface{})
-
-	// Map 'vcluster' section to appropriate controlPlane fields
-	if vclusterConfig, ok := values["vcluster"].(map[string]interface{}); ok {
-		// In schema, the K3s/K8s image is defined within controlPlane
-		controlPlane := make(map[string]interface{})
-
-		// Set the image if specified
-		if image, ok := vclusterConfig["image"].(string); ok {
-			controlPlane["image"] = image
-		}
-
-		transformedValues["controlPlane"] = controlPlane
-	}
-
-	// Map 'storage' (persistence) section to correct fields according to schema
-	if storage, ok := values["storage"].(map[string]interface{}); ok {
-		if persistence, ok := storage["persistence"].(bool); ok {
-			// Set storage-related fields
-			if !persistence {
-				// If persistence is false, we need to disable it in the vcluster chart
-				transformedValues["networking"] = map[string]interface{}{
-					"podCIDR":         "10.42.0.0/16",
-					"serviceCIDR":     "10.43.0.0/16",
-					"isolation":       "enabled",
-					"cloudController": false,
-				}
-			}
-		}
-	}
-
-	// Map 'telemetry' section to correct schema field
-	if telemetry, ok := values["telemetry"].(map[string]interface{}); ok {
-		telemetryConfig := make(map[string]interface{})
-		if disabled, ok := telemetry["disabled"].(bool); ok {
-			telemetryConfig["enabled"] = !disabled
-		}
-		transformedValues["telemetry"] = telemetryConfig
-	}
-
-	// Copy any other fields that match the schema
-	validRootFields := []string{
-		"controlPlane", "experimental", "exportKubeConfig", "external",
-		"global", "integrations", "networking", "plugin", "plugins",
-		"policies", "pro", "rbac", "serviceCIDR", "sleepMode", "sync", "telemetry",
-	}
-
-	for _, field := range validRootFields {
-		if v, ok := values[field]; ok {
-			transformedValues[field] = v
-		}
-	}
-
-	// Convert to JSON for validation
-	transformedJSON, err := json.Marshal(transformedValues)
-	if err != nil {
-		logger.Error(err, "Failed to marshal transformed values to JSON")
+	if err := schema.Validate(schemaData, transformedValues); err != nil {
 		return err
 	}
 
-	// Create schema and document loaders
-	schemaLoader := gojsonschema.NewStringLoader(schemaData)
-	documentLoader := gojsonschema.NewBytesLoader(transformedJSON)
-
-	// Validate
-	result, err := gojsonschema.Validate(schemaLoader, documentLoader)
-	if err != nil {
-		return fmt.Errorf("validation failed: %w", err)
-	}
-
-	if !result.Valid() {
-		var errors []string
-		for _, desc := range result.Errors() {
-			errors = append(errors, desc.String())
-		}
-		return fmt.Errorf("schema validation errors: %s", strings.Join(errors, "; "))
-	}
-
 	logger.Info("Values successfully validated against schema")
 	return nil
 }
@@ -700,80 +1035,119 @@ func (r *VirtualClusterReconciler) validateValuesAgainstSchema(ctx context.Conte
 func (r *VirtualClusterReconciler) helmReleaseExists(ctx context.Context, vcluster *corev1alpha1.VirtualCluster) (bool, error) {
 	logger := log.FromContext(ctx)
 
-	// Use helm list command to check if release exists
-	cmd := exec.Command(
-		"helm", "list",
-		"--namespace", vcluster.Namespace,
-		"--filter", vcluster.Name,
-		"--output", "json",
-	)
-
-	output, err := cmd.CombinedOutput()
+	exists, err := r.Helm.Exists(ctx, vcluster.ReleaseNamespace(), vcluster.Name)
 	if err != nil {
-		logger.Error(err, "Failed to list Helm releases", "output", string(output))
-		return false, err
-	}
-
-	logger.Info("Helm list output", "output", string(output))
-
-	// Parse the output
-	var releases []map[string]interface{}
-	if err := json.Unmarshal(output, &releases); err != nil {
-		logger.Error(err, "Failed to parse Helm list output")
+		logger.Error(err, "Failed to list Helm releases")
 		return false, err
 	}
 
-	// Check if our release is in the list
-	for _, release := range releases {
-		name, ok := release["name"].(string)
-		if ok && name == vcluster.Name {
-			return true, nil
-		}
-	}
-
-	return false, nil
+	logger.Info("Checked for Helm release", "release", vcluster.Name, "exists", exists)
+	return exists, nil
 }
 
 // finalizeVirtualCluster handles deletion of the VirtualCluster resource
 func (r *VirtualClusterReconciler) finalizeVirtualCluster(ctx context.Context, vcluster *corev1alpha1.VirtualCluster) error {
 	logger := log.FromContext(ctx)
-	logger.Info("Finalizing VirtualCluster", "namespace", vcluster.Namespace, "name", vcluster.Name)
+	policy := recordedDeletionPolicy(vcluster)
+	logger.Info("Finalizing VirtualCluster", "namespace", vcluster.Namespace, "name", vcluster.Name, "deletionPolicy", policy)
+	r.requestCounts.Delete(vcluster.UID)
+
+	// Orphaned resources are left as they are
+	if policy == corev1alpha1.DeletionPolicyOrphan {
+		logger.Info("Orphaning Helm release", "release", vcluster.Name)
+		return nil
+	}
 
-	// Use helm uninstall to delete the release
-	cmd := exec.Command(
-		"helm", "uninstall",
-		vcluster.Name,
-		"--namespace", vcluster.Namespace,
-	)
+	// Secrets in other namespaces aren't garbage collected with the
+	// VirtualCluster
+	if err := r.deleteKubeconfigCopies(ctx, vcluster, nil); err != nil {
+		logger.Error(err, "Failed to delete kubeconfig Secrets")
+		return err
+	}
 
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		// If the error indicates that the release is not found, we can consider it already deleted
-		if strings.Contains(string(output), "not found") {
-			logger.Info("Helm release already deleted", "output", string(output))
-			return nil
-		}
-		logger.Error(err, "Failed to uninstall Helm release", "output", string(output))
+	// Neither are the isolation resources in a target namespace
+	if err := r.deleteIsolation(ctx, vcluster); err != nil {
+		logger.Error(err, "Failed to delete isolation resources")
 		return err
 	}
 
-	// Delete the values file if it exists
-	valuesFilePath := filepath.Join(os.TempDir(), fmt.Sprintf("values-%s-%s.yaml", vcluster.Name, vcluster.Namespace))
-	if _, err := os.Stat(valuesFilePath); err == nil {
-		if err := os.Remove(valuesFilePath); err != nil {
-			logger.Error(err, "Failed to delete values file", "path", valuesFilePath)
-			// Don't return error as this is not critical
+	// Uninstall the Helm release
+	if err := r.Helm.Uninstall(ctx, vcluster.ReleaseNamespace(), vcluster.Name); err != nil {
+		// If the release is not found, we can consider it already deleted
+		if !helm.IsReleaseNotFound(err) {
+			logger.Error(err, "Failed to uninstall Helm release")
+			return err
 		}
+		logger.Info("Helm release already deleted", "release", vcluster.Name)
 	}
 
-	logger.Info("Successfully uninstalled Helm release", "output", string(output))
+	// Helm keeps the PVCs of StatefulSets and the namespace
+	if policy == corev1alpha1.DeletionPolicyDelete {
+		if err := r.deleteReleaseData(ctx, vcluster); err != nil {
+			logger.Error(err, "Failed to delete the data of the VirtualCluster")
+			return err
+		}
+	}
+
+	logger.Info("Successfully finalized VirtualCluster", "release", vcluster.Name)
 	return nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *VirtualClusterReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	ctx := context.Background()
+	indexer := mgr.GetFieldIndexer()
+	if err := indexer.IndexField(ctx, &corev1alpha1.VirtualCluster{}, valuesFromConfigMapIndex, valuesFromIndexer("ConfigMap")); err != nil {
+		return err
+	}
+	if err := indexer.IndexField(ctx, &corev1alpha1.VirtualCluster{}, valuesFromSecretIndex, valuesFromIndexer("Secret")); err != nil {
+		return err
+	}
+	if err := indexer.IndexField(ctx, &corev1alpha1.VirtualCluster{}, classRefIndex, classRefIndexer); err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&corev1alpha1.VirtualCluster{}).
+		// Re-reconcile when values referenced by valuesFrom change
+		Watches(&corev1.ConfigMap{},
+			handler.EnqueueRequestsFromMapFunc(r.requestsForValuesObject(valuesFromConfigMapIndex)),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{})).
+		Watches(&corev1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(r.requestsForValuesObject(valuesFromSecretIndex)),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{})).
+		// Re-reconcile when the class of a VirtualCluster changes
+		Watches(&corev1alpha1.VirtualClusterClass{},
+			handler.EnqueueRequestsFromMapFunc(r.requestsForClass),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		// Re-export the kubeconfig when the vcluster syncer rewrites it
+		Watches(&corev1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(r.requestsForKubeconfigSecret),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{})).
+		// Re-reconcile when the control plane of a release changes. Helm doesn't
+		// set owner references, so the objects are mapped by their release
+		// annotations.
+		Watches(&appsv1.StatefulSet{},
+			handler.EnqueueRequestsFromMapFunc(r.requestsForReleaseObject),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{})).
+		Watches(&appsv1.Deployment{},
+			handler.EnqueueRequestsFromMapFunc(r.requestsForReleaseObject),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{})).
+		Watches(&corev1.Service{},
+			handler.EnqueueRequestsFromMapFunc(r.requestsForReleaseObject),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{})).
+		// Revert changes to the isolation resources and report the usage of
+		// the quota. Resources in a target namespace aren't owned by the
+		// VirtualCluster, so all of them are mapped by their labels.
+		Watches(&corev1.ResourceQuota{},
+			handler.EnqueueRequestsFromMapFunc(r.requestsForIsolationObject),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{})).
+		Watches(&corev1.LimitRange{},
+			handler.EnqueueRequestsFromMapFunc(r.requestsForIsolationObject),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{})).
+		Watches(&networkingv1.NetworkPolicy{},
+			handler.EnqueueRequestsFromMapFunc(r.requestsForIsolationObject),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{})).
 		Named("virtualcluster").
 		Complete(r)
 }
@@ -18,7 +18,6 @@ package controller
 
 import (
 	"context"
-	"os"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -31,6 +30,7 @@ import (
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/yaml"
 
 	corev1alpha1 "github.com/OpenVirtualCluster/openvirtualcluster-operator/api/v1alpha1"
 )
@@ -159,8 +159,8 @@ var _ = Describe("VirtualCluster Controller", func() {
 		})
 	})
 
-	Context("Values File Generation", func() {
-		It("should create a valid values file", func() {
+	Context("Values Generation", func() {
+		It("should generate valid values", func() {
 			vc := &corev1alpha1.VirtualCluster{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "test-values",
@@ -180,22 +180,16 @@ var _ = Describe("VirtualCluster Controller", func() {
 				Recorder: record.NewFakeRecorder(10),
 			}
 
-			// Test createValuesFile function
-			valuesFile, err := reconciler.createValuesFile(ctx, vc)
+			// Test translateValues function
+			values, err := reconciler.translateValues(ctx, vc, nil)
 			Expect(err).NotTo(HaveOccurred())
-			Expect(valuesFile).NotTo(BeEmpty())
-
-			// Verify file exists
-			_, err = os.Stat(valuesFile)
-			Expect(err).NotTo(HaveOccurred())
-
-			// Clean up
-			defer os.Remove(valuesFile)
+			Expect(values).NotTo(BeEmpty())
 
 			// Check content
-			content, err := os.ReadFile(valuesFile)
+			content, err := yaml.Marshal(values)
 			Expect(err).NotTo(HaveOccurred())
-			Expect(string(content)).To(ContainSubstring("rancher/k3s:v1.25.0-k3s1"))
+			Expect(string(content)).To(ContainSubstring("repository: rancher/k3s"))
+			Expect(string(content)).To(ContainSubstring("tag: v1.25.0-k3s1"))
 		})
 	})
 
@@ -0,0 +1,477 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	corev1alpha1 "github.com/OpenVirtualCluster/openvirtualcluster-operator/api/v1alpha1"
+)
+
+const (
+	// poolLabel holds the name of the pool a VirtualCluster belongs to
+	poolLabel = "core.openvc.dev/pool"
+
+	// claimLabel holds the name of the claim a VirtualCluster is bound to
+	claimLabel = "core.openvc.dev/claim"
+
+	// claimUIDAnnotation holds the UID of the claim a VirtualCluster is bound
+	// to, so a new claim of the same name doesn't inherit it
+	claimUIDAnnotation = "core.openvc.dev/claim-uid"
+
+	// claimPoolIndex is the field index of the pool referenced by a claim
+	claimPoolIndex = ".spec.poolRef.name"
+)
+
+// VirtualClusterPoolReconciler reconciles a VirtualClusterPool object. It
+// keeps the VirtualClusters of the pool ready and binds them to the claims
+// referencing the pool. Binding only happens here, so a VirtualCluster is
+// never bound twice.
+type VirtualClusterPoolReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=core.openvc.dev,resources=virtualclusterpools,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core.openvc.dev,resources=virtualclusterpools/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=core.openvc.dev,resources=virtualclusterpools/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core.openvc.dev,resources=virtualclusterclaims,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core.openvc.dev,resources=virtualclusterclaims/status,verbs=get;update;patch
+
+// Reconcile releases the VirtualClusters of deleted claims, binds pending
+// claims and creates or deletes VirtualClusters to keep spec.replicas of
+// them available.
+func (r *VirtualClusterPoolReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	logger.Info("Reconciling VirtualClusterPool", "namespace", req.Namespace, "name", req.Name)
+
+	claims, err := r.claimsOf(ctx, req.Namespace, req.Name)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	pool := &corev1alpha1.VirtualClusterPool{}
+	if err := r.Get(ctx, req.NamespacedName, pool); err != nil {
+		if errors.IsNotFound(err) {
+			// Claims of a missing pool wait for it to be created, bound ones
+			// lost their VirtualCluster along with the pool
+			for _, claim := range claims {
+				phase := corev1alpha1.VirtualClusterClaimPending
+				message := fmt.Sprintf("VirtualClusterPool %s not found", req.Name)
+				if claim.Status.Phase == corev1alpha1.VirtualClusterClaimBound || claim.Status.Phase == corev1alpha1.VirtualClusterClaimLost {
+					phase = corev1alpha1.VirtualClusterClaimLost
+					message = fmt.Sprintf("VirtualClusterPool %s was deleted", req.Name)
+				}
+				if err := r.updateClaimStatus(ctx, claim, phase, nil, message); err != nil {
+					return ctrl.Result{}, err
+				}
+			}
+			return ctrl.Result{}, nil
+		}
+		logger.Error(err, "Failed to get VirtualClusterPool")
+		return ctrl.Result{}, err
+	}
+	if !pool.DeletionTimestamp.IsZero() {
+		// The VirtualClusters are deleted along with the pool that owns them
+		return ctrl.Result{}, nil
+	}
+
+	vclusters := &corev1alpha1.VirtualClusterList{}
+	if err := r.List(ctx, vclusters, client.InNamespace(pool.Namespace), client.MatchingLabels{poolLabel: pool.Name}); err != nil {
+		logger.Error(err, "Failed to list VirtualClusters of the pool")
+		return ctrl.Result{}, err
+	}
+
+	claimsByUID := make(map[types.UID]*corev1alpha1.VirtualClusterClaim, len(claims))
+	for _, claim := range claims {
+		claimsByUID[claim.UID] = claim
+	}
+
+	// Sort the VirtualClusters into bound, released and available ones
+	hash := templateHash(&pool.Spec.Template)
+	var (
+		total     int32
+		available []*corev1alpha1.VirtualCluster
+		released  []*corev1alpha1.VirtualCluster
+	)
+	bound := map[types.UID]*corev1alpha1.VirtualCluster{}
+	existing := map[string]bool{}
+	for i := range vclusters.Items {
+		vcluster := &vclusters.Items[i]
+		if !metav1.IsControlledBy(vcluster, pool) || !vcluster.DeletionTimestamp.IsZero() {
+			continue
+		}
+		total++
+		existing[vcluster.Name] = true
+
+		if vcluster.Labels[claimLabel] == "" {
+			available = append(available, vcluster)
+			continue
+		}
+		claim, ok := claimsByUID[types.UID(vcluster.Annotations[claimUIDAnnotation])]
+		if !ok {
+			released = append(released, vcluster)
+			continue
+		}
+		// A claim bound twice from a stale cache keeps the VirtualCluster in
+		// its status
+		if other, ok := bound[claim.UID]; ok {
+			if claim.Status.VirtualClusterName == vcluster.Name {
+				vcluster, other = other, vcluster
+			}
+			bound[claim.UID] = other
+			released = append(released, vcluster)
+			continue
+		}
+		bound[claim.UID] = vcluster
+	}
+
+	for _, vcluster := range released {
+		recycled, err := r.releaseVirtualCluster(ctx, pool, vcluster)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if recycled {
+			available = append(available, vcluster)
+		} else {
+			total--
+		}
+	}
+
+	// Bind the pending claims, oldest first, preferring VirtualClusters of
+	// the current template
+	sort.SliceStable(available, func(i, j int) bool {
+		return available[i].Annotations[templateHashAnnotation] == hash && available[j].Annotations[templateHashAnnotation] != hash
+	})
+	var (
+		pending int32
+		result  ctrl.Result
+	)
+	for _, claim := range claims {
+		if vcluster, ok := bound[claim.UID]; ok {
+			if err := r.updateClaimStatus(ctx, claim, corev1alpha1.VirtualClusterClaimBound, vcluster, ""); err != nil {
+				return ctrl.Result{}, err
+			}
+			continue
+		}
+		if claim.Status.Phase == corev1alpha1.VirtualClusterClaimBound && existing[claim.Status.VirtualClusterName] {
+			// The cache doesn't show the binding of the VirtualCluster yet
+			result.Requeue = true
+			continue
+		}
+		if claim.Status.Phase == corev1alpha1.VirtualClusterClaimBound || claim.Status.Phase == corev1alpha1.VirtualClusterClaimLost {
+			// The VirtualCluster of the claim was deleted, it's never rebound
+			message := fmt.Sprintf("VirtualCluster %s was deleted", claim.Status.VirtualClusterName)
+			if claim.Status.Phase == corev1alpha1.VirtualClusterClaimBound {
+				r.Recorder.Event(claim, corev1.EventTypeWarning, "Lost", message)
+			}
+			if err := r.updateClaimStatus(ctx, claim, corev1alpha1.VirtualClusterClaimLost, nil, message); err != nil {
+				return ctrl.Result{}, err
+			}
+			continue
+		}
+
+		i := indexOfReady(available)
+		if i < 0 {
+			pending++
+			message := fmt.Sprintf("Waiting for a ready VirtualCluster of pool %s", pool.Name)
+			if err := r.updateClaimStatus(ctx, claim, corev1alpha1.VirtualClusterClaimPending, nil, message); err != nil {
+				return ctrl.Result{}, err
+			}
+			continue
+		}
+		vcluster := available[i]
+		if err := r.bindVirtualCluster(ctx, claim, vcluster); err != nil {
+			return ctrl.Result{}, err
+		}
+		available = append(available[:i], available[i+1:]...)
+		bound[claim.UID] = vcluster
+	}
+
+	// Keep spec.replicas VirtualClusters of the current template available,
+	// plus one for each pending claim
+	if err := r.scale(ctx, pool, available, hash, pool.Spec.Replicas+pending); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// VirtualClusters created or deleted by scaling are counted once the
+	// watch reports them
+	var ready int32
+	for _, vcluster := range available {
+		if isReadyForClaim(vcluster) {
+			ready++
+		}
+	}
+
+	status := corev1alpha1.VirtualClusterPoolStatus{
+		ObservedGeneration: pool.Generation,
+		Replicas:           total,
+		ReadyReplicas:      ready,
+		ClaimedReplicas:    int32(len(bound)),
+		PendingClaims:      pending,
+	}
+	if !equality.Semantic.DeepEqual(pool.Status, status) {
+		pool.Status = status
+		if err := r.Status().Update(ctx, pool); err != nil {
+			logger.Error(err, "Failed to update VirtualClusterPool status")
+			return ctrl.Result{}, err
+		}
+	}
+	return result, nil
+}
+
+// claimsOf returns the claims referencing the pool that aren't being deleted,
+// oldest first.
+func (r *VirtualClusterPoolReconciler) claimsOf(ctx context.Context, namespace, pool string) ([]*corev1alpha1.VirtualClusterClaim, error) {
+	list := &corev1alpha1.VirtualClusterClaimList{}
+	if err := r.List(ctx, list, client.InNamespace(namespace), client.MatchingFields{claimPoolIndex: pool}); err != nil {
+		return nil, fmt.Errorf("failed to list claims of pool %s: %w", pool, err)
+	}
+
+	claims := make([]*corev1alpha1.VirtualClusterClaim, 0, len(list.Items))
+	for i := range list.Items {
+		if list.Items[i].DeletionTimestamp.IsZero() {
+			claims = append(claims, &list.Items[i])
+		}
+	}
+	sort.SliceStable(claims, func(i, j int) bool {
+		if !claims[i].CreationTimestamp.Equal(&claims[j].CreationTimestamp) {
+			return claims[i].CreationTimestamp.Before(&claims[j].CreationTimestamp)
+		}
+		return claims[i].Name < claims[j].Name
+	})
+	return claims, nil
+}
+
+// bindVirtualCluster labels the VirtualCluster for the claim and reports it in
+// the status of the claim.
+func (r *VirtualClusterPoolReconciler) bindVirtualCluster(ctx context.Context, claim *corev1alpha1.VirtualClusterClaim, vcluster *corev1alpha1.VirtualCluster) error {
+	vcluster.Labels[claimLabel] = claim.Name
+	if vcluster.Annotations == nil {
+		vcluster.Annotations = map[string]string{}
+	}
+	vcluster.Annotations[claimUIDAnnotation] = string(claim.UID)
+	if err := r.Update(ctx, vcluster); err != nil {
+		return fmt.Errorf("failed to bind VirtualCluster %s: %w", vcluster.Name, err)
+	}
+
+	log.FromContext(ctx).Info("Bound VirtualCluster to claim", "virtualCluster", vcluster.Name, "claim", claim.Name)
+	r.Recorder.Event(claim, corev1.EventTypeNormal, "Bound", fmt.Sprintf("Bound to VirtualCluster %s", vcluster.Name))
+	return r.updateClaimStatus(ctx, claim, corev1alpha1.VirtualClusterClaimBound, vcluster, "")
+}
+
+// releaseVirtualCluster deletes the VirtualCluster of a deleted claim or
+// returns it to the pool, depending on the release policy. It reports
+// whether the VirtualCluster was returned.
+func (r *VirtualClusterPoolReconciler) releaseVirtualCluster(ctx context.Context, pool *corev1alpha1.VirtualClusterPool, vcluster *corev1alpha1.VirtualCluster) (bool, error) {
+	logger := log.FromContext(ctx)
+
+	if pool.Spec.ReleasePolicy == corev1alpha1.PoolReleasePolicyRecycle {
+		delete(vcluster.Labels, claimLabel)
+		delete(vcluster.Annotations, claimUIDAnnotation)
+		if err := r.Update(ctx, vcluster); err != nil {
+			return false, fmt.Errorf("failed to recycle VirtualCluster %s: %w", vcluster.Name, err)
+		}
+		logger.Info("Recycled released VirtualCluster", "virtualCluster", vcluster.Name)
+		r.Recorder.Event(pool, corev1.EventTypeNormal, "Recycled", fmt.Sprintf("Returned released VirtualCluster %s to the pool", vcluster.Name))
+		return true, nil
+	}
+
+	if err := r.Delete(ctx, vcluster); client.IgnoreNotFound(err) != nil {
+		return false, fmt.Errorf("failed to delete released VirtualCluster %s: %w", vcluster.Name, err)
+	}
+	logger.Info("Deleted released VirtualCluster", "virtualCluster", vcluster.Name)
+	r.Recorder.Event(pool, corev1.EventTypeNormal, "Deleted", fmt.Sprintf("Deleted released VirtualCluster %s", vcluster.Name))
+	return false, nil
+}
+
+// scale creates VirtualClusters until desired ones of the current template
+// are available and deletes the surplus. VirtualClusters of a previous
+// template are only deleted while enough current ones are ready, so changing
+// the template doesn't drain the pool.
+func (r *VirtualClusterPoolReconciler) scale(ctx context.Context, pool *corev1alpha1.VirtualClusterPool, available []*corev1alpha1.VirtualCluster, hash string, desired int32) error {
+	var current, outdated []*corev1alpha1.VirtualCluster
+	var ready int32
+	for _, vcluster := range available {
+		if vcluster.Annotations[templateHashAnnotation] == hash {
+			current = append(current, vcluster)
+			if isReadyForClaim(vcluster) {
+				ready++
+			}
+		} else {
+			outdated = append(outdated, vcluster)
+		}
+	}
+
+	for i := int32(len(current)); i < desired; i++ {
+		if err := r.createVirtualCluster(ctx, pool, hash); err != nil {
+			return err
+		}
+	}
+
+	// Delete the VirtualClusters that aren't ready first
+	var surplus []*corev1alpha1.VirtualCluster
+	sort.SliceStable(current, func(i, j int) bool {
+		return !isReadyForClaim(current[i]) && isReadyForClaim(current[j])
+	})
+	if n := int32(len(current)) - desired; n > 0 {
+		surplus = append(surplus, current[:n]...)
+	}
+	keep := desired - ready
+	for _, vcluster := range outdated {
+		if keep > 0 && isReadyForClaim(vcluster) {
+			keep--
+			continue
+		}
+		surplus = append(surplus, vcluster)
+	}
+
+	for _, vcluster := range surplus {
+		if err := r.Delete(ctx, vcluster); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete VirtualCluster %s: %w", vcluster.Name, err)
+		}
+		log.FromContext(ctx).Info("Deleted surplus VirtualCluster", "virtualCluster", vcluster.Name)
+		r.Recorder.Event(pool, corev1.EventTypeNormal, "Deleted", fmt.Sprintf("Deleted VirtualCluster %s", vcluster.Name))
+	}
+	return nil
+}
+
+// createVirtualCluster creates a VirtualCluster from the template of the pool.
+func (r *VirtualClusterPoolReconciler) createVirtualCluster(ctx context.Context, pool *corev1alpha1.VirtualClusterPool, hash string) error {
+	vcluster := &corev1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: pool.Name + "-",
+			Namespace:    pool.Namespace,
+		},
+	}
+	applyTemplate(vcluster, &pool.Spec.Template, hash)
+	vcluster.Labels[poolLabel] = pool.Name
+
+	if err := ctrl.SetControllerReference(pool, vcluster, r.Scheme); err != nil {
+		return err
+	}
+	if err := r.Create(ctx, vcluster); err != nil {
+		return fmt.Errorf("failed to create VirtualCluster for pool %s: %w", pool.Name, err)
+	}
+
+	log.FromContext(ctx).Info("Created VirtualCluster", "virtualCluster", vcluster.Name)
+	r.Recorder.Event(pool, corev1.EventTypeNormal, "Created", fmt.Sprintf("Created VirtualCluster %s", vcluster.Name))
+	return nil
+}
+
+// updateClaimStatus persists the phase of the claim and the VirtualCluster it
+// is bound to, if they changed.
+func (r *VirtualClusterPoolReconciler) updateClaimStatus(ctx context.Context, claim *corev1alpha1.VirtualClusterClaim, phase corev1alpha1.VirtualClusterClaimPhase, vcluster *corev1alpha1.VirtualCluster, message string) error {
+	status := claim.Status.DeepCopy()
+	status.Phase = phase
+	status.Message = message
+	if vcluster != nil {
+		status.VirtualClusterName = vcluster.Name
+		status.KubeconfigSecretRef = vcluster.Status.KubeconfigSecretRef.DeepCopy()
+		if status.BoundTime == nil {
+			now := metav1.Now()
+			status.BoundTime = &now
+		}
+	} else {
+		status.KubeconfigSecretRef = nil
+	}
+	if equality.Semantic.DeepEqual(&claim.Status, status) {
+		return nil
+	}
+
+	claim.Status = *status
+	if err := r.Status().Update(ctx, claim); err != nil && !errors.IsNotFound(err) {
+		log.FromContext(ctx).Error(err, "Failed to update VirtualClusterClaim status", "claim", claim.Name)
+		return err
+	}
+	return nil
+}
+
+// isReadyForClaim returns true if the VirtualCluster is running and its
+// kubeconfig is exported.
+func isReadyForClaim(vcluster *corev1alpha1.VirtualCluster) bool {
+	return vcluster.Status.Phase == corev1alpha1.VirtualClusterRunning &&
+		vcluster.Status.KubeconfigSecretRef != nil &&
+		!vcluster.Spec.Suspend
+}
+
+// indexOfReady returns the index of the first VirtualCluster ready for a
+// claim, or -1.
+func indexOfReady(vclusters []*corev1alpha1.VirtualCluster) int {
+	for i, vcluster := range vclusters {
+		if isReadyForClaim(vcluster) {
+			return i
+		}
+	}
+	return -1
+}
+
+// claimPoolIndexer indexes claims by the name of their pool.
+func claimPoolIndexer(obj client.Object) []string {
+	claim, ok := obj.(*corev1alpha1.VirtualClusterClaim)
+	if !ok {
+		return nil
+	}
+	return []string{claim.Spec.PoolRef.Name}
+}
+
+// requestsForClaim maps a claim to its pool.
+func (r *VirtualClusterPoolReconciler) requestsForClaim(_ context.Context, obj client.Object) []reconcile.Request {
+	claim, ok := obj.(*corev1alpha1.VirtualClusterClaim)
+	if !ok {
+		return nil
+	}
+	return []reconcile.Request{{
+		NamespacedName: types.NamespacedName{Namespace: claim.Namespace, Name: claim.Spec.PoolRef.Name},
+	}}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *VirtualClusterPoolReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &corev1alpha1.VirtualClusterClaim{}, claimPoolIndex, claimPoolIndexer); err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&corev1alpha1.VirtualClusterPool{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		// Bind or replace VirtualClusters when they become ready or are deleted
+		Owns(&corev1alpha1.VirtualCluster{}).
+		// Bind new claims and release the VirtualClusters of deleted ones
+		Watches(&corev1alpha1.VirtualClusterClaim{},
+			handler.EnqueueRequestsFromMapFunc(r.requestsForClaim),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Named("virtualclusterpool").
+		Complete(r)
+}
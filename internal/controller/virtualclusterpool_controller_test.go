@@ -0,0 +1,280 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	corev1alpha1 "github.com/OpenVirtualCluster/openvirtualcluster-operator/api/v1alpha1"
+)
+
+var _ = Describe("VirtualClusterPool Controller", func() {
+	var (
+		ctx        context.Context
+		recorder   *record.FakeRecorder
+		reconciler *VirtualClusterPoolReconciler
+		pool       *corev1alpha1.VirtualClusterPool
+	)
+
+	key := types.NamespacedName{Name: "ci", Namespace: "default"}
+
+	newReconciler := func(objs ...client.Object) {
+		fakeClient, s := NewTestClient(objs...)
+		reconciler = &VirtualClusterPoolReconciler{Client: fakeClient, Scheme: s, Recorder: recorder}
+	}
+
+	reconcilePool := func() {
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	// member returns a VirtualCluster of the pool created from the template
+	// with the given hash
+	member := func(name, hash string, ready bool) *corev1alpha1.VirtualCluster {
+		vc := CreateTestVirtualCluster(name, key.Namespace, "")
+		vc.UID = types.UID(name + "-uid")
+		vc.Labels = map[string]string{poolLabel: pool.Name}
+		vc.Annotations = map[string]string{templateHashAnnotation: hash}
+		vc.OwnerReferences = []metav1.OwnerReference{{
+			APIVersion: corev1alpha1.GroupVersion.String(),
+			Kind:       "VirtualClusterPool",
+			Name:       pool.Name,
+			UID:        pool.UID,
+			Controller: ptr.To(true),
+		}}
+		if ready {
+			vc.Status.Phase = corev1alpha1.VirtualClusterRunning
+			vc.Status.KubeconfigSecretRef = &corev1alpha1.SecretKeyReference{Name: name + "-kubeconfig", Key: defaultKubeconfigKey}
+		}
+		return vc
+	}
+
+	newClaim := func(name string) *corev1alpha1.VirtualClusterClaim {
+		return &corev1alpha1.VirtualClusterClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: key.Namespace, UID: types.UID(name + "-uid")},
+			Spec:       corev1alpha1.VirtualClusterClaimSpec{PoolRef: corev1alpha1.PoolReference{Name: key.Name}},
+		}
+	}
+
+	getClaim := func(name string) *corev1alpha1.VirtualClusterClaim {
+		claim := &corev1alpha1.VirtualClusterClaim{}
+		Expect(reconciler.Get(ctx, client.ObjectKey{Namespace: key.Namespace, Name: name}, claim)).To(Succeed())
+		return claim
+	}
+
+	members := func() []corev1alpha1.VirtualCluster {
+		list := &corev1alpha1.VirtualClusterList{}
+		Expect(reconciler.List(ctx, list, client.MatchingLabels{poolLabel: key.Name})).To(Succeed())
+		return list.Items
+	}
+
+	getPool := func() *corev1alpha1.VirtualClusterPool {
+		updated := &corev1alpha1.VirtualClusterPool{}
+		Expect(reconciler.Get(ctx, key, updated)).To(Succeed())
+		return updated
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		recorder = record.NewFakeRecorder(20)
+
+		pool = &corev1alpha1.VirtualClusterPool{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace, UID: "ci-uid", Generation: 1},
+			Spec: corev1alpha1.VirtualClusterPoolSpec{
+				Replicas: 2,
+				Template: corev1alpha1.VirtualClusterTemplate{
+					Metadata: corev1alpha1.VirtualClusterTemplateMetadata{Labels: map[string]string{"team": "ci"}},
+					Spec: corev1alpha1.VirtualClusterSpec{
+						ClassRef: &corev1alpha1.ClassReference{Name: "small"},
+					},
+				},
+				ReleasePolicy: corev1alpha1.PoolReleasePolicyDelete,
+			},
+		}
+	})
+
+	It("should create the VirtualClusters from the template", func() {
+		newReconciler(pool)
+		reconcilePool()
+
+		vclusters := members()
+		Expect(vclusters).To(HaveLen(2))
+		for _, vc := range vclusters {
+			Expect(vc.Name).To(HavePrefix("ci-"))
+			Expect(vc.Labels).To(HaveKeyWithValue("team", "ci"))
+			Expect(vc.Annotations).To(HaveKeyWithValue(templateHashAnnotation, templateHash(&pool.Spec.Template)))
+			Expect(vc.Spec.ClassRef.Name).To(Equal("small"))
+			Expect(metav1.IsControlledBy(&vc, pool)).To(BeTrue())
+		}
+
+		// The created VirtualClusters satisfy the pool
+		reconcilePool()
+		Expect(members()).To(HaveLen(2))
+		Expect(getPool().Status.Replicas).To(Equal(int32(2)))
+	})
+
+	It("should bind a claim to a ready VirtualCluster and create a replacement", func() {
+		pool.Spec.Replicas = 1
+		hash := templateHash(&pool.Spec.Template)
+		newReconciler(pool, member("ci-ready", hash, true), newClaim("job-1"))
+		reconcilePool()
+
+		claim := getClaim("job-1")
+		Expect(claim.Status.Phase).To(Equal(corev1alpha1.VirtualClusterClaimBound))
+		Expect(claim.Status.VirtualClusterName).To(Equal("ci-ready"))
+		Expect(claim.Status.KubeconfigSecretRef).To(Equal(&corev1alpha1.SecretKeyReference{Name: "ci-ready-kubeconfig", Key: "config"}))
+		Expect(claim.Status.BoundTime).NotTo(BeNil())
+		Expect(recorder.Events).To(Receive(ContainSubstring("Bound to VirtualCluster ci-ready")))
+
+		vclusters := members()
+		Expect(vclusters).To(HaveLen(2))
+		for _, vc := range vclusters {
+			if vc.Name == "ci-ready" {
+				Expect(vc.Labels).To(HaveKeyWithValue(claimLabel, "job-1"))
+				Expect(vc.Annotations).To(HaveKeyWithValue(claimUIDAnnotation, "job-1-uid"))
+			} else {
+				Expect(vc.Labels).NotTo(HaveKey(claimLabel))
+			}
+		}
+		Expect(getPool().Status.ClaimedReplicas).To(Equal(int32(1)))
+	})
+
+	It("should keep a claim pending until a VirtualCluster is ready", func() {
+		pool.Spec.Replicas = 0
+		newReconciler(pool, newClaim("job-1"))
+		reconcilePool()
+
+		claim := getClaim("job-1")
+		Expect(claim.Status.Phase).To(Equal(corev1alpha1.VirtualClusterClaimPending))
+		Expect(claim.Status.Message).To(Equal("Waiting for a ready VirtualCluster of pool ci"))
+		Expect(getPool().Status.PendingClaims).To(Equal(int32(1)))
+
+		// A VirtualCluster is created for the pending claim
+		vclusters := members()
+		Expect(vclusters).To(HaveLen(1))
+
+		vc := &vclusters[0]
+		vc.Status.Phase = corev1alpha1.VirtualClusterRunning
+		vc.Status.KubeconfigSecretRef = &corev1alpha1.SecretKeyReference{Name: vc.Name + "-kubeconfig", Key: defaultKubeconfigKey}
+		Expect(reconciler.Status().Update(ctx, vc)).To(Succeed())
+		reconcilePool()
+
+		Expect(getClaim("job-1").Status.VirtualClusterName).To(Equal(vc.Name))
+		Expect(members()).To(HaveLen(1))
+	})
+
+	DescribeTable("releasing the VirtualCluster of a deleted claim",
+		func(policy corev1alpha1.PoolReleasePolicy, recycled bool) {
+			pool.Spec.Replicas = 1
+			pool.Spec.ReleasePolicy = policy
+			hash := templateHash(&pool.Spec.Template)
+			released := member("ci-released", hash, true)
+			released.Labels[claimLabel] = "job-1"
+			released.Annotations[claimUIDAnnotation] = "job-1-uid"
+			newReconciler(pool, released)
+			reconcilePool()
+
+			vclusters := members()
+			if recycled {
+				Expect(vclusters).To(HaveLen(1))
+				Expect(vclusters[0].Name).To(Equal("ci-released"))
+				Expect(vclusters[0].Labels).NotTo(HaveKey(claimLabel))
+				Expect(vclusters[0].Annotations).NotTo(HaveKey(claimUIDAnnotation))
+			} else {
+				Expect(vclusters).To(HaveLen(1))
+				Expect(vclusters[0].Name).NotTo(Equal("ci-released"))
+			}
+		},
+		Entry("deletes it with the Delete policy", corev1alpha1.PoolReleasePolicyDelete, false),
+		Entry("returns it to the pool with the Recycle policy", corev1alpha1.PoolReleasePolicyRecycle, true),
+	)
+
+	It("should not hand the VirtualCluster of a deleted claim to a new claim of the same name", func() {
+		pool.Spec.Replicas = 0
+		released := member("ci-released", templateHash(&pool.Spec.Template), true)
+		released.Labels[claimLabel] = "job-1"
+		released.Annotations[claimUIDAnnotation] = "previous-uid"
+		newReconciler(pool, released, newClaim("job-1"))
+		reconcilePool()
+
+		claim := getClaim("job-1")
+		Expect(claim.Status.Phase).To(Equal(corev1alpha1.VirtualClusterClaimPending))
+		Expect(members()).NotTo(ContainElement(HaveField("Name", "ci-released")))
+	})
+
+	It("should mark a claim lost when its VirtualCluster is deleted", func() {
+		claim := newClaim("job-1")
+		claim.Status = corev1alpha1.VirtualClusterClaimStatus{
+			Phase:              corev1alpha1.VirtualClusterClaimBound,
+			VirtualClusterName: "ci-gone",
+		}
+		newReconciler(pool, claim)
+		reconcilePool()
+
+		updated := getClaim("job-1")
+		Expect(updated.Status.Phase).To(Equal(corev1alpha1.VirtualClusterClaimLost))
+		Expect(updated.Status.Message).To(Equal("VirtualCluster ci-gone was deleted"))
+		Expect(recorder.Events).To(Receive(ContainSubstring("Warning Lost")))
+	})
+
+	It("should keep the claims of a missing pool pending", func() {
+		newReconciler(newClaim("job-1"))
+		reconcilePool()
+
+		claim := getClaim("job-1")
+		Expect(claim.Status.Phase).To(Equal(corev1alpha1.VirtualClusterClaimPending))
+		Expect(claim.Status.Message).To(Equal("VirtualClusterPool ci not found"))
+	})
+
+	It("should replace the VirtualClusters of a previous template once new ones are ready", func() {
+		pool.Spec.Replicas = 1
+		newReconciler(pool, member("ci-old", "previous", true))
+		reconcilePool()
+
+		// The old VirtualCluster stays available until the new one is ready
+		vclusters := members()
+		Expect(vclusters).To(HaveLen(2))
+		var created *corev1alpha1.VirtualCluster
+		for i := range vclusters {
+			if vclusters[i].Name != "ci-old" {
+				created = &vclusters[i]
+			}
+		}
+		Expect(created.Annotations).To(HaveKeyWithValue(templateHashAnnotation, templateHash(&pool.Spec.Template)))
+
+		created.Status.Phase = corev1alpha1.VirtualClusterRunning
+		created.Status.KubeconfigSecretRef = &corev1alpha1.SecretKeyReference{Name: created.Name + "-kubeconfig", Key: defaultKubeconfigKey}
+		Expect(reconciler.Status().Update(ctx, created)).To(Succeed())
+		reconcilePool()
+
+		Expect(members()).To(ConsistOf(HaveField("Name", created.Name)))
+	})
+
+	It("should map a claim to its pool", func() {
+		newReconciler()
+		Expect(reconciler.requestsForClaim(ctx, newClaim("job-1"))).To(ConsistOf(reconcile.Request{NamespacedName: key}))
+	})
+})
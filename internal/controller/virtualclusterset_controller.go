@@ -0,0 +1,256 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	corev1alpha1 "github.com/OpenVirtualCluster/openvirtualcluster-operator/api/v1alpha1"
+)
+
+const (
+	// setLabel holds the name of the set a VirtualCluster belongs to
+	setLabel = "core.openvc.dev/set"
+
+	// setIndexLabel holds the index of a VirtualCluster in its set
+	setIndexLabel = "core.openvc.dev/set-index"
+
+	// defaultNamePattern names the VirtualClusters of a set without a
+	// spec.namePattern
+	defaultNamePattern = "{name}-{index}"
+)
+
+// VirtualClusterSetReconciler reconciles a VirtualClusterSet object
+type VirtualClusterSetReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=core.openvc.dev,resources=virtualclustersets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core.openvc.dev,resources=virtualclustersets/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=core.openvc.dev,resources=virtualclustersets/finalizers,verbs=update
+
+// Reconcile creates the VirtualClusters of the set, deletes the ones beyond
+// spec.replicas and rolls out changes of the template to them, keeping at most
+// maxUnavailable of them unavailable.
+func (r *VirtualClusterSetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	logger.Info("Reconciling VirtualClusterSet", "namespace", req.Namespace, "name", req.Name)
+
+	set := &corev1alpha1.VirtualClusterSet{}
+	if err := r.Get(ctx, req.NamespacedName, set); err != nil {
+		if errors.IsNotFound(err) {
+			// The VirtualClusters are deleted along with the set that owns them
+			return ctrl.Result{}, nil
+		}
+		logger.Error(err, "Failed to get VirtualClusterSet")
+		return ctrl.Result{}, err
+	}
+	if !set.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	list := &corev1alpha1.VirtualClusterList{}
+	if err := r.List(ctx, list, client.InNamespace(set.Namespace), client.MatchingLabels{setLabel: set.Name}); err != nil {
+		logger.Error(err, "Failed to list VirtualClusters of the set")
+		return ctrl.Result{}, err
+	}
+
+	// Delete the VirtualClusters beyond spec.replicas
+	replicas := int(set.Spec.Replicas)
+	children := make([]*corev1alpha1.VirtualCluster, replicas)
+	var surplus []*corev1alpha1.VirtualCluster
+	for i := range list.Items {
+		vcluster := &list.Items[i]
+		if !metav1.IsControlledBy(vcluster, set) || !vcluster.DeletionTimestamp.IsZero() {
+			continue
+		}
+		index, err := strconv.Atoi(vcluster.Labels[setIndexLabel])
+		if err != nil || index < 0 || index >= replicas || children[index] != nil {
+			surplus = append(surplus, vcluster)
+			continue
+		}
+		children[index] = vcluster
+	}
+	for _, vcluster := range surplus {
+		if err := r.Delete(ctx, vcluster); client.IgnoreNotFound(err) != nil {
+			logger.Error(err, "Failed to delete VirtualCluster", "virtualCluster", vcluster.Name)
+			return ctrl.Result{}, err
+		}
+		logger.Info("Deleted VirtualCluster", "virtualCluster", vcluster.Name)
+		r.Recorder.Event(set, corev1.EventTypeNormal, "Deleted", fmt.Sprintf("Deleted VirtualCluster %s", vcluster.Name))
+	}
+
+	// Create the missing VirtualClusters from the current template
+	hash := templateHash(&set.Spec.Template)
+	for index, vcluster := range children {
+		if vcluster != nil {
+			continue
+		}
+		vcluster, err := r.createVirtualCluster(ctx, set, index, hash)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		children[index] = vcluster
+	}
+
+	// Roll out the template. Updating unavailable VirtualClusters doesn't
+	// reduce the availability, so only available ones count against
+	// maxUnavailable.
+	unavailable := 0
+	for _, vcluster := range children {
+		if !isAvailableInSet(vcluster) {
+			unavailable++
+		}
+	}
+	maxUnavailable := maxUnavailableOf(set)
+	for _, vcluster := range children {
+		if vcluster.Annotations[templateHashAnnotation] == hash {
+			continue
+		}
+		available := isAvailableInSet(vcluster)
+		if available && unavailable >= maxUnavailable {
+			continue
+		}
+		applyTemplate(vcluster, &set.Spec.Template, hash)
+		if err := r.Update(ctx, vcluster); err != nil {
+			logger.Error(err, "Failed to update VirtualCluster", "virtualCluster", vcluster.Name)
+			return ctrl.Result{}, err
+		}
+		logger.Info("Updated VirtualCluster to the template", "virtualCluster", vcluster.Name)
+		r.Recorder.Event(set, corev1.EventTypeNormal, "Updated", fmt.Sprintf("Updated VirtualCluster %s", vcluster.Name))
+		if available {
+			unavailable++
+		}
+	}
+
+	status := corev1alpha1.VirtualClusterSetStatus{
+		ObservedGeneration: set.Generation,
+		Replicas:           int32(len(children)),
+	}
+	for _, vcluster := range children {
+		if isAvailableInSet(vcluster) {
+			status.ReadyReplicas++
+		}
+		if vcluster.Status.Phase == corev1alpha1.VirtualClusterFailed {
+			status.FailedReplicas++
+		}
+		if vcluster.Annotations[templateHashAnnotation] == hash {
+			status.UpdatedReplicas++
+		}
+	}
+	if !equality.Semantic.DeepEqual(set.Status, status) {
+		set.Status = status
+		if err := r.Status().Update(ctx, set); err != nil {
+			logger.Error(err, "Failed to update VirtualClusterSet status")
+			return ctrl.Result{}, err
+		}
+	}
+	return ctrl.Result{}, nil
+}
+
+// createVirtualCluster creates the VirtualCluster with the given index from
+// the template of the set.
+func (r *VirtualClusterSetReconciler) createVirtualCluster(ctx context.Context, set *corev1alpha1.VirtualClusterSet, index int, hash string) (*corev1alpha1.VirtualCluster, error) {
+	vcluster := &corev1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      childName(set, index),
+			Namespace: set.Namespace,
+		},
+	}
+	applyTemplate(vcluster, &set.Spec.Template, hash)
+	vcluster.Labels[setLabel] = set.Name
+	vcluster.Labels[setIndexLabel] = strconv.Itoa(index)
+
+	if err := ctrl.SetControllerReference(set, vcluster, r.Scheme); err != nil {
+		return nil, err
+	}
+	if err := r.Create(ctx, vcluster); err != nil {
+		if errors.IsAlreadyExists(err) {
+			// The VirtualCluster isn't listed, so it belongs to someone else
+			r.Recorder.Event(set, corev1.EventTypeWarning, "NameConflict",
+				fmt.Sprintf("VirtualCluster %s already exists and is not managed by the set", vcluster.Name))
+		}
+		return nil, fmt.Errorf("failed to create VirtualCluster %s: %w", vcluster.Name, err)
+	}
+
+	log.FromContext(ctx).Info("Created VirtualCluster", "virtualCluster", vcluster.Name)
+	r.Recorder.Event(set, corev1.EventTypeNormal, "Created", fmt.Sprintf("Created VirtualCluster %s", vcluster.Name))
+	return vcluster, nil
+}
+
+// childName returns the name of the VirtualCluster with the given index.
+func childName(set *corev1alpha1.VirtualClusterSet, index int) string {
+	pattern := set.Spec.NamePattern
+	if pattern == "" {
+		pattern = defaultNamePattern
+	}
+	return strings.NewReplacer("{name}", set.Name, "{index}", strconv.Itoa(index)).Replace(pattern)
+}
+
+// maxUnavailableOf returns the number of VirtualClusters of the set that may
+// be unavailable during a rollout. Percentages round down, but at least one
+// VirtualCluster is updated at a time.
+func maxUnavailableOf(set *corev1alpha1.VirtualClusterSet) int {
+	maxUnavailable := intstr.FromInt32(1)
+	if set.Spec.UpdateStrategy.MaxUnavailable != nil {
+		maxUnavailable = *set.Spec.UpdateStrategy.MaxUnavailable
+	}
+	n, err := intstr.GetScaledValueFromIntOrPercent(&maxUnavailable, int(set.Spec.Replicas), false)
+	if err != nil || n < 1 {
+		return 1
+	}
+	return n
+}
+
+// isAvailableInSet returns true if the VirtualCluster is running with its
+// current spec applied and a healthy control plane. A degraded VirtualCluster
+// stays in the Running phase, so the Available condition is checked as well.
+func isAvailableInSet(vcluster *corev1alpha1.VirtualCluster) bool {
+	return vcluster.Status.Phase == corev1alpha1.VirtualClusterRunning &&
+		vcluster.Status.ObservedGeneration == vcluster.Generation &&
+		meta.IsStatusConditionTrue(vcluster.Status.Conditions, VirtualClusterConditionAvailable)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *VirtualClusterSetReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&corev1alpha1.VirtualClusterSet{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		// Continue the rollout and update the counts when the VirtualClusters
+		// change
+		Owns(&corev1alpha1.VirtualCluster{}).
+		Named("virtualclusterset").
+		Complete(r)
+}
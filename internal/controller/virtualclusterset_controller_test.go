@@ -0,0 +1,268 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strconv"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	corev1alpha1 "github.com/OpenVirtualCluster/openvirtualcluster-operator/api/v1alpha1"
+)
+
+var _ = Describe("VirtualClusterSet Controller", func() {
+	var (
+		ctx        context.Context
+		recorder   *record.FakeRecorder
+		reconciler *VirtualClusterSetReconciler
+		set        *corev1alpha1.VirtualClusterSet
+	)
+
+	key := types.NamespacedName{Name: "lab", Namespace: "default"}
+
+	newReconciler := func(objs ...client.Object) {
+		fakeClient, s := NewTestClient(objs...)
+		reconciler = &VirtualClusterSetReconciler{Client: fakeClient, Scheme: s, Recorder: recorder}
+	}
+
+	reconcileSet := func() {
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	// child returns the VirtualCluster of the set with the given index,
+	// created from the template with the given hash
+	child := func(index int, hash string, phase corev1alpha1.VirtualClusterPhase) *corev1alpha1.VirtualCluster {
+		vc := CreateTestVirtualCluster(childName(set, index), key.Namespace, "")
+		vc.Generation = 1
+		vc.Labels = map[string]string{setLabel: set.Name, setIndexLabel: strconv.Itoa(index)}
+		vc.Annotations = map[string]string{templateHashAnnotation: hash}
+		vc.OwnerReferences = []metav1.OwnerReference{{
+			APIVersion: corev1alpha1.GroupVersion.String(),
+			Kind:       "VirtualClusterSet",
+			Name:       set.Name,
+			UID:        set.UID,
+			Controller: ptr.To(true),
+		}}
+		vc.Status.Phase = phase
+		vc.Status.ObservedGeneration = 1
+		if phase == corev1alpha1.VirtualClusterRunning {
+			meta.SetStatusCondition(&vc.Status.Conditions, metav1.Condition{
+				Type:   VirtualClusterConditionAvailable,
+				Status: metav1.ConditionTrue,
+				Reason: "Running",
+			})
+		}
+		return vc
+	}
+
+	getChild := func(index int) *corev1alpha1.VirtualCluster {
+		vc := &corev1alpha1.VirtualCluster{}
+		Expect(reconciler.Get(ctx, client.ObjectKey{Namespace: key.Namespace, Name: childName(set, index)}, vc)).To(Succeed())
+		return vc
+	}
+
+	children := func() []corev1alpha1.VirtualCluster {
+		list := &corev1alpha1.VirtualClusterList{}
+		Expect(reconciler.List(ctx, list, client.MatchingLabels{setLabel: key.Name})).To(Succeed())
+		return list.Items
+	}
+
+	getSet := func() *corev1alpha1.VirtualClusterSet {
+		updated := &corev1alpha1.VirtualClusterSet{}
+		Expect(reconciler.Get(ctx, key, updated)).To(Succeed())
+		return updated
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		recorder = record.NewFakeRecorder(20)
+
+		set = &corev1alpha1.VirtualClusterSet{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace, UID: "lab-uid", Generation: 1},
+			Spec: corev1alpha1.VirtualClusterSetSpec{
+				Replicas:    3,
+				NamePattern: "{name}-student-{index}",
+				Template: corev1alpha1.VirtualClusterTemplate{
+					Metadata: corev1alpha1.VirtualClusterTemplateMetadata{Labels: map[string]string{"course": "k8s-101"}},
+					Spec: corev1alpha1.VirtualClusterSpec{
+						Chart: corev1alpha1.HelmChart{Version: "v0.24.1"},
+					},
+				},
+			},
+		}
+	})
+
+	It("should create the VirtualClusters named by the pattern", func() {
+		newReconciler(set)
+		reconcileSet()
+
+		Expect(children()).To(ConsistOf(
+			HaveField("Name", "lab-student-0"),
+			HaveField("Name", "lab-student-1"),
+			HaveField("Name", "lab-student-2"),
+		))
+		vc := getChild(1)
+		Expect(vc.Labels).To(HaveKeyWithValue("course", "k8s-101"))
+		Expect(vc.Labels).To(HaveKeyWithValue(setIndexLabel, "1"))
+		Expect(vc.Spec.Chart.Version).To(Equal("v0.24.1"))
+		Expect(metav1.IsControlledBy(vc, set)).To(BeTrue())
+
+		status := getSet().Status
+		Expect(status.Replicas).To(Equal(int32(3)))
+		Expect(status.UpdatedReplicas).To(Equal(int32(3)))
+		Expect(status.ReadyReplicas).To(BeZero())
+	})
+
+	It("should name the VirtualClusters after the set by default", func() {
+		set.Spec.NamePattern = ""
+		Expect(childName(set, 4)).To(Equal("lab-4"))
+	})
+
+	It("should delete the VirtualClusters beyond the replicas", func() {
+		hash := templateHash(&set.Spec.Template)
+		set.Spec.Replicas = 1
+		newReconciler(set,
+			child(0, hash, corev1alpha1.VirtualClusterRunning),
+			child(1, hash, corev1alpha1.VirtualClusterRunning),
+			child(2, hash, corev1alpha1.VirtualClusterRunning))
+		reconcileSet()
+
+		Expect(children()).To(ConsistOf(HaveField("Name", "lab-student-0")))
+		Expect(getSet().Status.Replicas).To(Equal(int32(1)))
+	})
+
+	It("should aggregate the ready and failed VirtualClusters", func() {
+		hash := templateHash(&set.Spec.Template)
+		pending := child(2, hash, corev1alpha1.VirtualClusterRunning)
+		pending.Generation = 2
+		newReconciler(set,
+			child(0, hash, corev1alpha1.VirtualClusterRunning),
+			child(1, hash, corev1alpha1.VirtualClusterFailed),
+			pending)
+		reconcileSet()
+
+		status := getSet().Status
+		Expect(status.ReadyReplicas).To(Equal(int32(1)))
+		Expect(status.FailedReplicas).To(Equal(int32(1)))
+		Expect(status.ObservedGeneration).To(Equal(int64(1)))
+	})
+
+	It("should roll out a new chart version with at most maxUnavailable VirtualClusters unavailable", func() {
+		hash := templateHash(&set.Spec.Template)
+		set.Spec.Template.Spec.Chart.Version = "v0.25.0"
+		newReconciler(set,
+			child(0, hash, corev1alpha1.VirtualClusterRunning),
+			child(1, hash, corev1alpha1.VirtualClusterRunning),
+			child(2, hash, corev1alpha1.VirtualClusterRunning))
+		reconcileSet()
+
+		Expect(getChild(0).Spec.Chart.Version).To(Equal("v0.25.0"))
+		Expect(getChild(1).Spec.Chart.Version).To(Equal("v0.24.1"))
+		Expect(getChild(2).Spec.Chart.Version).To(Equal("v0.24.1"))
+		Expect(getSet().Status.UpdatedReplicas).To(Equal(int32(1)))
+
+		// The rollout waits while the updated VirtualCluster is upgraded
+		upgrading := getChild(0)
+		upgrading.Status.Phase = corev1alpha1.VirtualClusterProvisioning
+		Expect(reconciler.Status().Update(ctx, upgrading)).To(Succeed())
+		reconcileSet()
+		Expect(getChild(1).Spec.Chart.Version).To(Equal("v0.24.1"))
+
+		upgraded := getChild(0)
+		upgraded.Status.Phase = corev1alpha1.VirtualClusterRunning
+		Expect(reconciler.Status().Update(ctx, upgraded)).To(Succeed())
+		reconcileSet()
+		Expect(getChild(1).Spec.Chart.Version).To(Equal("v0.25.0"))
+		Expect(getChild(2).Spec.Chart.Version).To(Equal("v0.24.1"))
+	})
+
+	It("should wait while an updated VirtualCluster is running but degraded", func() {
+		hash := templateHash(&set.Spec.Template)
+		set.Spec.Template.Spec.Chart.Version = "v0.25.0"
+		newReconciler(set,
+			child(0, hash, corev1alpha1.VirtualClusterRunning),
+			child(1, hash, corev1alpha1.VirtualClusterRunning),
+			child(2, hash, corev1alpha1.VirtualClusterRunning))
+		reconcileSet()
+		Expect(getChild(0).Spec.Chart.Version).To(Equal("v0.25.0"))
+
+		// The upgrade was applied, but the control plane is crash-looping
+		degraded := getChild(0)
+		meta.SetStatusCondition(&degraded.Status.Conditions, metav1.Condition{
+			Type:   VirtualClusterConditionAvailable,
+			Status: metav1.ConditionFalse,
+			Reason: "PodNotReady",
+		})
+		meta.SetStatusCondition(&degraded.Status.Conditions, metav1.Condition{
+			Type:   VirtualClusterConditionDegraded,
+			Status: metav1.ConditionTrue,
+			Reason: "PodNotReady",
+		})
+		Expect(reconciler.Status().Update(ctx, degraded)).To(Succeed())
+		reconcileSet()
+
+		Expect(getChild(1).Spec.Chart.Version).To(Equal("v0.24.1"))
+		Expect(getChild(2).Spec.Chart.Version).To(Equal("v0.24.1"))
+		Expect(getSet().Status.ReadyReplicas).To(Equal(int32(2)))
+	})
+
+	It("should update unavailable VirtualClusters without waiting", func() {
+		hash := templateHash(&set.Spec.Template)
+		set.Spec.Template.Spec.Chart.Version = "v0.25.0"
+		newReconciler(set,
+			child(0, hash, corev1alpha1.VirtualClusterRunning),
+			child(1, hash, corev1alpha1.VirtualClusterFailed),
+			child(2, hash, corev1alpha1.VirtualClusterFailed))
+		reconcileSet()
+
+		Expect(getChild(0).Spec.Chart.Version).To(Equal("v0.24.1"))
+		Expect(getChild(1).Spec.Chart.Version).To(Equal("v0.25.0"))
+		Expect(getChild(2).Spec.Chart.Version).To(Equal("v0.25.0"))
+	})
+
+	It("should not take over an existing VirtualCluster", func() {
+		set.Spec.Replicas = 1
+		newReconciler(set, CreateTestVirtualCluster("lab-student-0", key.Namespace, ""))
+
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).To(MatchError(ContainSubstring("failed to create VirtualCluster lab-student-0")))
+		Expect(recorder.Events).To(Receive(ContainSubstring("Warning NameConflict")))
+	})
+
+	DescribeTable("maxUnavailable",
+		func(maxUnavailable *intstr.IntOrString, replicas int32, expected int) {
+			set.Spec.Replicas = replicas
+			set.Spec.UpdateStrategy.MaxUnavailable = maxUnavailable
+			Expect(maxUnavailableOf(set)).To(Equal(expected))
+		},
+		Entry("defaults to 1", nil, int32(30), 1),
+		Entry("takes a number", ptr.To(intstr.FromInt32(5)), int32(30), 5),
+		Entry("scales a percentage", ptr.To(intstr.FromString("20%")), int32(30), 6),
+		Entry("updates at least one", ptr.To(intstr.FromString("10%")), int32(5), 1),
+	)
+})
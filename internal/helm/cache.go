@@ -0,0 +1,302 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/getter"
+	"helm.sh/helm/v3/pkg/repo"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// ChartCache caches repository indexes and chart archives for all
+// reconciles of the manager. Indexes are kept in memory and refreshed after
+// the refresh interval, archives are stored on disk and reused for as long
+// as the chart version exists, since published chart versions don't change.
+// It is safe for concurrent use.
+type ChartCache struct {
+	dir             string
+	refreshInterval time.Duration
+
+	mu      sync.Mutex
+	locks   map[string]*sync.Mutex
+	indexes map[string]*cachedIndex
+}
+
+// cachedIndex is a repository index and the time it was downloaded.
+type cachedIndex struct {
+	index     *repo.IndexFile
+	fetchedAt time.Time
+}
+
+// NewChartCache returns a ChartCache storing its files in dir. Indexes are
+// downloaded again once they are older than refreshInterval. A zero interval
+// downloads them on every lookup.
+func NewChartCache(dir string, refreshInterval time.Duration) (*ChartCache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create chart cache directory: %w", err)
+	}
+	return &ChartCache{
+		dir:             dir,
+		refreshInterval: refreshInterval,
+		locks:           map[string]*sync.Mutex{},
+		indexes:         map[string]*cachedIndex{},
+	}, nil
+}
+
+// lock locks the key and returns the function unlocking it, so concurrent
+// reconciles wait for a download of the same file instead of racing on it.
+func (c *ChartCache) lock(key string) func() {
+	c.mu.Lock()
+	l, ok := c.locks[key]
+	if !ok {
+		l = &sync.Mutex{}
+		c.locks[key] = l
+	}
+	c.mu.Unlock()
+
+	l.Lock()
+	return l.Unlock
+}
+
+// Locate returns the path of the archive of the chart described by ref,
+// downloading it if it isn't cached yet. opts carries the credentials and
+// TLS files of the repository.
+func (c *ChartCache) Locate(ctx context.Context, ref ChartRef, opts *action.ChartPathOptions, settings *cli.EnvSettings) (string, error) {
+	if ref.IsOCI() {
+		return c.locateOCI(ref, opts, settings)
+	}
+	return c.locateRepo(ctx, ref, opts, settings)
+}
+
+// locateRepo resolves the chart version in the cached index of the Helm
+// repository and downloads the archive once.
+func (c *ChartCache) locateRepo(ctx context.Context, ref ChartRef, opts *action.ChartPathOptions, settings *cli.EnvSettings) (string, error) {
+	index, fresh, err := c.index(ctx, ref, opts, settings, false)
+	if err != nil {
+		return "", err
+	}
+
+	version, err := index.Get(ref.Name, ref.Version)
+	if err != nil && !fresh {
+		// The version may have been published since the index was cached
+		if index, _, err = c.index(ctx, ref, opts, settings, true); err != nil {
+			return "", err
+		}
+		version, err = index.Get(ref.Name, ref.Version)
+	}
+	if err != nil {
+		return "", fmt.Errorf("chart %q version %q not found in %s repository", ref.Name, ref.Version, ref.RepoURL)
+	}
+	if len(version.URLs) == 0 {
+		return "", fmt.Errorf("chart %q version %q has no downloadable URLs", ref.Name, version.Version)
+	}
+
+	path := filepath.Join(c.dir, "charts", cacheKey(ref),
+		fmt.Sprintf("%s-%s.tgz", version.Name, version.Version))
+	unlock := c.lock(path)
+	defer unlock()
+
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+
+	chartURL, err := repo.ResolveReferenceURL(ref.RepoURL, version.URLs[0])
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve chart URL: %w", err)
+	}
+	g, err := getter.All(settings).ByScheme(strings.SplitN(chartURL, "://", 2)[0])
+	if err != nil {
+		return "", fmt.Errorf("failed to download chart %s: %w", chartURL, err)
+	}
+
+	// Credentials are only sent to the host of the repository
+	data, err := g.Get(chartURL,
+		getter.WithURL(ref.RepoURL),
+		getter.WithBasicAuth(opts.Username, opts.Password),
+		getter.WithTLSClientConfig(opts.CertFile, opts.KeyFile, opts.CaFile),
+		getter.WithInsecureSkipVerifyTLS(opts.InsecureSkipTLSverify),
+		getter.WithPlainHTTP(opts.PlainHTTP),
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to download chart %s: %w", chartURL, err)
+	}
+	if err := writeFileAtomic(path, data.Bytes()); err != nil {
+		return "", fmt.Errorf("failed to cache chart %s: %w", chartURL, err)
+	}
+
+	log.FromContext(ctx).Info("Cached chart", "chart", ref.Name, "version", version.Version, "path", path)
+	return path, nil
+}
+
+// index returns the index of the Helm repository of ref, downloading it if
+// it isn't cached, it is older than the refresh interval or force is set. It
+// reports whether the index was downloaded. When refreshing fails, the
+// cached index is used.
+func (c *ChartCache) index(ctx context.Context, ref ChartRef, opts *action.ChartPathOptions, settings *cli.EnvSettings, force bool) (*repo.IndexFile, bool, error) {
+	key := cacheKey(ref)
+	unlock := c.lock("index/" + key)
+	defer unlock()
+
+	c.mu.Lock()
+	cached := c.indexes[key]
+	c.mu.Unlock()
+	if cached != nil && !force && time.Since(cached.fetchedAt) < c.refreshInterval {
+		return cached.index, false, nil
+	}
+
+	r, err := repo.NewChartRepository(&repo.Entry{
+		Name:                  key,
+		URL:                   ref.RepoURL,
+		Username:              opts.Username,
+		Password:              opts.Password,
+		CertFile:              opts.CertFile,
+		KeyFile:               opts.KeyFile,
+		CAFile:                opts.CaFile,
+		InsecureSkipTLSverify: opts.InsecureSkipTLSverify,
+	}, getter.All(settings))
+	if err != nil {
+		return nil, false, err
+	}
+	r.CachePath = filepath.Join(c.dir, "repository")
+
+	path, err := r.DownloadIndexFile()
+	if err == nil {
+		var index *repo.IndexFile
+		if index, err = repo.LoadIndexFile(path); err == nil {
+			c.mu.Lock()
+			c.indexes[key] = &cachedIndex{index: index, fetchedAt: time.Now()}
+			c.mu.Unlock()
+			return index, true, nil
+		}
+	}
+
+	if cached != nil {
+		log.FromContext(ctx).Error(err, "Failed to refresh repository index, using the cached index", "repository", ref.RepoURL)
+		return cached.index, false, nil
+	}
+	return nil, false, fmt.Errorf("failed to download index of repository %s: %w", ref.RepoURL, err)
+}
+
+// locateOCI pulls a chart from an OCI registry. Charts with an exact version
+// are pulled once, version ranges are resolved by the registry every time.
+func (c *ChartCache) locateOCI(ref ChartRef, opts *action.ChartPathOptions, settings *cli.EnvSettings) (string, error) {
+	dir := filepath.Join(c.dir, "charts", cacheKey(ref))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+
+	version, err := semver.StrictNewVersion(strings.TrimPrefix(ref.Version, "v"))
+	if err != nil {
+		unlock := c.lock(dir)
+		defer unlock()
+		return opts.LocateChart(ref.String(), cacheSettings(settings, dir))
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("%s-%s.tgz", filepath.Base(ref.Name), version))
+	unlock := c.lock(path)
+	defer unlock()
+
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+
+	tmp, err := os.MkdirTemp(dir, ".pull-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tmp)
+
+	pulled, err := opts.LocateChart(ref.String(), cacheSettings(settings, tmp))
+	if err != nil {
+		return "", err
+	}
+	if err := os.Rename(pulled, path); err != nil {
+		return "", fmt.Errorf("failed to cache chart %s: %w", ref, err)
+	}
+	return path, nil
+}
+
+// cacheSettings returns a copy of settings downloading charts to dir.
+func cacheSettings(settings *cli.EnvSettings, dir string) *cli.EnvSettings {
+	s := *settings
+	s.RepositoryCache = dir
+	return &s
+}
+
+// cacheKey returns the directory name used for the repository of ref and the
+// credentials accessing it. All of the credentials are hashed, as nothing is
+// requested from the repository once a chart is cached: charts of private
+// repositories are only shared with the same credentials, not with anyone
+// sharing a fixed username such as "AWS" or "_json_key".
+func cacheKey(ref ChartRef) string {
+	h := sha256.New()
+	field := func(data []byte) {
+		// Prefix each field with its length so fields can't run into each other
+		_ = binary.Write(h, binary.BigEndian, uint64(len(data)))
+		h.Write(data)
+	}
+
+	field([]byte(strings.TrimSuffix(ref.RepoURL, "/")))
+	if creds := ref.Credentials; creds != nil {
+		field([]byte(creds.Username))
+		field([]byte(creds.Password))
+		field(creds.CAData)
+		field(creds.CertData)
+		field(creds.KeyData)
+		field([]byte(strconv.FormatBool(creds.InsecureSkipTLSVerify)))
+		field([]byte(strconv.FormatBool(creds.PlainHTTP)))
+	}
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
+
+// writeFileAtomic writes data to a temporary file next to path and renames
+// it, so readers never see a partially written file.
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".download-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,286 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/cli"
+)
+
+// testRepository is a Helm repository serving versions of the test chart and
+// counting the requests per path.
+type testRepository struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	versions []string
+	archives map[string][]byte
+	failing  bool
+	password string
+	requests map[string]*atomic.Int32
+}
+
+func newTestRepository(t *testing.T, versions ...string) *testRepository {
+	t.Helper()
+
+	r := &testRepository{archives: map[string][]byte{}, requests: map[string]*atomic.Int32{}}
+	r.Server = httptest.NewServer(http.HandlerFunc(r.serve))
+	t.Cleanup(r.Close)
+
+	for _, version := range versions {
+		r.publish(t, version)
+	}
+	return r
+}
+
+// publish packages the test chart with the version and adds it to the index.
+func (r *testRepository) publish(t *testing.T, version string) {
+	t.Helper()
+
+	chrt, err := loader.Load(writeTestChart(t))
+	require.NoError(t, err)
+	chrt.Metadata.Version = version
+
+	path, err := chartutil.Save(chrt, t.TempDir())
+	require.NoError(t, err)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.versions = append(r.versions, version)
+	r.archives[fmt.Sprintf("/test-chart-%s.tgz", version)] = data
+	r.requests[fmt.Sprintf("/test-chart-%s.tgz", version)] = &atomic.Int32{}
+}
+
+func (r *testRepository) serve(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.requests[req.URL.Path] == nil {
+		r.requests[req.URL.Path] = &atomic.Int32{}
+	}
+	r.requests[req.URL.Path].Add(1)
+
+	if r.failing {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if _, password, _ := req.BasicAuth(); r.password != "" && password != r.password {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if req.URL.Path == "/index.yaml" {
+		var index strings.Builder
+		index.WriteString("apiVersion: v1\nentries:\n  test-chart:\n")
+		for _, version := range r.versions {
+			fmt.Fprintf(&index, "  - name: test-chart\n    version: %s\n    apiVersion: v2\n    urls:\n    - test-chart-%s.tgz\n", version, version)
+		}
+		_, _ = w.Write([]byte(index.String()))
+		return
+	}
+
+	data, ok := r.archives[req.URL.Path]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	_, _ = w.Write(data)
+}
+
+// count returns the number of requests of the path.
+func (r *testRepository) count(path string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if c := r.requests[path]; c != nil {
+		return int(c.Load())
+	}
+	return 0
+}
+
+func (r *testRepository) setFailing(failing bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.failing = failing
+}
+
+func TestChartCache_SharesDownloads(t *testing.T) {
+	repository := newTestRepository(t, "0.1.0")
+	cache, err := NewChartCache(t.TempDir(), time.Hour)
+	require.NoError(t, err)
+
+	ref := ChartRef{RepoURL: repository.URL, Name: "test-chart", Version: "0.1.0"}
+	paths := make([]string, 10)
+	var wg sync.WaitGroup
+	for i := range paths {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			path, err := cache.Locate(context.Background(), ref, &action.ChartPathOptions{}, cli.New())
+			assert.NoError(t, err)
+			paths[i] = path
+		}()
+	}
+	wg.Wait()
+
+	for _, path := range paths {
+		assert.Equal(t, paths[0], path)
+	}
+	chrt, err := loader.Load(paths[0])
+	require.NoError(t, err)
+	assert.Equal(t, "0.1.0", chrt.Metadata.Version)
+
+	assert.Equal(t, 1, repository.count("/index.yaml"))
+	assert.Equal(t, 1, repository.count("/test-chart-0.1.0.tgz"))
+}
+
+func TestChartCache_SeparatesCredentials(t *testing.T) {
+	repository := newTestRepository(t, "0.1.0")
+	repository.password = "secret"
+	cache, err := NewChartCache(t.TempDir(), time.Hour)
+	require.NoError(t, err)
+
+	locate := func(username, password string) (string, error) {
+		ref := ChartRef{
+			RepoURL:     repository.URL,
+			Name:        "test-chart",
+			Version:     "0.1.0",
+			Credentials: &Credentials{Username: username, Password: password},
+		}
+		opts := &action.ChartPathOptions{Username: username, Password: password}
+		return cache.Locate(context.Background(), ref, opts, cli.New())
+	}
+
+	path, err := locate("AWS", "secret")
+	require.NoError(t, err)
+
+	// The same username with another password must not get the cached chart
+	_, err = locate("AWS", "guessed")
+	assert.Error(t, err)
+	assert.Equal(t, 2, repository.count("/index.yaml"))
+
+	cached, err := locate("AWS", "secret")
+	require.NoError(t, err)
+	assert.Equal(t, path, cached)
+	assert.Equal(t, 1, repository.count("/test-chart-0.1.0.tgz"))
+}
+
+func TestChartCache_RefreshesIndex(t *testing.T) {
+	tests := []struct {
+		name            string
+		refreshInterval time.Duration
+		wantIndex       int
+	}{
+		{name: "within the refresh interval", refreshInterval: time.Hour, wantIndex: 1},
+		{name: "after the refresh interval", refreshInterval: 0, wantIndex: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := newTestRepository(t, "0.1.0")
+			cache, err := NewChartCache(t.TempDir(), tt.refreshInterval)
+			require.NoError(t, err)
+
+			ref := ChartRef{RepoURL: repository.URL, Name: "test-chart", Version: "^0.1.0"}
+			for range 3 {
+				_, err := cache.Locate(context.Background(), ref, &action.ChartPathOptions{}, cli.New())
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantIndex, repository.count("/index.yaml"))
+			assert.Equal(t, 1, repository.count("/test-chart-0.1.0.tgz"))
+		})
+	}
+}
+
+func TestChartCache_RefreshesIndexForNewVersions(t *testing.T) {
+	repository := newTestRepository(t, "0.1.0")
+	cache, err := NewChartCache(t.TempDir(), time.Hour)
+	require.NoError(t, err)
+
+	ref := ChartRef{RepoURL: repository.URL, Name: "test-chart", Version: "0.1.0"}
+	_, err = cache.Locate(context.Background(), ref, &action.ChartPathOptions{}, cli.New())
+	require.NoError(t, err)
+
+	repository.publish(t, "0.2.0")
+	ref.Version = "0.2.0"
+	path, err := cache.Locate(context.Background(), ref, &action.ChartPathOptions{}, cli.New())
+	require.NoError(t, err)
+	assert.Contains(t, path, "test-chart-0.2.0.tgz")
+	assert.Equal(t, 2, repository.count("/index.yaml"))
+
+	ref.Version = "0.3.0"
+	_, err = cache.Locate(context.Background(), ref, &action.ChartPathOptions{}, cli.New())
+	assert.ErrorContains(t, err, `version "0.3.0" not found`)
+}
+
+func TestChartCache_KeepsIndexWhenRefreshFails(t *testing.T) {
+	repository := newTestRepository(t, "0.1.0")
+	cache, err := NewChartCache(t.TempDir(), 0)
+	require.NoError(t, err)
+
+	ref := ChartRef{RepoURL: repository.URL, Name: "test-chart", Version: "0.1.0"}
+	_, err = cache.Locate(context.Background(), ref, &action.ChartPathOptions{}, cli.New())
+	require.NoError(t, err)
+
+	repository.setFailing(true)
+	_, err = cache.Locate(context.Background(), ref, &action.ChartPathOptions{}, cli.New())
+	assert.NoError(t, err)
+
+	other, err := NewChartCache(t.TempDir(), 0)
+	require.NoError(t, err)
+	_, err = other.Locate(context.Background(), ref, &action.ChartPathOptions{}, cli.New())
+	assert.ErrorContains(t, err, "failed to download index")
+}
+
+func TestEngine_InstallFromChartCache(t *testing.T) {
+	repository := newTestRepository(t, "0.1.0")
+	cache, err := NewChartCache(t.TempDir(), time.Hour)
+	require.NoError(t, err)
+
+	engine := newTestEngine(t)
+	engine.charts = cache
+
+	req := ReleaseRequest{
+		Name:      "test-vc",
+		Namespace: "default",
+		Chart:     ChartRef{RepoURL: repository.URL, Name: "test-chart", Version: "0.1.0"},
+		Values:    map[string]interface{}{"image": "rancher/k3s:v1.25.0-k3s1"},
+	}
+	_, err = engine.Install(context.Background(), req)
+	require.NoError(t, err)
+	_, err = engine.Upgrade(context.Background(), req)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, repository.count("/index.yaml"))
+	assert.Equal(t, 1, repository.count("/test-chart-0.1.0.tgz"))
+}
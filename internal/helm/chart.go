@@ -0,0 +1,225 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/registry"
+)
+
+// ChartRef identifies a chart in a Helm repository or OCI registry.
+type ChartRef struct {
+	// RepoURL is the URL of the chart repository, or an oci:// URL of the
+	// registry path holding the chart
+	RepoURL string
+	// Name is the name of the chart in the repository
+	Name string
+	// Version is the version constraint of the chart
+	Version string
+	// Credentials are used to authenticate against the repository
+	Credentials *Credentials
+	// Archive is a packaged chart. When set, the chart is loaded from it and
+	// the repository is not contacted.
+	Archive []byte
+}
+
+// IsOCI returns true if the chart is served from an OCI registry.
+func (c ChartRef) IsOCI() bool {
+	return registry.IsOCI(c.RepoURL)
+}
+
+// String returns the location of the chart.
+func (c ChartRef) String() string {
+	if c.RepoURL == "" {
+		return c.Name
+	}
+	return fmt.Sprintf("%s/%s", strings.TrimSuffix(c.RepoURL, "/"), c.Name)
+}
+
+// Credentials holds authentication material for a chart repository.
+type Credentials struct {
+	// Username and Password are used for basic auth
+	Username string
+	Password string
+	// CAData is a PEM encoded CA bundle used to verify the repository
+	CAData []byte
+	// CertData and KeyData are a PEM encoded client certificate and key
+	CertData []byte
+	KeyData  []byte
+	// InsecureSkipTLSVerify skips verification of the repository certificate
+	InsecureSkipTLSVerify bool
+	// PlainHTTP talks to an OCI registry over plain HTTP
+	PlainHTTP bool
+}
+
+// tlsConfig builds a TLS configuration from the credentials.
+func (c *Credentials) tlsConfig() (*tls.Config, error) {
+	cfg := &tls.Config{InsecureSkipVerify: c.InsecureSkipTLSVerify} //nolint:gosec
+	if len(c.CAData) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(c.CAData) {
+			return nil, fmt.Errorf("failed to parse CA certificate")
+		}
+		cfg.RootCAs = pool
+	}
+	if len(c.CertData) > 0 || len(c.KeyData) > 0 {
+		cert, err := tls.X509KeyPair(c.CertData, c.KeyData)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
+
+// hasTLS returns true if the credentials carry any TLS settings.
+func (c *Credentials) hasTLS() bool {
+	return len(c.CAData) > 0 || len(c.CertData) > 0 || len(c.KeyData) > 0 || c.InsecureSkipTLSVerify
+}
+
+// writeTLSFiles writes the PEM data to dir, since the Helm repository getter
+// only accepts file paths, and sets the paths on opts.
+func (c *Credentials) writeTLSFiles(dir string, opts *action.ChartPathOptions) error {
+	files := []struct {
+		data []byte
+		name string
+		path *string
+	}{
+		{c.CAData, "ca.crt", &opts.CaFile},
+		{c.CertData, "tls.crt", &opts.CertFile},
+		{c.KeyData, "tls.key", &opts.KeyFile},
+	}
+	for _, f := range files {
+		if len(f.data) == 0 {
+			continue
+		}
+		path := filepath.Join(dir, f.name)
+		if err := os.WriteFile(path, f.data, 0600); err != nil {
+			return err
+		}
+		*f.path = path
+	}
+	return nil
+}
+
+// registryClientSetter is implemented by the install and upgrade actions.
+type registryClientSetter interface {
+	SetRegistryClient(client *registry.Client)
+}
+
+// newRegistryClient returns a registry client for OCI charts configured with
+// the given credentials.
+func (e *Engine) newRegistryClient(creds *Credentials) (*registry.Client, error) {
+	opts := []registry.ClientOption{
+		registry.ClientOptEnableCache(true),
+		registry.ClientOptWriter(io.Discard),
+		registry.ClientOptCredentialsFile(e.settings.RegistryConfig),
+	}
+	if creds != nil {
+		opts = append(opts, registry.ClientOptBasicAuth(creds.Username, creds.Password))
+		if creds.PlainHTTP {
+			opts = append(opts, registry.ClientOptPlainHTTP())
+		}
+		if creds.hasTLS() {
+			tlsConfig, err := creds.tlsConfig()
+			if err != nil {
+				return nil, err
+			}
+			opts = append(opts, registry.ClientOptHTTPClient(&http.Client{
+				Transport: &http.Transport{
+					TLSClientConfig: tlsConfig,
+					Proxy:           http.ProxyFromEnvironment,
+				},
+			}))
+		}
+	}
+	return registry.NewClient(opts...)
+}
+
+// loadChart locates the chart described by ref, downloading it if needed,
+// and loads it into memory.
+func (e *Engine) loadChart(ctx context.Context, opts *action.ChartPathOptions, setter registryClientSetter, ref ChartRef) (*chart.Chart, error) {
+	if len(ref.Archive) > 0 {
+		chrt, err := loader.LoadArchive(bytes.NewReader(ref.Archive))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load chart archive: %w", err)
+		}
+		return chrt, nil
+	}
+
+	opts.Version = ref.Version
+	name := ref.Name
+
+	if ref.IsOCI() {
+		// OCI charts are addressed by their full reference
+		name = ref.String()
+	} else {
+		opts.RepoURL = ref.RepoURL
+	}
+
+	registryClient, err := e.newRegistryClient(ref.Credentials)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create registry client: %w", err)
+	}
+	setter.SetRegistryClient(registryClient)
+
+	if creds := ref.Credentials; creds != nil {
+		opts.Username = creds.Username
+		opts.Password = creds.Password
+		opts.InsecureSkipTLSverify = creds.InsecureSkipTLSVerify
+		opts.PlainHTTP = creds.PlainHTTP
+
+		dir, err := os.MkdirTemp("", "openvc-chart-tls-")
+		if err != nil {
+			return nil, err
+		}
+		defer os.RemoveAll(dir)
+
+		if err := creds.writeTLSFiles(dir, opts); err != nil {
+			return nil, fmt.Errorf("failed to write TLS files: %w", err)
+		}
+	}
+
+	var path string
+	if e.charts != nil && ref.RepoURL != "" {
+		path, err = e.charts.Locate(ctx, ref, opts, e.settings)
+	} else {
+		path, err = opts.LocateChart(name, e.settings)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate chart %s: %w", ref, err)
+	}
+
+	chrt, err := loader.Load(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load chart %s: %w", path, err)
+	}
+	return chrt, nil
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/action"
+)
+
+func TestChartRef_String(t *testing.T) {
+	tests := []struct {
+		name    string
+		ref     ChartRef
+		want    string
+		wantOCI bool
+	}{
+		{
+			name: "http repository",
+			ref:  ChartRef{RepoURL: "https://charts.loft.sh", Name: "vcluster"},
+			want: "https://charts.loft.sh/vcluster",
+		},
+		{
+			name:    "oci registry with trailing slash",
+			ref:     ChartRef{RepoURL: "oci://ghcr.io/loft-sh/charts/", Name: "vcluster"},
+			want:    "oci://ghcr.io/loft-sh/charts/vcluster",
+			wantOCI: true,
+		},
+		{
+			name: "local chart",
+			ref:  ChartRef{Name: "/charts/vcluster"},
+			want: "/charts/vcluster",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.ref.String())
+			assert.Equal(t, tt.wantOCI, tt.ref.IsOCI())
+		})
+	}
+}
+
+func TestCredentials_TLSConfig(t *testing.T) {
+	creds := &Credentials{InsecureSkipTLSVerify: true}
+	assert.True(t, creds.hasTLS())
+
+	cfg, err := creds.tlsConfig()
+	require.NoError(t, err)
+	assert.True(t, cfg.InsecureSkipVerify)
+
+	creds = &Credentials{CAData: []byte("not a certificate")}
+	_, err = creds.tlsConfig()
+	assert.Error(t, err)
+
+	assert.False(t, (&Credentials{Username: "user", Password: "pass"}).hasTLS())
+}
+
+func TestCredentials_WriteTLSFiles(t *testing.T) {
+	dir := t.TempDir()
+	creds := &Credentials{CAData: []byte("ca"), CertData: []byte("cert")}
+
+	opts := &action.ChartPathOptions{}
+	require.NoError(t, creds.writeTLSFiles(dir, opts))
+
+	assert.Empty(t, opts.KeyFile)
+	data, err := os.ReadFile(opts.CaFile)
+	require.NoError(t, err)
+	assert.Equal(t, "ca", string(data))
+	data, err = os.ReadFile(opts.CertFile)
+	require.NoError(t, err)
+	assert.Equal(t, "cert", string(data))
+}
@@ -0,0 +1,347 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package helm wraps the Helm v3 SDK behind a small interface so that the
+// VirtualCluster reconciler can install, upgrade and uninstall releases
+// without shelling out to the helm binary.
+package helm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// defaultTimeout mirrors the default --timeout of the helm CLI.
+const defaultTimeout = 5 * time.Minute
+
+// ErrReleaseNotFound is returned when an operation targets a release that
+// does not exist in the namespace.
+var ErrReleaseNotFound = driver.ErrReleaseNotFound
+
+// IsReleaseNotFound returns true if the error indicates that the release
+// does not exist.
+func IsReleaseNotFound(err error) bool {
+	return errors.Is(err, ErrReleaseNotFound)
+}
+
+// ReleaseEngine performs the Helm release operations needed by the reconciler.
+type ReleaseEngine interface {
+	// Exists reports whether a deployed or failed release with the given name
+	// exists in the namespace.
+	Exists(ctx context.Context, namespace, name string) (bool, error)
+
+	// Get returns the latest revision of a release, whatever its status. It
+	// returns ErrReleaseNotFound if the release does not exist.
+	Get(ctx context.Context, namespace, name string) (*release.Release, error)
+
+	// Install installs a new release.
+	Install(ctx context.Context, req ReleaseRequest) (*release.Release, error)
+
+	// Upgrade upgrades an existing release.
+	Upgrade(ctx context.Context, req ReleaseRequest) (*release.Release, error)
+
+	// Rollback rolls a release back to a previous revision.
+	Rollback(ctx context.Context, req RollbackRequest) (*release.Release, error)
+
+	// History returns the revisions of a release, oldest first. It returns
+	// ErrReleaseNotFound if the release does not exist.
+	History(ctx context.Context, namespace, name string) ([]*release.Release, error)
+
+	// DeleteRevision removes the record of a single revision of a release
+	// without touching its resources. It returns ErrReleaseNotFound if the
+	// revision does not exist.
+	DeleteRevision(ctx context.Context, namespace, name string, revision int) error
+
+	// Uninstall removes a release. It returns ErrReleaseNotFound if the
+	// release does not exist.
+	Uninstall(ctx context.Context, namespace, name string) error
+}
+
+// ReleaseRequest describes the desired state of a release.
+type ReleaseRequest struct {
+	// Name is the name of the release
+	Name string
+	// Namespace is the namespace the release is installed into
+	Namespace string
+	// Chart is the chart to deploy
+	Chart ChartRef
+	// Values are the values passed to the chart
+	Values map[string]interface{}
+	// CreateNamespace creates the release namespace on install if it does not exist
+	CreateNamespace bool
+	// Options configure how the operation is run
+	Options OperationOptions
+}
+
+// RollbackRequest describes a rollback of a release.
+type RollbackRequest struct {
+	// Name is the name of the release
+	Name string
+	// Namespace is the namespace of the release
+	Namespace string
+	// Revision is the revision to roll back to. Zero rolls back to the
+	// previous revision.
+	Revision int
+	// Options configure how the operation is run
+	Options OperationOptions
+}
+
+// OperationOptions configure how a Helm operation is run.
+type OperationOptions struct {
+	// Atomic rolls back a failed upgrade and uninstalls a failed install. It
+	// implies Wait.
+	Atomic bool
+	// Wait waits until the resources of the release are ready
+	Wait bool
+	// Timeout of the operation. Defaults to the context deadline or the
+	// default of the helm CLI.
+	Timeout time.Duration
+	// MaxHistory limits the revisions kept for the release. Zero keeps all.
+	MaxHistory int
+}
+
+// timeout returns the timeout of the operation.
+func (o OperationOptions) timeout(ctx context.Context) time.Duration {
+	if o.Timeout > 0 {
+		return o.Timeout
+	}
+	return timeoutFromContext(ctx)
+}
+
+// Engine is a ReleaseEngine backed by the Helm v3 SDK. Releases are stored
+// as Secrets, matching the default of the helm CLI.
+type Engine struct {
+	settings *cli.EnvSettings
+
+	// charts caches repository indexes and chart archives. Without it charts
+	// are downloaded to the cache of the helm CLI on every operation.
+	charts *ChartCache
+
+	// newConfiguration builds the action configuration for a namespace.
+	newConfiguration func(ctx context.Context, namespace string) (*action.Configuration, error)
+}
+
+var _ ReleaseEngine = &Engine{}
+
+// NewEngine returns an Engine talking to the cluster described by config.
+// Charts are loaded through the shared cache charts, if set.
+func NewEngine(config *rest.Config, charts *ChartCache) (*Engine, error) {
+	dc, mapper, err := newDiscovery(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create discovery client: %w", err)
+	}
+
+	return &Engine{
+		settings: cli.New(),
+		charts:   charts,
+		newConfiguration: func(ctx context.Context, namespace string) (*action.Configuration, error) {
+			logger := log.FromContext(ctx)
+
+			cfg := &action.Configuration{}
+			getter := newRESTClientGetter(config, namespace, dc, mapper)
+			if err := cfg.Init(getter, namespace, "secret", func(format string, v ...interface{}) {
+				logger.V(1).Info(fmt.Sprintf(format, v...))
+			}); err != nil {
+				return nil, fmt.Errorf("failed to initialize Helm configuration: %w", err)
+			}
+			return cfg, nil
+		},
+	}, nil
+}
+
+// Exists reports whether a deployed or failed release exists.
+func (e *Engine) Exists(ctx context.Context, namespace, name string) (bool, error) {
+	cfg, err := e.newConfiguration(ctx, namespace)
+	if err != nil {
+		return false, err
+	}
+
+	list := action.NewList(cfg)
+	list.Filter = fmt.Sprintf("^%s$", regexp.QuoteMeta(name))
+
+	releases, err := list.Run()
+	if err != nil {
+		return false, fmt.Errorf("failed to list Helm releases: %w", err)
+	}
+
+	for _, rel := range releases {
+		if rel.Name == name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Get returns the latest revision of a release.
+func (e *Engine) Get(ctx context.Context, namespace, name string) (*release.Release, error) {
+	cfg, err := e.newConfiguration(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	rel, err := action.NewGet(cfg).Run(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get release %s/%s: %w", namespace, name, err)
+	}
+	return rel, nil
+}
+
+// Install installs a new release.
+func (e *Engine) Install(ctx context.Context, req ReleaseRequest) (*release.Release, error) {
+	cfg, err := e.newConfiguration(ctx, req.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	install := action.NewInstall(cfg)
+	install.ReleaseName = req.Name
+	install.Namespace = req.Namespace
+	install.CreateNamespace = req.CreateNamespace
+	install.Atomic = req.Options.Atomic
+	install.Wait = req.Options.Wait || req.Options.Atomic
+	install.Timeout = req.Options.timeout(ctx)
+
+	chrt, err := e.loadChart(ctx, &install.ChartPathOptions, install, req.Chart)
+	if err != nil {
+		return nil, err
+	}
+
+	rel, err := install.RunWithContext(ctx, chrt, req.Values)
+	if err != nil {
+		return rel, fmt.Errorf("failed to install release %s/%s: %w", req.Namespace, req.Name, err)
+	}
+	return rel, nil
+}
+
+// Upgrade upgrades an existing release.
+func (e *Engine) Upgrade(ctx context.Context, req ReleaseRequest) (*release.Release, error) {
+	cfg, err := e.newConfiguration(ctx, req.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	upgrade := action.NewUpgrade(cfg)
+	upgrade.Namespace = req.Namespace
+	upgrade.Atomic = req.Options.Atomic
+	upgrade.Wait = req.Options.Wait || req.Options.Atomic
+	upgrade.Timeout = req.Options.timeout(ctx)
+	upgrade.MaxHistory = req.Options.MaxHistory
+
+	chrt, err := e.loadChart(ctx, &upgrade.ChartPathOptions, upgrade, req.Chart)
+	if err != nil {
+		return nil, err
+	}
+
+	rel, err := upgrade.RunWithContext(ctx, req.Name, chrt, req.Values)
+	if err != nil {
+		return rel, fmt.Errorf("failed to upgrade release %s/%s: %w", req.Namespace, req.Name, err)
+	}
+	return rel, nil
+}
+
+// Rollback rolls a release back to a previous revision.
+func (e *Engine) Rollback(ctx context.Context, req RollbackRequest) (*release.Release, error) {
+	cfg, err := e.newConfiguration(ctx, req.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	rollback := action.NewRollback(cfg)
+	rollback.Version = req.Revision
+	rollback.Wait = req.Options.Wait || req.Options.Atomic
+	rollback.Timeout = req.Options.timeout(ctx)
+	rollback.MaxHistory = req.Options.MaxHistory
+
+	if err := rollback.Run(req.Name); err != nil {
+		return nil, fmt.Errorf("failed to roll back release %s/%s: %w", req.Namespace, req.Name, err)
+	}
+
+	// Rollback doesn't return the release it created
+	rel, err := action.NewGet(cfg).Run(req.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get release %s/%s: %w", req.Namespace, req.Name, err)
+	}
+	return rel, nil
+}
+
+// History returns the revisions of a release, oldest first.
+func (e *Engine) History(ctx context.Context, namespace, name string) ([]*release.Release, error) {
+	cfg, err := e.newConfiguration(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	releases, err := action.NewHistory(cfg).Run(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get history of release %s/%s: %w", namespace, name, err)
+	}
+
+	sort.Slice(releases, func(i, j int) bool { return releases[i].Version < releases[j].Version })
+	return releases, nil
+}
+
+// DeleteRevision removes the record of a revision of a release.
+func (e *Engine) DeleteRevision(ctx context.Context, namespace, name string, revision int) error {
+	cfg, err := e.newConfiguration(ctx, namespace)
+	if err != nil {
+		return err
+	}
+
+	if _, err := cfg.Releases.Delete(name, revision); err != nil {
+		return fmt.Errorf("failed to delete revision %d of release %s/%s: %w", revision, namespace, name, err)
+	}
+	return nil
+}
+
+// Uninstall removes a release.
+func (e *Engine) Uninstall(ctx context.Context, namespace, name string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	cfg, err := e.newConfiguration(ctx, namespace)
+	if err != nil {
+		return err
+	}
+
+	uninstall := action.NewUninstall(cfg)
+	uninstall.Timeout = timeoutFromContext(ctx)
+
+	if _, err := uninstall.Run(name); err != nil {
+		return fmt.Errorf("failed to uninstall release %s/%s: %w", namespace, name, err)
+	}
+	return nil
+}
+
+// timeoutFromContext converts the context deadline, if any, into a Helm
+// operation timeout, falling back to the helm CLI default.
+func timeoutFromContext(ctx context.Context) time.Duration {
+	if deadline, ok := ctx.Deadline(); ok {
+		return time.Until(deadline)
+	}
+	return defaultTimeout
+}
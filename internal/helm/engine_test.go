@@ -0,0 +1,254 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/cli"
+	kubefake "helm.sh/helm/v3/pkg/kube/fake"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage"
+	"helm.sh/helm/v3/pkg/storage/driver"
+)
+
+// newTestEngine returns an Engine that keeps releases in memory and does not
+// talk to a cluster.
+func newTestEngine(t *testing.T) *Engine {
+	t.Helper()
+
+	store := storage.Init(driver.NewMemory())
+	return &Engine{
+		settings: cli.New(),
+		newConfiguration: func(_ context.Context, _ string) (*action.Configuration, error) {
+			return &action.Configuration{
+				Releases:     store,
+				KubeClient:   &kubefake.PrintingKubeClient{Out: io.Discard},
+				Capabilities: chartutil.DefaultCapabilities,
+				Log:          func(string, ...interface{}) {},
+			}, nil
+		},
+	}
+}
+
+// writeTestChart writes a minimal chart to a temporary directory and returns
+// its path.
+func writeTestChart(t *testing.T) string {
+	t.Helper()
+
+	dir := filepath.Join(t.TempDir(), "test-chart")
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "templates"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Chart.yaml"), []byte(
+		"apiVersion: v2\nname: test-chart\nversion: 0.1.0\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "templates", "configmap.yaml"), []byte(
+		"apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: {{ .Release.Name }}\ndata:\n  image: {{ .Values.image | quote }}\n"), 0644))
+	return dir
+}
+
+func TestEngine_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	engine := newTestEngine(t)
+	req := ReleaseRequest{
+		Name:      "test-vc",
+		Namespace: "default",
+		Chart:     ChartRef{Name: writeTestChart(t)},
+		Values:    map[string]interface{}{"image": "rancher/k3s:v1.25.0-k3s1"},
+	}
+
+	exists, err := engine.Exists(ctx, req.Namespace, req.Name)
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	rel, err := engine.Install(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, 1, rel.Version)
+	assert.Contains(t, rel.Manifest, "rancher/k3s:v1.25.0-k3s1")
+
+	exists, err = engine.Exists(ctx, req.Namespace, req.Name)
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	req.Values = map[string]interface{}{"image": "rancher/k3s:v1.26.0-k3s1"}
+	rel, err = engine.Upgrade(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, 2, rel.Version)
+	assert.Contains(t, rel.Manifest, "rancher/k3s:v1.26.0-k3s1")
+
+	rel, err = engine.Get(ctx, req.Namespace, req.Name)
+	require.NoError(t, err)
+	assert.Equal(t, 2, rel.Version)
+	assert.Equal(t, req.Values, rel.Config)
+
+	require.NoError(t, engine.Uninstall(ctx, req.Namespace, req.Name))
+
+	_, err = engine.Get(ctx, req.Namespace, req.Name)
+	assert.True(t, IsReleaseNotFound(err))
+
+	exists, err = engine.Exists(ctx, req.Namespace, req.Name)
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestEngine_ExistsMatchesExactName(t *testing.T) {
+	ctx := context.Background()
+	engine := newTestEngine(t)
+
+	_, err := engine.Install(ctx, ReleaseRequest{
+		Name:      "test-vc-2",
+		Namespace: "default",
+		Chart:     ChartRef{Name: writeTestChart(t)},
+	})
+	require.NoError(t, err)
+
+	exists, err := engine.Exists(ctx, "default", "test-vc")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestEngine_UninstallNotFound(t *testing.T) {
+	engine := newTestEngine(t)
+
+	err := engine.Uninstall(context.Background(), "default", "missing")
+	require.Error(t, err)
+	assert.True(t, IsReleaseNotFound(err))
+}
+
+func TestEngine_UninstallHonorsContext(t *testing.T) {
+	engine := newTestEngine(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := engine.Uninstall(ctx, "default", "test-vc")
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestEngine_InstallFromArchive(t *testing.T) {
+	chrt, err := loader.Load(writeTestChart(t))
+	require.NoError(t, err)
+	path, err := chartutil.Save(chrt, t.TempDir())
+	require.NoError(t, err)
+	archive, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	// The repository is unreachable, so the chart must come from the archive
+	rel, err := newTestEngine(t).Install(context.Background(), ReleaseRequest{
+		Name:      "test-vc",
+		Namespace: "default",
+		Chart:     ChartRef{RepoURL: "https://127.0.0.1:1", Name: "test-chart", Archive: archive},
+		Values:    map[string]interface{}{"image": "rancher/k3s:v1.25.0-k3s1"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "test-chart", rel.Chart.Name())
+
+	_, err = newTestEngine(t).Install(context.Background(), ReleaseRequest{
+		Name:      "test-vc",
+		Namespace: "default",
+		Chart:     ChartRef{Archive: []byte("not a chart")},
+	})
+	assert.Error(t, err)
+}
+
+func TestEngine_RollbackAndHistory(t *testing.T) {
+	ctx := context.Background()
+	engine := newTestEngine(t)
+	req := ReleaseRequest{
+		Name:      "test-vc",
+		Namespace: "default",
+		Chart:     ChartRef{Name: writeTestChart(t)},
+		Values:    map[string]interface{}{"image": "rancher/k3s:v1.25.0-k3s1"},
+		Options:   OperationOptions{MaxHistory: 3},
+	}
+
+	_, err := engine.Install(ctx, req)
+	require.NoError(t, err)
+	for _, image := range []string{"rancher/k3s:v1.26.0-k3s1", "rancher/k3s:v1.27.0-k3s1"} {
+		req.Values = map[string]interface{}{"image": image}
+		_, err = engine.Upgrade(ctx, req)
+		require.NoError(t, err)
+	}
+
+	rel, err := engine.Rollback(ctx, RollbackRequest{
+		Name:      req.Name,
+		Namespace: req.Namespace,
+		Revision:  1,
+		Options:   req.Options,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 4, rel.Version)
+	assert.Contains(t, rel.Manifest, "rancher/k3s:v1.25.0-k3s1")
+
+	// MaxHistory prunes the oldest revision
+	history, err := engine.History(ctx, req.Namespace, req.Name)
+	require.NoError(t, err)
+	versions := make([]int, 0, len(history))
+	for _, rel := range history {
+		versions = append(versions, rel.Version)
+	}
+	assert.Equal(t, []int{2, 3, 4}, versions)
+
+	_, err = engine.Rollback(ctx, RollbackRequest{Name: req.Name, Namespace: req.Namespace, Revision: 1})
+	assert.Error(t, err)
+
+	_, err = engine.History(ctx, req.Namespace, "missing")
+	assert.True(t, IsReleaseNotFound(err))
+}
+
+func TestEngine_DeleteRevision(t *testing.T) {
+	ctx := context.Background()
+	engine := newTestEngine(t)
+	req := ReleaseRequest{
+		Name:      "test-vc",
+		Namespace: "default",
+		Chart:     ChartRef{Name: writeTestChart(t)},
+		Values:    map[string]interface{}{"image": "rancher/k3s:v1.25.0-k3s1"},
+	}
+
+	installed, err := engine.Install(ctx, req)
+	require.NoError(t, err)
+
+	// Simulate an upgrade that was interrupted before it completed
+	cfg, err := engine.newConfiguration(ctx, req.Namespace)
+	require.NoError(t, err)
+	pending := *installed
+	pending.Info = &release.Info{Status: release.StatusPendingUpgrade}
+	pending.Version = 2
+	require.NoError(t, cfg.Releases.Create(&pending))
+
+	_, err = engine.Upgrade(ctx, req)
+	assert.ErrorContains(t, err, "another operation")
+
+	require.NoError(t, engine.DeleteRevision(ctx, req.Namespace, req.Name, 2))
+	rel, err := engine.Get(ctx, req.Namespace, req.Name)
+	require.NoError(t, err)
+	assert.Equal(t, 1, rel.Version)
+
+	_, err = engine.Upgrade(ctx, req)
+	assert.NoError(t, err)
+
+	err = engine.DeleteRevision(ctx, req.Namespace, req.Name, 7)
+	assert.True(t, IsReleaseNotFound(err))
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// restClientGetter adapts a rest.Config to the genericclioptions.RESTClientGetter
+// interface that the Helm action configuration expects. The discovery client
+// and REST mapper are shared between getters so that every release operation
+// does not have to rediscover the API surface of the cluster.
+type restClientGetter struct {
+	config    *rest.Config
+	namespace string
+	discovery discovery.CachedDiscoveryInterface
+	mapper    meta.RESTMapper
+}
+
+// newRESTClientGetter returns a getter bound to the given namespace.
+func newRESTClientGetter(config *rest.Config, namespace string, dc discovery.CachedDiscoveryInterface, mapper meta.RESTMapper) *restClientGetter {
+	return &restClientGetter{
+		config:    config,
+		namespace: namespace,
+		discovery: dc,
+		mapper:    mapper,
+	}
+}
+
+// newDiscovery builds a memory cached discovery client and a deferred REST
+// mapper on top of it.
+func newDiscovery(config *rest.Config) (discovery.CachedDiscoveryInterface, meta.RESTMapper, error) {
+	dc, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return nil, nil, err
+	}
+	cached := memory.NewMemCacheClient(dc)
+	return cached, restmapper.NewDeferredDiscoveryRESTMapper(cached), nil
+}
+
+// ToRESTConfig returns a copy of the underlying rest.Config.
+func (g *restClientGetter) ToRESTConfig() (*rest.Config, error) {
+	return rest.CopyConfig(g.config), nil
+}
+
+// ToDiscoveryClient returns the shared cached discovery client.
+func (g *restClientGetter) ToDiscoveryClient() (discovery.CachedDiscoveryInterface, error) {
+	return g.discovery, nil
+}
+
+// ToRESTMapper returns the shared REST mapper.
+func (g *restClientGetter) ToRESTMapper() (meta.RESTMapper, error) {
+	return g.mapper, nil
+}
+
+// ToRawKubeConfigLoader returns a client config that only carries the
+// namespace override, since the connection details come from the rest.Config.
+func (g *restClientGetter) ToRawKubeConfigLoader() clientcmd.ClientConfig {
+	return clientcmd.NewDefaultClientConfig(*clientcmdapi.NewConfig(), &clientcmd.ConfigOverrides{
+		Context: clientcmdapi.Context{Namespace: g.namespace},
+	})
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package hibernation evaluates the sleep and wake schedules of
+// VirtualClusters.
+package hibernation
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// lookback bounds the search for the last time a schedule fired, so
+// schedules that fire less than once a week are only considered for a week.
+const lookback = 7 * 24 * time.Hour
+
+// Schedule is a pair of cron schedules putting a VirtualCluster to sleep and
+// waking it up, evaluated in a time zone.
+type Schedule struct {
+	sleep    cron.Schedule
+	wake     cron.Schedule
+	location *time.Location
+}
+
+// Parse parses the standard cron expressions of the sleep and wake schedules
+// and the IANA name of the time zone. An empty time zone means UTC.
+func Parse(sleep, wake, timeZone string) (*Schedule, error) {
+	location := time.UTC
+	if timeZone != "" {
+		var err error
+		if location, err = time.LoadLocation(timeZone); err != nil {
+			return nil, fmt.Errorf("invalid time zone %q: %w", timeZone, err)
+		}
+	}
+
+	s := &Schedule{location: location}
+	var err error
+	if s.sleep, err = cron.ParseStandard(sleep); err != nil {
+		return nil, fmt.Errorf("invalid sleep schedule %q: %w", sleep, err)
+	}
+	if s.wake, err = cron.ParseStandard(wake); err != nil {
+		return nil, fmt.Errorf("invalid wake schedule %q: %w", wake, err)
+	}
+	return s, nil
+}
+
+// Asleep reports whether the sleep schedule fired more recently than the wake
+// schedule at now.
+func (s *Schedule) Asleep(now time.Time) bool {
+	now = now.In(s.location)
+	return last(s.sleep, now).After(last(s.wake, now))
+}
+
+// Next returns the next time one of the schedules fires after now, or the
+// zero time if neither ever fires again.
+func (s *Schedule) Next(now time.Time) time.Time {
+	now = now.In(s.location)
+	next := s.sleep.Next(now)
+	if wake := s.wake.Next(now); !wake.IsZero() && (next.IsZero() || wake.Before(next)) {
+		next = wake
+	}
+	return next
+}
+
+// last returns the last time the schedule fired at or before now within the
+// lookback, or the zero time.
+func last(schedule cron.Schedule, now time.Time) time.Time {
+	var last time.Time
+	for t := schedule.Next(now.Add(-lookback)); !t.IsZero() && !t.After(now); t = schedule.Next(t) {
+		last = t
+	}
+	return last
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hibernation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		sleep    string
+		wake     string
+		timeZone string
+		wantErr  string
+	}{
+		{name: "valid schedules", sleep: "0 20 * * 1-5", wake: "0 7 * * 1-5"},
+		{name: "descriptors and a time zone", sleep: "@midnight", wake: "@hourly", timeZone: "Europe/Berlin"},
+		{name: "invalid sleep schedule", sleep: "0 25 * * *", wake: "0 7 * * *", wantErr: "invalid sleep schedule"},
+		{name: "missing wake schedule", sleep: "0 20 * * *", wantErr: "invalid wake schedule"},
+		{name: "unknown time zone", sleep: "0 20 * * *", wake: "0 7 * * *", timeZone: "Mars/Olympus_Mons", wantErr: "invalid time zone"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.sleep, tt.wake, tt.timeZone)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestSchedule_Asleep(t *testing.T) {
+	// Weekdays 20:00 to 07:00 in Berlin, which is UTC+2 in June
+	schedule, err := Parse("0 20 * * 1-5", "0 7 * * 1-5", "Europe/Berlin")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		now       time.Time
+		wantSleep bool
+		wantNext  time.Time
+	}{
+		{
+			name:      "during the working day",
+			now:       time.Date(2025, 6, 11, 10, 0, 0, 0, time.UTC),
+			wantSleep: false,
+			wantNext:  time.Date(2025, 6, 11, 18, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "at night",
+			now:       time.Date(2025, 6, 11, 23, 0, 0, 0, time.UTC),
+			wantSleep: true,
+			wantNext:  time.Date(2025, 6, 12, 5, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "over the weekend",
+			now:       time.Date(2025, 6, 14, 12, 0, 0, 0, time.UTC),
+			wantSleep: true,
+			wantNext:  time.Date(2025, 6, 16, 5, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "at the wake time",
+			now:       time.Date(2025, 6, 16, 5, 0, 0, 0, time.UTC),
+			wantSleep: false,
+			wantNext:  time.Date(2025, 6, 16, 18, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantSleep, schedule.Asleep(tt.now))
+			assert.True(t, tt.wantNext.Equal(schedule.Next(tt.now)), "next is %s", schedule.Next(tt.now))
+		})
+	}
+}
+
+func TestSchedule_AsleepWithoutRecentActivations(t *testing.T) {
+	// Neither schedule fired within the lookback
+	schedule, err := Parse("0 0 1 1 *", "0 0 1 7 *", "")
+	require.NoError(t, err)
+
+	assert.False(t, schedule.Asleep(time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)))
+}
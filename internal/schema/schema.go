@@ -0,0 +1,103 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package schema validates VirtualCluster values against the JSON schema of
+// the vcluster chart.
+package schema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// Key is the key holding the schema in the schema ConfigMap.
+const Key = "values.schema.json"
+
+// ConfigMapName returns the name of the ConfigMap caching the schema of the
+// chart version.
+func ConfigMapName(version string) string {
+	return fmt.Sprintf("vcluster-schema-%s", strings.ReplaceAll(version, ".", "-"))
+}
+
+// Violation is a value failing the schema.
+type Violation struct {
+	// Path is the JSON path of the value
+	Path string
+	// Message describes the violation
+	Message string
+}
+
+// ValidationError is returned when values fail the schema.
+type ValidationError struct {
+	Violations []Violation
+}
+
+func (e *ValidationError) Error() string {
+	items := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		items = append(items, fmt.Sprintf("%s: %s", v.Path, v.Message))
+	}
+	return fmt.Sprintf("schema validation errors: %s", strings.Join(items, "; "))
+}
+
+// Paths returns the JSON paths of the failing values.
+func (e *ValidationError) Paths() []string {
+	paths := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		paths = append(paths, v.Path)
+	}
+	return paths
+}
+
+// AsValidationError returns the ValidationError wrapped in err.
+func AsValidationError(err error) (*ValidationError, bool) {
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		return validationErr, true
+	}
+	return nil, false
+}
+
+// Validate validates values against the schema. A *ValidationError is
+// returned if the values don't match the schema.
+func Validate(schema string, values map[string]interface{}) error {
+	document, err := json.Marshal(values)
+	if err != nil {
+		return fmt.Errorf("failed to marshal values: %w", err)
+	}
+
+	result, err := gojsonschema.Validate(gojsonschema.NewStringLoader(schema), gojsonschema.NewBytesLoader(document))
+	if err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+	if result.Valid() {
+		return nil
+	}
+
+	validationErr := &ValidationError{}
+	for _, desc := range result.Errors() {
+		path := "$"
+		if field := desc.Field(); field != gojsonschema.STRING_CONTEXT_ROOT {
+			path = "$." + field
+		}
+		validationErr.Violations = append(validationErr.Violations, Violation{Path: path, Message: desc.Description()})
+	}
+	return validationErr
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSchema = `{
+	"type": "object",
+	"required": ["controlPlane"],
+	"properties": {
+		"controlPlane": {
+			"type": "object",
+			"properties": {
+				"service": {
+					"type": "object",
+					"properties": {
+						"type": {"enum": ["ClusterIP", "NodePort", "LoadBalancer"]}
+					}
+				}
+			}
+		},
+		"telemetry": {
+			"type": "object",
+			"properties": {"enabled": {"type": "boolean"}}
+		}
+	}
+}`
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		values    map[string]interface{}
+		wantPaths []string
+	}{
+		{
+			name: "valid values",
+			values: map[string]interface{}{
+				"controlPlane": map[string]interface{}{"service": map[string]interface{}{"type": "NodePort"}},
+			},
+		},
+		{
+			name:      "missing required key",
+			values:    map[string]interface{}{},
+			wantPaths: []string{"$"},
+		},
+		{
+			name: "invalid enum and type",
+			values: map[string]interface{}{
+				"controlPlane": map[string]interface{}{"service": map[string]interface{}{"type": "Invalid"}},
+				"telemetry":    map[string]interface{}{"enabled": "no"},
+			},
+			wantPaths: []string{"$.controlPlane.service.type", "$.telemetry.enabled"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(testSchema, tt.values)
+			if tt.wantPaths == nil {
+				assert.NoError(t, err)
+				return
+			}
+
+			validationErr, ok := AsValidationError(fmt.Errorf("wrapped: %w", err))
+			require.True(t, ok)
+			assert.ElementsMatch(t, tt.wantPaths, validationErr.Paths())
+			assert.Contains(t, err.Error(), "schema validation errors")
+		})
+	}
+}
+
+func TestValidate_InvalidSchema(t *testing.T) {
+	err := Validate(`{"type": 1}`, map[string]interface{}{})
+	require.Error(t, err)
+
+	_, ok := AsValidationError(err)
+	assert.False(t, ok)
+}
+
+func TestConfigMapName(t *testing.T) {
+	assert.Equal(t, "vcluster-schema-v0-24-1", ConfigMapName("v0.24.1"))
+}
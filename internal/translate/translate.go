@@ -0,0 +1,288 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package translate converts VirtualCluster values written for older vcluster
+// charts to the layout expected by the chart version being deployed.
+package translate
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"helm.sh/helm/v3/pkg/chartutil"
+)
+
+// ChangeType is the kind of change made to a key during translation.
+type ChangeType string
+
+const (
+	// Renamed means the value was moved to a new path.
+	Renamed ChangeType = "Renamed"
+	// Dropped means the key is not supported by the chart and was removed.
+	Dropped ChangeType = "Dropped"
+)
+
+// Change describes a key changed during translation.
+type Change struct {
+	Type ChangeType
+	// Path is the dot notated path of the key in the input values
+	Path string
+	// NewPath is the path a renamed value was moved to
+	NewPath string
+}
+
+// String returns a human readable description of the change.
+func (c Change) String() string {
+	if c.Type == Renamed {
+		return fmt.Sprintf("%s -> %s", c.Path, c.NewPath)
+	}
+	return c.Path
+}
+
+// Rule moves the value at a legacy path to its new path.
+type Rule struct {
+	// From is the dot notated path in the legacy values
+	From string
+	// To is the dot notated path in the chart values
+	To string
+	// Convert optionally converts the value. Returning false drops the value.
+	Convert func(interface{}) (interface{}, bool)
+}
+
+// RuleSet is the translation applied for a range of chart versions.
+type RuleSet struct {
+	// MinVersion is the first chart version the rule set applies to
+	MinVersion *semver.Version
+	// Rules are applied in order
+	Rules []Rule
+	// RootFields are the top level keys accepted by the chart. Nil accepts
+	// every key.
+	RootFields []string
+}
+
+// Result is the outcome of a translation.
+type Result struct {
+	Values  map[string]interface{}
+	Changes []Change
+}
+
+// Renamed returns the keys that were moved to a new path.
+func (r *Result) Renamed() []Change {
+	return r.filter(Renamed)
+}
+
+// Dropped returns the keys that were removed.
+func (r *Result) Dropped() []Change {
+	return r.filter(Dropped)
+}
+
+func (r *Result) filter(t ChangeType) []Change {
+	var changes []Change
+	for _, c := range r.Changes {
+		if c.Type == t {
+			changes = append(changes, c)
+		}
+	}
+	return changes
+}
+
+// ruleSets are ordered from the newest to the oldest chart version.
+var ruleSets = []RuleSet{
+	{
+		// v0.20 moved the control plane configuration below controlPlane
+		MinVersion: semver.MustParse("0.20.0"),
+		Rules: []Rule{
+			{From: "vcluster.image", To: "controlPlane.distro.k3s.image", Convert: splitImage},
+			{From: "vcluster.extraArgs", To: "controlPlane.distro.k3s.extraArgs"},
+			{From: "vcluster.env", To: "controlPlane.distro.k3s.env"},
+			{From: "vcluster.resources", To: "controlPlane.distro.k3s.resources"},
+			{From: "service.type", To: "controlPlane.service.spec.type"},
+			{From: "storage.persistence", To: "controlPlane.statefulSet.persistence.volumeClaim.enabled"},
+			{From: "storage.size", To: "controlPlane.statefulSet.persistence.volumeClaim.size"},
+			{From: "storage.className", To: "controlPlane.statefulSet.persistence.volumeClaim.storageClass"},
+			{From: "telemetry.disabled", To: "telemetry.enabled", Convert: negate},
+		},
+		RootFields: []string{
+			"controlPlane", "experimental", "exportKubeConfig", "external",
+			"global", "integrations", "networking", "plugin", "plugins",
+			"policies", "pro", "rbac", "serviceCIDR", "sleepMode", "sync", "telemetry",
+		},
+	},
+	{
+		// Charts before v0.20 take the legacy values as they are
+		MinVersion: semver.MustParse("0.0.0"),
+	},
+}
+
+// ForVersion returns the rule set for the chart version. Versions that can't
+// be parsed, such as constraints, get the newest rule set.
+func ForVersion(version string) *RuleSet {
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		return &ruleSets[0]
+	}
+	for i := range ruleSets {
+		if !v.LessThan(ruleSets[i].MinVersion) {
+			return &ruleSets[i]
+		}
+	}
+	return &ruleSets[len(ruleSets)-1]
+}
+
+// Values translates values for the chart version. The input is not modified.
+func Values(values map[string]interface{}, version string) *Result {
+	return ForVersion(version).Translate(values)
+}
+
+// Translate applies the rule set to values. The input is not modified.
+func (s *RuleSet) Translate(values map[string]interface{}) *Result {
+	in := deepCopy(values)
+	translated := make(map[string]interface{})
+	result := &Result{}
+
+	for _, rule := range s.Rules {
+		v, ok := remove(in, rule.From)
+		if !ok {
+			continue
+		}
+		if rule.Convert != nil {
+			if v, ok = rule.Convert(v); !ok {
+				result.Changes = append(result.Changes, Change{Type: Dropped, Path: rule.From})
+				continue
+			}
+		}
+		set(translated, rule.To, v)
+		result.Changes = append(result.Changes, Change{Type: Renamed, Path: rule.From, NewPath: rule.To})
+	}
+
+	keys := make([]string, 0, len(in))
+	for k := range in {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	kept := make(map[string]interface{})
+	for _, k := range keys {
+		if !s.allowed(k) {
+			for _, path := range leaves(k, in[k]) {
+				result.Changes = append(result.Changes, Change{Type: Dropped, Path: path})
+			}
+			continue
+		}
+		kept[k] = in[k]
+	}
+
+	// Keys written for the current chart take precedence over translated ones
+	result.Values = chartutil.MergeTables(kept, translated)
+	return result
+}
+
+func (s *RuleSet) allowed(key string) bool {
+	if s.RootFields == nil {
+		return true
+	}
+	for _, f := range s.RootFields {
+		if f == key {
+			return true
+		}
+	}
+	return false
+}
+
+// splitImage converts an image reference to the repository and tag used by
+// the chart.
+func splitImage(v interface{}) (interface{}, bool) {
+	image, ok := v.(string)
+	if !ok || image == "" {
+		return nil, false
+	}
+	repository, tag := image, ""
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		repository, tag = image[:i], image[i+1:]
+	}
+	out := map[string]interface{}{"repository": repository}
+	if tag != "" {
+		out["tag"] = tag
+	}
+	return out, true
+}
+
+// negate inverts a boolean.
+func negate(v interface{}) (interface{}, bool) {
+	b, ok := v.(bool)
+	return !b, ok
+}
+
+// remove deletes the value at path and prunes parents left empty.
+func remove(values map[string]interface{}, path string) (interface{}, bool) {
+	key, rest, nested := strings.Cut(path, ".")
+	if !nested {
+		v, ok := values[key]
+		delete(values, key)
+		return v, ok
+	}
+	child, ok := values[key].(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	v, ok := remove(child, rest)
+	if ok && len(child) == 0 {
+		delete(values, key)
+	}
+	return v, ok
+}
+
+// set stores v at path, creating parents as needed.
+func set(values map[string]interface{}, path string, v interface{}) {
+	key, rest, nested := strings.Cut(path, ".")
+	if !nested {
+		values[key] = v
+		return
+	}
+	child, ok := values[key].(map[string]interface{})
+	if !ok {
+		child = make(map[string]interface{})
+		values[key] = child
+	}
+	set(child, rest, v)
+}
+
+// leaves returns the paths of all leaf values below prefix.
+func leaves(prefix string, v interface{}) []string {
+	m, ok := v.(map[string]interface{})
+	if !ok || len(m) == 0 {
+		return []string{prefix}
+	}
+	var paths []string
+	for k, child := range m {
+		paths = append(paths, leaves(prefix+"."+k, child)...)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func deepCopy(values map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(values))
+	for k, v := range values {
+		if m, ok := v.(map[string]interface{}); ok {
+			v = deepCopy(m)
+		}
+		out[k] = v
+	}
+	return out
+}
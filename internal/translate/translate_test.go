@@ -0,0 +1,189 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package translate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValues(t *testing.T) {
+	tests := []struct {
+		name        string
+		version     string
+		values      map[string]interface{}
+		want        map[string]interface{}
+		wantChanges []Change
+	}{
+		{
+			name:    "legacy keys are moved below controlPlane",
+			version: "v0.24.1",
+			values: map[string]interface{}{
+				"vcluster": map[string]interface{}{
+					"image":     "rancher/k3s:v1.25.0-k3s1",
+					"extraArgs": []interface{}{"--disable=traefik"},
+				},
+				"service":   map[string]interface{}{"type": "NodePort"},
+				"storage":   map[string]interface{}{"persistence": false},
+				"telemetry": map[string]interface{}{"disabled": true},
+			},
+			want: map[string]interface{}{
+				"controlPlane": map[string]interface{}{
+					"distro": map[string]interface{}{
+						"k3s": map[string]interface{}{
+							"image": map[string]interface{}{
+								"repository": "rancher/k3s",
+								"tag":        "v1.25.0-k3s1",
+							},
+							"extraArgs": []interface{}{"--disable=traefik"},
+						},
+					},
+					"service": map[string]interface{}{
+						"spec": map[string]interface{}{"type": "NodePort"},
+					},
+					"statefulSet": map[string]interface{}{
+						"persistence": map[string]interface{}{
+							"volumeClaim": map[string]interface{}{"enabled": false},
+						},
+					},
+				},
+				"telemetry": map[string]interface{}{"enabled": false},
+			},
+			wantChanges: []Change{
+				{Type: Renamed, Path: "vcluster.image", NewPath: "controlPlane.distro.k3s.image"},
+				{Type: Renamed, Path: "vcluster.extraArgs", NewPath: "controlPlane.distro.k3s.extraArgs"},
+				{Type: Renamed, Path: "service.type", NewPath: "controlPlane.service.spec.type"},
+				{Type: Renamed, Path: "storage.persistence", NewPath: "controlPlane.statefulSet.persistence.volumeClaim.enabled"},
+				{Type: Renamed, Path: "telemetry.disabled", NewPath: "telemetry.enabled"},
+			},
+		},
+		{
+			name:    "unknown keys are dropped and reported",
+			version: "0.24.1",
+			values: map[string]interface{}{
+				"sync":     map[string]interface{}{"toHost": map[string]interface{}{"ingresses": map[string]interface{}{"enabled": true}}},
+				"vcluster": map[string]interface{}{"command": []interface{}{"/bin/k3s"}},
+				"unknown":  "value",
+			},
+			want: map[string]interface{}{
+				"sync": map[string]interface{}{"toHost": map[string]interface{}{"ingresses": map[string]interface{}{"enabled": true}}},
+			},
+			wantChanges: []Change{
+				{Type: Dropped, Path: "unknown"},
+				{Type: Dropped, Path: "vcluster.command"},
+			},
+		},
+		{
+			name:    "current keys take precedence over translated ones",
+			version: "v0.24.1",
+			values: map[string]interface{}{
+				"vcluster": map[string]interface{}{"image": "rancher/k3s:v1.25.0-k3s1"},
+				"controlPlane": map[string]interface{}{
+					"distro": map[string]interface{}{
+						"k3s": map[string]interface{}{
+							"image": map[string]interface{}{"tag": "v1.30.2-k3s2"},
+						},
+					},
+				},
+			},
+			want: map[string]interface{}{
+				"controlPlane": map[string]interface{}{
+					"distro": map[string]interface{}{
+						"k3s": map[string]interface{}{
+							"image": map[string]interface{}{
+								"repository": "rancher/k3s",
+								"tag":        "v1.30.2-k3s2",
+							},
+						},
+					},
+				},
+			},
+			wantChanges: []Change{
+				{Type: Renamed, Path: "vcluster.image", NewPath: "controlPlane.distro.k3s.image"},
+			},
+		},
+		{
+			name:    "legacy charts take the values as they are",
+			version: "v0.19.7",
+			values: map[string]interface{}{
+				"vcluster": map[string]interface{}{"image": "rancher/k3s:v1.25.0-k3s1"},
+				"service":  map[string]interface{}{"type": "ClusterIP"},
+			},
+			want: map[string]interface{}{
+				"vcluster": map[string]interface{}{"image": "rancher/k3s:v1.25.0-k3s1"},
+				"service":  map[string]interface{}{"type": "ClusterIP"},
+			},
+		},
+		{
+			name:    "values with an unexpected type are dropped",
+			version: "v0.24.1",
+			values: map[string]interface{}{
+				"telemetry": map[string]interface{}{"disabled": "yes"},
+			},
+			want: map[string]interface{}{},
+			wantChanges: []Change{
+				{Type: Dropped, Path: "telemetry.disabled"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Values(tt.values, tt.version)
+			assert.Equal(t, tt.want, result.Values)
+			assert.Equal(t, tt.wantChanges, result.Changes)
+		})
+	}
+}
+
+func TestValues_DoesNotModifyInput(t *testing.T) {
+	values := map[string]interface{}{
+		"vcluster": map[string]interface{}{"image": "rancher/k3s:v1.25.0-k3s1"},
+	}
+
+	Values(values, "v0.24.1")
+
+	assert.Equal(t, map[string]interface{}{
+		"vcluster": map[string]interface{}{"image": "rancher/k3s:v1.25.0-k3s1"},
+	}, values)
+}
+
+func TestForVersion(t *testing.T) {
+	assert.NotNil(t, ForVersion("v0.20.0").RootFields)
+	assert.NotNil(t, ForVersion("0.24.x").RootFields, "constraints use the newest rule set")
+	assert.Nil(t, ForVersion("v0.19.7").RootFields)
+}
+
+func TestSplitImage(t *testing.T) {
+	tests := []struct {
+		image string
+		want  interface{}
+	}{
+		{"rancher/k3s:v1.25.0-k3s1", map[string]interface{}{"repository": "rancher/k3s", "tag": "v1.25.0-k3s1"}},
+		{"registry.local:5000/rancher/k3s", map[string]interface{}{"repository": "registry.local:5000/rancher/k3s"}},
+		{"registry.local:5000/k3s:v1", map[string]interface{}{"repository": "registry.local:5000/k3s", "tag": "v1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			got, ok := splitImage(tt.image)
+			assert.True(t, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
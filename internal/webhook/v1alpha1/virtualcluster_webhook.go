@@ -0,0 +1,375 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"helm.sh/helm/v3/pkg/chartutil"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	corev1alpha1 "github.com/OpenVirtualCluster/openvirtualcluster-operator/api/v1alpha1"
+	"github.com/OpenVirtualCluster/openvirtualcluster-operator/internal/hibernation"
+	"github.com/OpenVirtualCluster/openvirtualcluster-operator/internal/schema"
+	"github.com/OpenVirtualCluster/openvirtualcluster-operator/internal/translate"
+)
+
+const (
+	// defaultChartName is the name of the chart used when spec.chart.source.name is empty
+	defaultChartName = "vcluster"
+
+	// Standard labels added to every VirtualCluster
+	labelName     = "app.kubernetes.io/name"
+	labelInstance = "app.kubernetes.io/instance"
+	labelVersion  = "app.kubernetes.io/version"
+)
+
+// log is for logging in this package.
+var virtualclusterlog = logf.Log.WithName("virtualcluster-resource")
+
+// SetupVirtualClusterWebhookWithManager registers the webhook for VirtualCluster in the manager.
+// The conversion webhook is registered as well, as v1alpha1 is the hub of the
+// VirtualCluster versions in the scheme of the manager.
+func SetupVirtualClusterWebhookWithManager(mgr ctrl.Manager, defaultValidationMode corev1alpha1.ValidationMode,
+	defaultValues map[string]interface{}) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&corev1alpha1.VirtualCluster{}).
+		WithValidator(&VirtualClusterCustomValidator{
+			Client:                mgr.GetClient(),
+			DefaultValidationMode: defaultValidationMode,
+		}).
+		WithDefaulter(&VirtualClusterCustomDefaulter{
+			DefaultValues: defaultValues,
+		}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-core-openvc-dev-v1alpha1-virtualcluster,mutating=true,failurePolicy=fail,sideEffects=None,groups=core.openvc.dev,resources=virtualclusters,verbs=create;update,versions=v1alpha1,name=mvirtualcluster-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// VirtualClusterCustomDefaulter writes the effective chart version, the
+// default values of the operator and the standard labels into VirtualClusters
+// when they are created or updated, so the stored object shows what is
+// deployed.
+type VirtualClusterCustomDefaulter struct {
+	// DefaultValues are operator-level values merged beneath spec.values
+	DefaultValues map[string]interface{}
+}
+
+var _ webhook.CustomDefaulter = &VirtualClusterCustomDefaulter{}
+
+// Default implements webhook.CustomDefaulter so a webhook will be registered for the type VirtualCluster.
+func (d *VirtualClusterCustomDefaulter) Default(_ context.Context, obj runtime.Object) error {
+	vcluster, ok := obj.(*corev1alpha1.VirtualCluster)
+	if !ok {
+		return fmt.Errorf("expected a VirtualCluster object but got %T", obj)
+	}
+	virtualclusterlog.Info("Defaulting for VirtualCluster", "name", vcluster.GetName())
+
+	// Leave objects being deleted alone so finalizers can always be removed
+	if !vcluster.DeletionTimestamp.IsZero() {
+		return nil
+	}
+
+	// VirtualClusters referencing a class get the chart version of the class
+	if vcluster.Spec.Chart.Version == "" && vcluster.Spec.ClassRef == nil {
+		vcluster.Spec.Chart.Version = corev1alpha1.DefaultChartVersion
+	}
+
+	if err := d.defaultValues(vcluster); err != nil {
+		return err
+	}
+
+	labels := vcluster.GetLabels()
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	if _, ok := labels[labelName]; !ok {
+		labels[labelName] = chartName(vcluster)
+	}
+	if _, ok := labels[labelInstance]; !ok {
+		labels[labelInstance] = vcluster.Name
+	}
+	// Build metadata isn't allowed in label values, and constraints don't
+	// name a single version
+	if _, err := semver.NewVersion(vcluster.Spec.Chart.Version); err == nil {
+		labels[labelVersion] = strings.ReplaceAll(vcluster.Spec.Chart.Version, "+", "_")
+	} else {
+		delete(labels, labelVersion)
+	}
+	vcluster.SetLabels(labels)
+
+	return nil
+}
+
+// defaultValues merges the default values of the operator beneath
+// spec.values. VirtualClusters using valuesFrom or a class are skipped, as
+// the defaults written to spec.values would take precedence over the
+// referenced values; the controller applies the defaults to them when
+// deploying.
+func (d *VirtualClusterCustomDefaulter) defaultValues(vcluster *corev1alpha1.VirtualCluster) error {
+	if len(d.DefaultValues) == 0 || len(vcluster.Spec.ValuesFrom) > 0 || vcluster.Spec.ClassRef != nil {
+		return nil
+	}
+
+	values, err := vcluster.GetValues()
+	if err != nil {
+		// Rejected by the validating webhook
+		return nil
+	}
+
+	raw, err := json.Marshal(chartutil.MergeTables(values, runtime.DeepCopyJSON(d.DefaultValues)))
+	if err != nil {
+		return fmt.Errorf("failed to marshal values: %w", err)
+	}
+	vcluster.Spec.Values = &apiextensionsv1.JSON{Raw: raw}
+	return nil
+}
+
+// +kubebuilder:webhook:path=/validate-core-openvc-dev-v1alpha1-virtualcluster,mutating=false,failurePolicy=fail,sideEffects=None,groups=core.openvc.dev,resources=virtualclusters,verbs=create;update,versions=v1alpha1,name=vvirtualcluster-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// VirtualClusterCustomValidator validates VirtualClusters when they are created or updated.
+type VirtualClusterCustomValidator struct {
+	// Client reads the cached chart schemas
+	Client client.Reader
+	// DefaultValidationMode is used for VirtualClusters that don't set
+	// spec.validation.mode
+	DefaultValidationMode corev1alpha1.ValidationMode
+}
+
+var _ webhook.CustomValidator = &VirtualClusterCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type VirtualCluster.
+func (v *VirtualClusterCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	vcluster, ok := obj.(*corev1alpha1.VirtualCluster)
+	if !ok {
+		return nil, fmt.Errorf("expected a VirtualCluster object but got %T", obj)
+	}
+	virtualclusterlog.Info("Validation for VirtualCluster upon creation", "name", vcluster.GetName())
+
+	return v.validate(ctx, vcluster, nil)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type VirtualCluster.
+func (v *VirtualClusterCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	vcluster, ok := newObj.(*corev1alpha1.VirtualCluster)
+	if !ok {
+		return nil, fmt.Errorf("expected a VirtualCluster object for the newObj but got %T", newObj)
+	}
+	old, ok := oldObj.(*corev1alpha1.VirtualCluster)
+	if !ok {
+		return nil, fmt.Errorf("expected a VirtualCluster object for the oldObj but got %T", oldObj)
+	}
+	virtualclusterlog.Info("Validation for VirtualCluster upon update", "name", vcluster.GetName())
+
+	// Never block metadata changes such as finalizer removal
+	if !vcluster.DeletionTimestamp.IsZero() || equality.Semantic.DeepEqual(old.Spec, vcluster.Spec) {
+		return nil, nil
+	}
+
+	return v.validate(ctx, vcluster, old)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type VirtualCluster.
+func (v *VirtualClusterCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validate checks the VirtualCluster. old is nil on creation.
+func (v *VirtualClusterCustomValidator) validate(ctx context.Context, vcluster, old *corev1alpha1.VirtualCluster) (admission.Warnings, error) {
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+	versionPath := specPath.Child("chart", "version")
+
+	// Whether the chart version exists is only known once the controller
+	// pulls the chart
+	version := vcluster.Spec.Chart.Version
+	if version != "" {
+		if _, err := semver.NewConstraint(version); err != nil {
+			allErrs = append(allErrs, field.Invalid(versionPath, version, "invalid chart version, must be a semantic version or version constraint"))
+		}
+	}
+
+	if hibernationSpec := vcluster.Spec.Hibernation; hibernationSpec != nil && hibernationSpec.SleepSchedule != "" {
+		if _, err := hibernation.Parse(hibernationSpec.SleepSchedule, hibernationSpec.WakeSchedule, hibernationSpec.TimeZone); err != nil {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("hibernation"), "", err.Error()))
+		}
+	}
+
+	if ttl := vcluster.Spec.TTL; ttl != nil && ttl.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("ttl"), ttl.Duration.String(), "must be positive"))
+	}
+	if idleTimeout := vcluster.Spec.IdleTimeout; idleTimeout != nil && idleTimeout.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("idleTimeout"), idleTimeout.Duration.String(), "must be positive"))
+	}
+
+	if old != nil {
+		allErrs = append(allErrs, validateImmutable(old, vcluster, specPath)...)
+	}
+
+	values, err := vcluster.GetValues()
+	if err != nil {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("values"), "", err.Error()))
+	}
+
+	var warnings admission.Warnings
+
+	// The class may be created after the VirtualCluster
+	var class *corev1alpha1.VirtualClusterClass
+	if ref := vcluster.Spec.ClassRef; ref != nil {
+		class = &corev1alpha1.VirtualClusterClass{}
+		err := v.Client.Get(ctx, client.ObjectKey{Name: ref.Name}, class)
+		if apierrors.IsNotFound(err) {
+			warnings = append(warnings, fmt.Sprintf("spec.classRef: VirtualClusterClass %s not found", ref.Name))
+		} else if err != nil {
+			virtualclusterlog.Error(err, "Failed to get VirtualClusterClass", "name", ref.Name)
+		}
+		if err != nil {
+			class = nil
+		}
+	}
+
+	if len(allErrs) == 0 {
+		var schemaWarnings admission.Warnings
+		schemaWarnings, allErrs = v.validateSchema(ctx, vcluster, class, values, specPath)
+		warnings = append(schemaWarnings, warnings...)
+	}
+
+	if len(allErrs) > 0 {
+		return warnings, apierrors.NewInvalid(corev1alpha1.GroupVersion.WithKind("VirtualCluster").GroupKind(), vcluster.Name, allErrs)
+	}
+	return warnings, nil
+}
+
+// validateImmutable rejects changes that can't be applied to an existing release.
+func validateImmutable(old, vcluster *corev1alpha1.VirtualCluster, specPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if oldName, name := chartName(old), chartName(vcluster); oldName != name {
+		allErrs = append(allErrs, field.Forbidden(specPath.Child("chart", "source", "name"),
+			fmt.Sprintf("the chart can't be changed from %s to %s", oldName, name)))
+	}
+
+	oldVersion, oldErr := semver.NewVersion(old.Spec.Chart.Version)
+	version, err := semver.NewVersion(vcluster.Spec.Chart.Version)
+	if oldErr == nil && err == nil && version.LessThan(oldVersion) {
+		allErrs = append(allErrs, field.Forbidden(specPath.Child("chart", "version"),
+			fmt.Sprintf("downgrading the chart from %s to %s is not supported", old.Spec.Chart.Version, vcluster.Spec.Chart.Version)))
+	}
+
+	// Moving the release would leave the deployed one behind
+	if oldNamespace, namespace := old.ReleaseNamespace(), vcluster.ReleaseNamespace(); oldNamespace != namespace {
+		allErrs = append(allErrs, field.Forbidden(specPath.Child("targetNamespace"),
+			fmt.Sprintf("the release can't be moved from namespace %s to %s", oldNamespace, namespace)))
+	}
+
+	return allErrs
+}
+
+// validateSchema validates the values against the schema cached for the chart
+// version. Violations are returned as warnings unless the validation mode is
+// Enforce. The chart version and validation mode fall back to the ones of the
+// class like in the controller. Values referenced by valuesFrom are validated
+// by the controller.
+func (v *VirtualClusterCustomValidator) validateSchema(ctx context.Context, vcluster *corev1alpha1.VirtualCluster, class *corev1alpha1.VirtualClusterClass, values map[string]interface{}, specPath *field.Path) (admission.Warnings, field.ErrorList) {
+	mode := v.DefaultValidationMode
+	version := vcluster.Spec.Chart.Version
+	if class != nil {
+		if class.Spec.Validation != nil && class.Spec.Validation.Mode != "" {
+			mode = class.Spec.Validation.Mode
+		}
+		if version == "" {
+			version = class.Spec.Chart.Version
+		}
+	}
+	if vcluster.Spec.Validation != nil && vcluster.Spec.Validation.Mode != "" {
+		mode = vcluster.Spec.Validation.Mode
+	}
+	if mode == corev1alpha1.ValidationModeDisabled || version == "" {
+		return nil, nil
+	}
+
+	// Enforce can't be honored without a schema, which charts don't have to
+	// ship
+	var missingSchema admission.Warnings
+	if mode == corev1alpha1.ValidationModeEnforce {
+		missingSchema = admission.Warnings{fmt.Sprintf(
+			"spec.validation.mode: no values schema is cached for chart version %s, schema enforcement is skipped if the chart has none", version)}
+	}
+
+	// Schemas are cached for chart versions, not for constraints
+	if _, err := semver.NewVersion(version); err != nil {
+		return missingSchema, nil
+	}
+
+	configMap := &corev1.ConfigMap{}
+	key := client.ObjectKey{Namespace: vcluster.Namespace, Name: schema.ConfigMapName(version)}
+	if err := v.Client.Get(ctx, key, configMap); err != nil {
+		// The schema is fetched by the controller on the first deployment
+		if !apierrors.IsNotFound(err) {
+			virtualclusterlog.Error(err, "Failed to get schema ConfigMap", "name", key.Name)
+		}
+		return missingSchema, nil
+	}
+	schemaData := configMap.Data[schema.Key]
+	if schemaData == "" {
+		return missingSchema, nil
+	}
+
+	err := schema.Validate(schemaData, translate.Values(values, version).Values)
+	validationErr, ok := schema.AsValidationError(err)
+	if !ok {
+		if err != nil {
+			virtualclusterlog.Error(err, "Failed to validate values", "name", vcluster.Name)
+		}
+		return nil, nil
+	}
+
+	if mode == corev1alpha1.ValidationModeEnforce {
+		var allErrs field.ErrorList
+		for _, violation := range validationErr.Violations {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("values"), violation.Path, violation.Message))
+		}
+		return nil, allErrs
+	}
+
+	var warnings admission.Warnings
+	for _, violation := range validationErr.Violations {
+		warnings = append(warnings, fmt.Sprintf("spec.values: %s: %s", violation.Path, violation.Message))
+	}
+	return warnings, nil
+}
+
+// chartName returns the name of the chart deployed for the VirtualCluster.
+func chartName(vcluster *corev1alpha1.VirtualCluster) string {
+	if source := vcluster.Spec.Chart.Source; source != nil && source.Name != "" {
+		return source.Name
+	}
+	return defaultChartName
+}
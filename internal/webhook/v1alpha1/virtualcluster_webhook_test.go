@@ -0,0 +1,396 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	corev1alpha1 "github.com/OpenVirtualCluster/openvirtualcluster-operator/api/v1alpha1"
+)
+
+var _ = Describe("VirtualCluster Webhook", func() {
+	const testSchema = `{
+		"type": "object",
+		"properties": {
+			"controlPlane": {"type": "object", "properties": {
+				"service": {"type": "object", "properties": {
+					"spec": {"type": "object", "properties": {
+						"type": {"enum": ["ClusterIP", "NodePort", "LoadBalancer"]}
+					}}
+				}}
+			}}
+		}
+	}`
+
+	var (
+		ctx       context.Context
+		validator *VirtualClusterCustomValidator
+		vcluster  *corev1alpha1.VirtualCluster
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		s := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(s)).To(Succeed())
+		Expect(corev1alpha1.AddToScheme(s)).To(Succeed())
+
+		schemaConfigMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "vcluster-schema-v0-24-1", Namespace: "default"},
+			Data:       map[string]string{"values.schema.json": testSchema},
+		}
+
+		validator = &VirtualClusterCustomValidator{
+			Client:                fake.NewClientBuilder().WithScheme(s).WithObjects(schemaConfigMap).Build(),
+			DefaultValidationMode: corev1alpha1.ValidationModeWarn,
+		}
+
+		vcluster = &corev1alpha1.VirtualCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+			Spec: corev1alpha1.VirtualClusterSpec{
+				Chart: corev1alpha1.HelmChart{Version: "v0.24.1"},
+			},
+		}
+		setValues(vcluster, map[string]interface{}{
+			"controlPlane": map[string]interface{}{
+				"service": map[string]interface{}{"spec": map[string]interface{}{"type": "NodePort"}},
+			},
+		})
+	})
+
+	Context("When creating a VirtualCluster", func() {
+		It("Should admit valid values", func() {
+			warnings, err := validator.ValidateCreate(ctx, vcluster)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("Should deny values that aren't valid JSON", func() {
+			vcluster.Spec.Values = &apiextensionsv1.JSON{Raw: []byte("{invalid")}
+
+			_, err := validator.ValidateCreate(ctx, vcluster)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.values"))
+		})
+
+		It("Should deny an invalid chart version", func() {
+			vcluster.Spec.Chart.Version = "latest"
+
+			_, err := validator.ValidateCreate(ctx, vcluster)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("invalid chart version"))
+		})
+
+		It("Should admit a chart version constraint", func() {
+			vcluster.Spec.Chart.Version = "~0.24.0"
+			vcluster.Spec.Validation = &corev1alpha1.ValidationSpec{Mode: corev1alpha1.ValidationModeEnforce}
+			setValues(vcluster, map[string]interface{}{"service": map[string]interface{}{"type": "Invalid"}})
+
+			warnings, err := validator.ValidateCreate(ctx, vcluster)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf(ContainSubstring("no values schema is cached for chart version ~0.24.0")))
+		})
+
+		It("Should deny an invalid hibernation schedule", func() {
+			vcluster.Spec.Hibernation = &corev1alpha1.HibernationSpec{
+				SleepSchedule: "0 20 * * 1-5",
+				WakeSchedule:  "0 7 * * 1-5",
+				TimeZone:      "Mars/Olympus_Mons",
+			}
+
+			_, err := validator.ValidateCreate(ctx, vcluster)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("invalid time zone"))
+
+			vcluster.Spec.Hibernation.TimeZone = "Europe/Berlin"
+			_, err = validator.ValidateCreate(ctx, vcluster)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should deny a TTL or idle timeout that isn't positive", func() {
+			vcluster.Spec.TTL = &metav1.Duration{Duration: -time.Hour}
+			vcluster.Spec.IdleTimeout = &metav1.Duration{}
+
+			_, err := validator.ValidateCreate(ctx, vcluster)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.ttl"))
+			Expect(err.Error()).To(ContainSubstring("spec.idleTimeout"))
+
+			vcluster.Spec.TTL.Duration = time.Hour
+			vcluster.Spec.IdleTimeout.Duration = 30 * time.Minute
+			_, err = validator.ValidateCreate(ctx, vcluster)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should warn about a missing class", func() {
+			vcluster.Spec.ClassRef = &corev1alpha1.ClassReference{Name: "small"}
+
+			warnings, err := validator.ValidateCreate(ctx, vcluster)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf("spec.classRef: VirtualClusterClass small not found"))
+
+			Expect(validator.Client.(client.Client).Create(ctx, &corev1alpha1.VirtualClusterClass{
+				ObjectMeta: metav1.ObjectMeta{Name: "small"},
+			})).To(Succeed())
+			warnings, err = validator.ValidateCreate(ctx, vcluster)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("Should warn about schema violations in Warn mode", func() {
+			setValues(vcluster, map[string]interface{}{"service": map[string]interface{}{"type": "Invalid"}})
+
+			warnings, err := validator.ValidateCreate(ctx, vcluster)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf(ContainSubstring("$.controlPlane.service.spec.type")))
+		})
+
+		It("Should deny schema violations in Enforce mode", func() {
+			setValues(vcluster, map[string]interface{}{"service": map[string]interface{}{"type": "Invalid"}})
+			vcluster.Spec.Validation = &corev1alpha1.ValidationSpec{Mode: corev1alpha1.ValidationModeEnforce}
+
+			_, err := validator.ValidateCreate(ctx, vcluster)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("$.controlPlane.service.spec.type"))
+		})
+
+		It("Should use the validation mode and chart version of the class", func() {
+			Expect(validator.Client.(client.Client).Create(ctx, &corev1alpha1.VirtualClusterClass{
+				ObjectMeta: metav1.ObjectMeta{Name: "strict"},
+				Spec: corev1alpha1.VirtualClusterClassSpec{
+					Chart:      corev1alpha1.HelmChart{Version: "v0.24.1"},
+					Validation: &corev1alpha1.ValidationSpec{Mode: corev1alpha1.ValidationModeEnforce},
+				},
+			})).To(Succeed())
+			vcluster.Spec.Chart.Version = ""
+			vcluster.Spec.ClassRef = &corev1alpha1.ClassReference{Name: "strict"}
+			setValues(vcluster, map[string]interface{}{"service": map[string]interface{}{"type": "Invalid"}})
+
+			_, err := validator.ValidateCreate(ctx, vcluster)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("$.controlPlane.service.spec.type"))
+
+			// The mode of the VirtualCluster takes precedence
+			vcluster.Spec.Validation = &corev1alpha1.ValidationSpec{Mode: corev1alpha1.ValidationModeWarn}
+			warnings, err := validator.ValidateCreate(ctx, vcluster)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf(ContainSubstring("$.controlPlane.service.spec.type")))
+		})
+
+		It("Should skip schema validation in Disabled mode", func() {
+			setValues(vcluster, map[string]interface{}{"service": map[string]interface{}{"type": "Invalid"}})
+			vcluster.Spec.Validation = &corev1alpha1.ValidationSpec{Mode: corev1alpha1.ValidationModeDisabled}
+
+			warnings, err := validator.ValidateCreate(ctx, vcluster)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("Should admit values when the schema isn't cached yet", func() {
+			vcluster.Spec.Chart.Version = "v0.25.0"
+			vcluster.Spec.Validation = &corev1alpha1.ValidationSpec{Mode: corev1alpha1.ValidationModeEnforce}
+			setValues(vcluster, map[string]interface{}{"service": map[string]interface{}{"type": "Invalid"}})
+
+			warnings, err := validator.ValidateCreate(ctx, vcluster)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf(ContainSubstring("schema enforcement is skipped if the chart has none")))
+
+			// Only Enforce mode depends on the schema
+			vcluster.Spec.Validation.Mode = corev1alpha1.ValidationModeWarn
+			warnings, err = validator.ValidateCreate(ctx, vcluster)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+	})
+
+	Context("When updating a VirtualCluster", func() {
+		It("Should deny changing the chart", func() {
+			updated := vcluster.DeepCopy()
+			updated.Spec.Chart.Source = &corev1alpha1.ChartSource{Name: "other"}
+
+			_, err := validator.ValidateUpdate(ctx, vcluster, updated)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.chart.source.name"))
+		})
+
+		It("Should deny downgrading the chart", func() {
+			updated := vcluster.DeepCopy()
+			updated.Spec.Chart.Version = "v0.23.0"
+
+			_, err := validator.ValidateUpdate(ctx, vcluster, updated)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("downgrading"))
+		})
+
+		It("Should deny moving the release to another namespace", func() {
+			updated := vcluster.DeepCopy()
+			updated.Spec.TargetNamespace = &corev1alpha1.TargetNamespaceSpec{Name: "workloads"}
+
+			_, err := validator.ValidateUpdate(ctx, vcluster, updated)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.targetNamespace"))
+		})
+
+		It("Should admit changing the labels of the target namespace", func() {
+			vcluster.Spec.TargetNamespace = &corev1alpha1.TargetNamespaceSpec{}
+			updated := vcluster.DeepCopy()
+			updated.Spec.TargetNamespace.Labels = map[string]string{"team": "a"}
+			updated.Spec.TargetNamespace.PodSecurity = corev1alpha1.PodSecurityLevelRestricted
+
+			_, err := validator.ValidateUpdate(ctx, vcluster, updated)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should admit upgrading the chart", func() {
+			updated := vcluster.DeepCopy()
+			updated.Spec.Chart.Version = "v0.25.0"
+
+			_, err := validator.ValidateUpdate(ctx, vcluster, updated)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should admit metadata changes of invalid VirtualClusters", func() {
+			vcluster.Spec.Chart.Version = "latest"
+			updated := vcluster.DeepCopy()
+			updated.Finalizers = nil
+			updated.Labels = map[string]string{"team": "a"}
+
+			_, err := validator.ValidateUpdate(ctx, vcluster, updated)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should admit VirtualClusters being deleted", func() {
+			updated := vcluster.DeepCopy()
+			updated.Spec.Chart.Version = "v0.23.0"
+			now := metav1.Now()
+			updated.DeletionTimestamp = &now
+
+			_, err := validator.ValidateUpdate(ctx, vcluster, updated)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Context("When defaulting a VirtualCluster", func() {
+		var defaulter *VirtualClusterCustomDefaulter
+
+		BeforeEach(func() {
+			defaulter = &VirtualClusterCustomDefaulter{
+				DefaultValues: map[string]interface{}{
+					"controlPlane": map[string]interface{}{
+						"service": map[string]interface{}{"spec": map[string]interface{}{"type": "ClusterIP"}},
+					},
+					"telemetry": map[string]interface{}{"enabled": false},
+				},
+			}
+		})
+
+		It("Should write the resolved chart version", func() {
+			vcluster.Spec.Chart.Version = ""
+
+			Expect(defaulter.Default(ctx, vcluster)).To(Succeed())
+			Expect(vcluster.Spec.Chart.Version).To(Equal(corev1alpha1.DefaultChartVersion))
+		})
+
+		It("Should keep a set chart version", func() {
+			vcluster.Spec.Chart.Version = "v0.25.0"
+
+			Expect(defaulter.Default(ctx, vcluster)).To(Succeed())
+			Expect(vcluster.Spec.Chart.Version).To(Equal("v0.25.0"))
+		})
+
+		It("Should merge the default values beneath spec.values", func() {
+			Expect(defaulter.Default(ctx, vcluster)).To(Succeed())
+
+			values, err := vcluster.GetValues()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(values).To(Equal(map[string]interface{}{
+				"controlPlane": map[string]interface{}{
+					"service": map[string]interface{}{"spec": map[string]interface{}{"type": "NodePort"}},
+				},
+				"telemetry": map[string]interface{}{"enabled": false},
+			}))
+		})
+
+		It("Should leave VirtualClusters using valuesFrom to the controller", func() {
+			vcluster.Spec.ValuesFrom = []corev1alpha1.ValuesReference{{Kind: "ConfigMap", Name: "values"}}
+			raw := vcluster.Spec.Values.Raw
+
+			Expect(defaulter.Default(ctx, vcluster)).To(Succeed())
+			Expect(vcluster.Spec.Values.Raw).To(Equal(raw))
+		})
+
+		It("Should leave the chart version and values of VirtualClusters using a class", func() {
+			vcluster.Spec.Chart.Version = ""
+			vcluster.Spec.ClassRef = &corev1alpha1.ClassReference{Name: "small"}
+			raw := vcluster.Spec.Values.Raw
+
+			Expect(defaulter.Default(ctx, vcluster)).To(Succeed())
+			Expect(vcluster.Spec.Chart.Version).To(BeEmpty())
+			Expect(vcluster.Spec.Values.Raw).To(Equal(raw))
+			Expect(vcluster.Labels).NotTo(HaveKey(labelVersion))
+		})
+
+		It("Should add the standard labels", func() {
+			vcluster.Labels = map[string]string{labelName: "custom"}
+			vcluster.Spec.Chart.Version = "v0.25.0+build.1"
+
+			Expect(defaulter.Default(ctx, vcluster)).To(Succeed())
+			Expect(vcluster.Labels).To(Equal(map[string]string{
+				labelName:     "custom",
+				labelInstance: "test",
+				labelVersion:  "v0.25.0_build.1",
+			}))
+		})
+
+		It("Should not label a chart version constraint", func() {
+			vcluster.Labels = map[string]string{labelVersion: "v0.24.1"}
+			vcluster.Spec.Chart.Version = ">=0.24.0 <0.25.0"
+
+			Expect(defaulter.Default(ctx, vcluster)).To(Succeed())
+			Expect(vcluster.Labels).NotTo(HaveKey(labelVersion))
+		})
+
+		It("Should not change VirtualClusters being deleted", func() {
+			vcluster.Spec.Chart.Version = ""
+			now := metav1.Now()
+			vcluster.DeletionTimestamp = &now
+
+			Expect(defaulter.Default(ctx, vcluster)).To(Succeed())
+			Expect(vcluster.Spec.Chart.Version).To(BeEmpty())
+			Expect(vcluster.Labels).To(BeEmpty())
+		})
+	})
+})
+
+func setValues(vcluster *corev1alpha1.VirtualCluster, values map[string]interface{}) {
+	raw, err := json.Marshal(values)
+	Expect(err).NotTo(HaveOccurred())
+	vcluster.Spec.Values = &apiextensionsv1.JSON{Raw: raw}
+}